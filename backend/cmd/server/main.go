@@ -2,20 +2,80 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 
+	"refurnish/internal/alerts"
+	"refurnish/internal/appointments"
+	"refurnish/internal/cities"
 	"refurnish/internal/config"
+	"refurnish/internal/events"
 	"refurnish/internal/handlers"
+	"refurnish/internal/jobs"
 	authMiddleware "refurnish/internal/middleware"
+	"refurnish/internal/notify"
+	"refurnish/internal/ratings"
+	"refurnish/internal/realtime"
+	"refurnish/internal/subscribers"
+	"refurnish/internal/telegram"
+	"refurnish/internal/uploads"
+	"refurnish/internal/webhooks"
 )
 
 func main() {
-	_ = config.GetDB()
+	db := config.GetDB()
+
+	// Справочник городов
+	if err := cities.Seed(db); err != nil {
+		log.Printf("⚠️  Не удалось загрузить справочник городов: %v", err)
+	}
+
+	// Внешние каналы уведомлений подключаются, если заданы настройки
+	if sender, ok := notify.EmailFromEnv(); ok {
+		notify.Register(notify.Email, sender)
+	}
+	if sender, ok := notify.SMSFromEnv(); ok {
+		notify.Register(notify.SMS, sender)
+	}
+	var bot *telegram.Bot
+	if token := os.Getenv("TELEGRAM_BOT_TOKEN"); token != "" {
+		api := telegram.NewClient(token, os.Getenv("TELEGRAM_API_URL"))
+		notify.Register(notify.Telegram, telegram.NewSender(db, api))
+		bot = telegram.NewBot(db, api)
+	}
+
+	// Доменные события: реакции подписчиков и диспетчер outbox
+	subscribers.Register()
+	ctx := context.Background()
+	go events.Run(ctx, db)
+	go webhooks.Run(ctx, db)
+	// getUpdates может опрашивать только один экземпляр: на остальных TELEGRAM_POLLING=false
+	if bot != nil && os.Getenv("TELEGRAM_POLLING") != "false" {
+		go bot.Run(ctx)
+	}
+
+	// Фоновые задачи
+	go jobs.Every(ctx, "publish-scheduled", time.Minute, jobs.PublishScheduled(db))
+	go jobs.Every(ctx, "expire-projects", 15*time.Minute, jobs.ExpireProjects(db, config.ListingLifetime()))
+	go jobs.Every(ctx, "search-digests", time.Hour, alerts.SendDigests(db))
+	go jobs.Every(ctx, "notification-deliveries", 30*time.Second, notify.DeliverPending(db))
+	go jobs.Every(ctx, "recalculate-ratings", 24*time.Hour, ratings.RecalculateAll(db))
+	go jobs.Every(ctx, "reveal-reviews", time.Hour, ratings.RevealExpired(db, config.ReviewRevealWindow()))
+	go jobs.Every(ctx, "appointment-reminders", 5*time.Minute, appointments.SendReminders(db))
+
+	// Realtime: события между экземплярами через Postgres LISTEN/NOTIFY
+	if config.RealtimeBackend() == "postgres" {
+		bus := realtime.NewPostgresBus(db, config.DSN(), realtime.Default())
+		realtime.SetBus(bus)
+		go bus.Listen(ctx)
+	}
 
 	r := chi.NewRouter()
 
@@ -23,6 +83,8 @@ func main() {
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.RequestID)
+	// IP клиента для модерации отзывов: X-Forwarded-For принимается только от TRUSTED_PROXIES
+	r.Use(authMiddleware.RealIP(config.TrustedProxies()))
 
 	// ВАЖНО: CORS должен быть ПЕРВЫМ middleware!
 	r.Use(cors.Handler(cors.Options{
@@ -39,13 +101,35 @@ func main() {
 		r.Post("/api/auth/register", handlers.Register)
 		r.Post("/api/auth/login", handlers.Login)
 		r.Get("/api/masters", handlers.ListMasters)
+		r.Get("/api/masters/{id}", handlers.PublicMasterProfile)
+		r.Get("/api/masters/{id}/reviews", handlers.MasterReviews)
+		r.Get("/api/masters/{id}/portfolio", handlers.MasterPortfolio)
+		r.Get("/api/masters/{id}/availability", handlers.MasterAvailability)
 		r.Get("/api/projects/open", handlers.OpenProjects)
+		r.Get("/api/cities", handlers.ListCities)
+		r.Get("/api/categories", handlers.ListCategories)
+		r.Get("/api/categories/{slug}/spec", handlers.GetCategorySpec)
+		r.Get("/api/searches/unsubscribe", handlers.UnsubscribePreview)
+		r.Post("/api/searches/unsubscribe", handlers.UnsubscribeSavedSearch)
+	})
+
+	// Realtime: браузеры не шлют заголовки, поэтому вместо JWT в URL -
+	// одноразовый ?ticket= из POST /api/realtime/ticket
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.QueryTicket(func(ticket string) (string, string, error) {
+			return realtime.RedeemTicket(db, ticket)
+		}))
+
+		r.Get("/api/realtime/ws", handlers.RealtimeWebSocket)
+		r.Get("/api/realtime/events", handlers.RealtimeEvents)
 	})
 
 	// Protected routes
 	r.Group(func(r chi.Router) {
 		r.Use(authMiddleware.AuthMiddleware)
 
+		r.Post("/api/realtime/ticket", handlers.RealtimeTicket)
+
 		// Master routes
 		r.Route("/api/master", func(r chi.Router) {
 			r.Put("/profile", handlers.UpdateMasterProfile)
@@ -53,6 +137,41 @@ func main() {
 			r.Get("/responses", handlers.MyResponses)
 			r.Get("/profile", handlers.GetMasterProfile)
 			r.Get("/assigned-projects", handlers.MasterAssignedProjects)
+			r.Get("/invitations", handlers.MasterInvitations)
+			r.Post("/invitations/{id}/accept", handlers.AcceptInvitation)
+			r.Post("/invitations/{id}/decline", handlers.DeclineInvitation)
+			r.Get("/feed", handlers.MasterFeed)
+			r.Post("/feed/{projectId}/hide", handlers.HideFeedProject)
+			r.Delete("/feed/{projectId}/hide", handlers.UnhideFeedProject)
+			r.Get("/searches", handlers.MySavedSearches)
+			r.Post("/searches", handlers.CreateSavedSearch)
+			r.Put("/searches/{id}", handlers.UpdateSavedSearch)
+			r.Delete("/searches/{id}", handlers.DeleteSavedSearch)
+			r.Post("/reviews/{id}/reply", handlers.ReplyToReview)
+			r.Post("/reviews/{id}/dispute", handlers.DisputeReview)
+			r.Get("/portfolio", handlers.MyPortfolio)
+			r.Post("/portfolio", handlers.CreatePortfolioItem)
+			r.Put("/portfolio/{id}", handlers.UpdatePortfolioItem)
+			r.Delete("/portfolio/{id}", handlers.DeletePortfolioItem)
+			r.Post("/portfolio/{id}/photos", handlers.UploadPortfolioPhoto)
+			r.Delete("/portfolio/{id}/photos/{photoId}", handlers.DeletePortfolioPhoto)
+			r.Post("/portfolio/from-project/{projectId}", handlers.PortfolioFromProject)
+			r.Get("/verification", handlers.MyVerification)
+			r.Post("/verification/documents", handlers.UploadVerificationDocument)
+			r.Delete("/verification/documents/{id}", handlers.DeleteVerificationDocument)
+			r.Post("/verification/submit", handlers.SubmitVerification)
+			r.Get("/availability", handlers.MyAvailability)
+			r.Put("/availability", handlers.UpdateAvailability)
+			r.Post("/availability/time-off", handlers.CreateTimeOff)
+			r.Delete("/availability/time-off/{id}", handlers.DeleteTimeOff)
+			r.Post("/project/{id}/appointments", handlers.ProposeAppointment)
+			r.Get("/project/{id}/timeline", handlers.MasterProjectTimeline)
+			r.Get("/appointments", handlers.MyAppointments)
+			r.Get("/appointments/slots", handlers.MyVisitSlots)
+			r.Post("/appointments/{id}/reschedule", handlers.RescheduleAppointmentByMaster)
+			r.Post("/appointments/{id}/cancel", handlers.CancelAppointmentByMaster)
+			r.Post("/project/{id}/client-review", handlers.CreateClientReview)
+			r.Get("/clients/{id}/reviews", handlers.ClientReviews)
 		})
 
 		// Client routes
@@ -60,17 +179,105 @@ func main() {
 			r.Post("/project", handlers.CreateProject)
 			r.Get("/projects", handlers.MyProjects)
 			r.Put("/project/{id}", handlers.EditProject)
+			r.Post("/project/{id}/publish", handlers.PublishProject)
+			r.Post("/project/{id}/extend", handlers.ExtendProject)
+			r.Post("/project/{id}/invitations", handlers.InviteMasters)
+			r.Get("/project/{id}/invitations", handlers.ProjectInvitations)
+			r.Post("/project/{id}/questions/{questionId}/answer", handlers.AnswerQuestion)
+			r.Post("/project/{id}/photos", handlers.UploadProjectPhoto)
+			r.Delete("/project/{id}/photos/{photoId}", handlers.DeleteProjectPhoto)
 			r.Post("/project/{id}/assign", handlers.AssignMaster)
 			r.Get("/project/{id}/responses", handlers.ProjectResponses)
+			r.Get("/project/{id}/recommended-masters", handlers.RecommendedMasters)
+			r.Post("/project/{id}/complete", handlers.CompleteProject)
+			r.Post("/project/{id}/review", handlers.CreateReview)
+			r.Post("/project/{id}/review/photos", handlers.UploadReviewPhoto)
+			r.Put("/project/{id}/portfolio-consent", handlers.SetPortfolioConsent)
+			r.Get("/project/{id}/appointments", handlers.ProjectAppointments)
+			r.Get("/project/{id}/timeline", handlers.ClientProjectTimeline)
+			r.Post("/appointments/{id}/confirm", handlers.ConfirmAppointment)
+			r.Post("/appointments/{id}/reschedule", handlers.RescheduleAppointmentByClient)
+			r.Post("/appointments/{id}/cancel", handlers.CancelAppointmentByClient)
 			r.Get("/profile", handlers.GetClientProfile)
+			r.Post("/reviews/{id}/dispute", handlers.DisputeClientReview)
+		})
+
+		// Admin routes
+		r.Route("/api/admin", func(r chi.Router) {
+			r.Use(authMiddleware.RequireRole("admin"))
+
+			r.Get("/categories", handlers.ListCategories)
+			r.Post("/categories", handlers.CreateCategory)
+			r.Put("/categories/{id}", handlers.UpdateCategory)
+			r.Delete("/categories/{id}", handlers.DeleteCategory)
+
+			r.Get("/notifications/deliveries", handlers.NotificationDeliveries)
+			r.Post("/notifications/deliveries/{id}/retry", handlers.RetryNotificationDelivery)
+
+			r.Get("/reviews", handlers.ModerationQueue)
+			r.Put("/reviews/{id}", handlers.EditReview)
+			r.Post("/reviews/{id}/approve", handlers.ApproveReview)
+			r.Post("/reviews/{id}/reject", handlers.RejectReview)
+			r.Get("/reviews/{id}/log", handlers.ReviewModerationLog)
+			r.Get("/disputes", handlers.ReviewDisputes)
+			r.Post("/disputes/{id}/resolve", handlers.ResolveDispute)
+			r.Get("/client-reviews", handlers.ClientReviewModerationQueue)
+			r.Put("/client-reviews/{id}", handlers.EditClientReview)
+			r.Post("/client-reviews/{id}/approve", handlers.ApproveClientReview)
+			r.Post("/client-reviews/{id}/reject", handlers.RejectClientReview)
+			r.Get("/client-reviews/{id}/log", handlers.ClientReviewModerationLog)
+
+			r.Get("/verifications", handlers.VerificationQueue)
+			r.Get("/verifications/{id}/documents/{documentId}", handlers.VerificationDocumentFile)
+			r.Post("/verifications/{id}/approve", handlers.ApproveVerification)
+			r.Post("/verifications/{id}/reject", handlers.RejectVerification)
 		})
 
+		// Conversations
+		r.Route("/api/conversations", func(r chi.Router) {
+			r.Post("/", handlers.StartConversation)
+			r.Get("/", handlers.MyConversations)
+			r.Get("/unread", handlers.UnreadMessagesCount)
+			r.Get("/{id}/messages", handlers.ConversationMessages)
+			r.Post("/{id}/messages", handlers.SendMessage)
+			r.Post("/{id}/read", handlers.MarkConversationRead)
+			r.Post("/{id}/attachments", handlers.UploadMessageAttachment)
+			r.Get("/{id}/attachments/{attachmentId}", handlers.ConversationAttachment)
+		})
+
+		// Webhooks
+		r.Route("/api/webhooks", func(r chi.Router) {
+			r.Get("/", handlers.MyWebhooks)
+			r.Post("/", handlers.CreateWebhook)
+			r.Put("/{id}", handlers.UpdateWebhook)
+			r.Delete("/{id}", handlers.DeleteWebhook)
+			r.Get("/{id}/deliveries", handlers.WebhookDeliveries)
+			r.Post("/{id}/deliveries/{deliveryId}/redeliver", handlers.RedeliverWebhook)
+		})
+
+		// Telegram
+		r.Get("/api/telegram", handlers.TelegramStatus)
+		r.Post("/api/telegram/link-code", handlers.CreateTelegramLinkCode)
+		r.Delete("/api/telegram", handlers.UnlinkTelegram)
+
+		// Notifications
+		r.Get("/api/notifications", handlers.MyNotifications)
+		r.Post("/api/notifications/read-all", handlers.MarkAllNotificationsRead)
+		r.Get("/api/notifications/preferences", handlers.NotificationPreferences)
+		r.Put("/api/notifications/preferences", handlers.UpdateNotificationPreferences)
+		r.Post("/api/notifications/{id}/read", handlers.MarkNotificationRead)
+		r.Post("/api/notifications/{id}/unread", handlers.MarkNotificationUnread)
+
 		// Common routes
 		r.Route("/api/project", func(r chi.Router) {
 			r.Get("/{id}", handlers.GetProjectDetails)
+			r.Post("/{id}/questions", handlers.AskQuestion)
 		})
 	})
 
+	// Загруженные файлы
+	r.Handle(uploads.URLPrefix+"*", uploads.Handler())
+
 	// Health check
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -0,0 +1,14 @@
+CREATE TABLE cities (
+                        id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                        name TEXT NOT NULL UNIQUE,
+                        region TEXT,
+                        timezone TEXT NOT NULL DEFAULT 'Europe/Moscow',
+                        latitude DOUBLE PRECISION,
+                        longitude DOUBLE PRECISION,
+                        population INTEGER NOT NULL DEFAULT 0,
+                        aliases TEXT[] NOT NULL DEFAULT '{}',
+                        search_keys TEXT[] NOT NULL DEFAULT '{}',
+                        created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_cities_search_keys ON cities USING GIN (search_keys);
@@ -0,0 +1,78 @@
+-- Справочник категорий мебели / специализаций мастеров
+CREATE TABLE categories (
+                            id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                            slug TEXT NOT NULL UNIQUE,
+                            parent_id UUID,
+                            name_ru TEXT NOT NULL,
+                            name_en TEXT NOT NULL,
+                            sort_order INTEGER NOT NULL DEFAULT 0,
+                            is_active BOOLEAN NOT NULL DEFAULT TRUE,
+                            created_at TIMESTAMP NOT NULL DEFAULT now(),
+                            updated_at TIMESTAMP NOT NULL DEFAULT now(),
+
+                            CONSTRAINT fk_categories_parent
+                                FOREIGN KEY (parent_id)
+                                    REFERENCES categories(id)
+                                    ON DELETE RESTRICT
+);
+
+CREATE INDEX idx_categories_parent ON categories(parent_id);
+
+INSERT INTO categories (slug, name_ru, name_en, sort_order) VALUES
+    ('kitchen', 'Кухня', 'Kitchen', 10),
+    ('cabinet', 'Корпусная мебель', 'Cabinet furniture', 20),
+    ('tables-chairs', 'Столы и стулья', 'Tables and chairs', 30),
+    ('upholstery', 'Мягкая мебель', 'Upholstered furniture', 40),
+    ('beds', 'Кровать', 'Beds', 50),
+    ('antique', 'Антикварная мебель', 'Antique furniture', 60),
+    ('other', 'Другое', 'Other', 1000);
+
+INSERT INTO categories (slug, parent_id, name_ru, name_en, sort_order)
+SELECT v.slug, p.id, v.name_ru, v.name_en, v.sort_order
+FROM (VALUES
+    ('wardrobes', 'cabinet', 'Шкаф', 'Wardrobes', 10),
+    ('dressers', 'cabinet', 'Комод', 'Dressers', 20),
+    ('nightstands', 'cabinet', 'Тумба', 'Nightstands', 30),
+    ('shelves', 'cabinet', 'Полка', 'Shelves', 40),
+    ('racks', 'cabinet', 'Стеллаж', 'Racks', 50),
+    ('tables', 'tables-chairs', 'Стол', 'Tables', 10),
+    ('chairs', 'tables-chairs', 'Стул', 'Chairs', 20),
+    ('sofas', 'upholstery', 'Диван', 'Sofas', 10),
+    ('armchairs', 'upholstery', 'Кресло', 'Armchairs', 20)
+) AS v(slug, parent_slug, name_ru, name_en, sort_order)
+JOIN categories p ON p.slug = v.parent_slug;
+
+INSERT INTO categories (slug, parent_id, name_ru, name_en, sort_order)
+SELECT v.slug, p.id, v.name_ru, v.name_en, v.sort_order
+FROM (VALUES
+    ('sofas-leather', 'sofas', 'Кожаный диван', 'Leather sofas', 10),
+    ('sofas-fabric', 'sofas', 'Тканевый диван', 'Fabric sofas', 20)
+) AS v(slug, parent_slug, name_ru, name_en, sort_order)
+JOIN categories p ON p.slug = v.parent_slug;
+
+-- Роль администратора для управления справочниками
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('client', 'master', 'admin'));
+
+-- Перенос свободного текста в категории
+ALTER TABLE projects ADD COLUMN category_id UUID REFERENCES categories(id) ON DELETE RESTRICT;
+CREATE INDEX idx_projects_category ON projects(category_id);
+
+UPDATE projects p
+SET category_id = c.id,
+    furniture_type = c.slug
+FROM categories c
+WHERE lower(trim(p.furniture_type)) IN (lower(c.name_ru), lower(c.name_en), c.slug);
+
+UPDATE projects
+SET category_id = (SELECT id FROM categories WHERE slug = 'other'),
+    furniture_type = 'other'
+WHERE category_id IS NULL;
+
+UPDATE masters m
+SET specializations = ARRAY(
+    SELECT DISTINCT c.slug
+    FROM unnest(m.specializations) AS s
+    JOIN categories c ON lower(trim(s)) IN (lower(c.name_ru), lower(c.name_en), c.slug)
+)
+WHERE m.specializations IS NOT NULL;
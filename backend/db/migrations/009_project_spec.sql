@@ -0,0 +1,44 @@
+-- Структурированная спецификация мебели
+ALTER TABLE categories ADD COLUMN spec_schema JSONB;
+ALTER TABLE projects ADD COLUMN spec JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_projects_spec ON projects USING GIN (spec);
+
+-- Общие поля для всех корневых категорий
+UPDATE categories SET spec_schema = '{
+  "fields": [
+    {"key": "items_count", "type": "integer", "labelRu": "Количество предметов", "labelEn": "Number of items", "required": true, "min": 1, "max": 100},
+    {"key": "width", "type": "number", "labelRu": "Ширина", "labelEn": "Width", "unit": "cm", "min": 1, "max": 2000},
+    {"key": "height", "type": "number", "labelRu": "Высота", "labelEn": "Height", "unit": "cm", "min": 1, "max": 500},
+    {"key": "depth", "type": "number", "labelRu": "Глубина", "labelEn": "Depth", "unit": "cm", "min": 1, "max": 500},
+    {"key": "material", "type": "enum", "labelRu": "Материал", "labelEn": "Material",
+     "options": ["solid_wood", "veneer", "chipboard", "mdf", "metal", "glass", "plastic", "other"]},
+    {"key": "finish", "type": "enum", "labelRu": "Покрытие", "labelEn": "Finish",
+     "options": ["lacquer", "paint", "oil", "wax", "laminate", "none", "other"]},
+    {"key": "damage_type", "type": "enum", "labelRu": "Тип повреждения", "labelEn": "Damage type", "required": true,
+     "options": ["scratches", "cracks", "broken_parts", "loose_joints", "water_damage", "worn_finish", "upholstery_wear", "mechanism", "other"]},
+    {"key": "damage_notes", "type": "string", "labelRu": "Описание повреждений", "labelEn": "Damage notes"}
+  ]
+}'
+WHERE parent_id IS NULL;
+
+-- Мягкая мебель: обивка вместо покрытия
+UPDATE categories SET spec_schema = '{
+  "fields": [
+    {"key": "upholstery", "type": "enum", "labelRu": "Обивка", "labelEn": "Upholstery",
+     "options": ["leather", "eco_leather", "fabric", "velour", "other"]},
+    {"key": "reupholster", "type": "boolean", "labelRu": "Нужна перетяжка", "labelEn": "Needs reupholstering"}
+  ]
+}'
+WHERE slug = 'upholstery';
+
+UPDATE categories SET spec_schema = '{
+  "fields": [
+    {"key": "seats", "type": "integer", "labelRu": "Количество мест", "labelEn": "Seats", "min": 1, "max": 12},
+    {"key": "has_mechanism", "type": "boolean", "labelRu": "Раскладной механизм", "labelEn": "Folding mechanism"}
+  ]
+}'
+WHERE slug = 'sofas';
+
+-- Существующим проектам проставляем минимальную спецификацию
+UPDATE projects SET spec = '{"items_count": 1}' WHERE spec = '{}';
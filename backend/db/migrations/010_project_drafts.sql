@@ -0,0 +1,23 @@
+-- Черновики и отложенная публикация
+ALTER TABLE projects ALTER COLUMN furniture_type DROP NOT NULL;
+ALTER TABLE projects ADD COLUMN publish_at TIMESTAMP;
+ALTER TABLE projects ADD COLUMN published_at TIMESTAMP;
+
+UPDATE projects SET published_at = created_at WHERE status = 'published';
+
+CREATE INDEX idx_projects_scheduled ON projects(publish_at) WHERE status = 'scheduled';
+
+CREATE TABLE project_photos (
+                                id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                project_id UUID NOT NULL,
+                                url TEXT NOT NULL,
+                                sort_order INTEGER NOT NULL DEFAULT 0,
+                                created_at TIMESTAMP NOT NULL DEFAULT now(),
+
+                                CONSTRAINT fk_project_photos_project
+                                    FOREIGN KEY (project_id)
+                                        REFERENCES projects(id)
+                                        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_project_photos_project ON project_photos(project_id);
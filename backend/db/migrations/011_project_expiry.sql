@@ -0,0 +1,23 @@
+-- Истечение срока публикации
+ALTER TABLE projects ADD COLUMN expired_at TIMESTAMP;
+
+CREATE INDEX idx_projects_published ON projects(published_at) WHERE status = 'published';
+
+-- Уведомления пользователей (in-app)
+CREATE TABLE notifications (
+                               id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                               user_id UUID NOT NULL,
+                               type TEXT NOT NULL,
+                               title TEXT NOT NULL,
+                               body TEXT,
+                               data JSONB NOT NULL DEFAULT '{}',
+                               read_at TIMESTAMP,
+                               created_at TIMESTAMP NOT NULL DEFAULT now(),
+
+                               CONSTRAINT fk_notifications_user
+                                   FOREIGN KEY (user_id)
+                                       REFERENCES users(id)
+                                       ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at DESC);
@@ -0,0 +1,29 @@
+-- Видимость проекта и приглашения мастеров
+ALTER TABLE projects ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public'
+    CHECK (visibility IN ('public', 'invite_only', 'link_only'));
+
+CREATE TABLE invitations (
+                             id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                             project_id UUID NOT NULL,
+                             master_id UUID NOT NULL,
+                             status TEXT NOT NULL DEFAULT 'pending'
+                                 CHECK (status IN ('pending', 'accepted', 'declined')),
+                             message TEXT,
+                             decline_reason TEXT,
+                             responded_at TIMESTAMP,
+                             created_at TIMESTAMP NOT NULL DEFAULT now(),
+
+                             CONSTRAINT fk_invitations_project
+                                 FOREIGN KEY (project_id)
+                                     REFERENCES projects(id)
+                                     ON DELETE CASCADE,
+
+                             CONSTRAINT fk_invitations_master
+                                 FOREIGN KEY (master_id)
+                                     REFERENCES masters(id)
+                                     ON DELETE CASCADE,
+
+                             CONSTRAINT uq_invitations_project_master UNIQUE (project_id, master_id)
+);
+
+CREATE INDEX idx_invitations_master ON invitations(master_id, created_at DESC);
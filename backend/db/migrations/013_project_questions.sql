@@ -0,0 +1,23 @@
+-- Публичные вопросы и ответы по проекту
+CREATE TABLE project_questions (
+                                   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                   project_id UUID NOT NULL,
+                                   master_id UUID NOT NULL,
+                                   question TEXT NOT NULL,
+                                   answer TEXT,
+                                   contacts_masked BOOLEAN NOT NULL DEFAULT FALSE,
+                                   answered_at TIMESTAMP,
+                                   created_at TIMESTAMP NOT NULL DEFAULT now(),
+
+                                   CONSTRAINT fk_project_questions_project
+                                       FOREIGN KEY (project_id)
+                                           REFERENCES projects(id)
+                                           ON DELETE CASCADE,
+
+                                   CONSTRAINT fk_project_questions_master
+                                       FOREIGN KEY (master_id)
+                                           REFERENCES masters(id)
+                                           ON DELETE CASCADE
+);
+
+CREATE INDEX idx_project_questions_project ON project_questions(project_id, created_at);
@@ -0,0 +1,37 @@
+-- Сохранённые поиски мастеров и оповещения о новых проектах
+CREATE TABLE saved_searches (
+                                id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                master_id UUID NOT NULL,
+                                name TEXT NOT NULL,
+                                city TEXT,
+                                category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+                                budget_min INTEGER,
+                                budget_max INTEGER,
+                                keywords TEXT,
+                                radius_km INTEGER NOT NULL DEFAULT 0,
+                                delivery TEXT NOT NULL DEFAULT 'instant' CHECK (delivery IN ('instant', 'daily')),
+                                unsubscribe_token TEXT NOT NULL UNIQUE,
+                                is_active BOOLEAN NOT NULL DEFAULT TRUE,
+                                last_digest_at TIMESTAMP,
+                                created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                updated_at TIMESTAMP NOT NULL DEFAULT now(),
+
+                                CONSTRAINT fk_saved_searches_master
+                                    FOREIGN KEY (master_id)
+                                        REFERENCES masters(id)
+                                        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_saved_searches_active ON saved_searches(is_active);
+
+-- Найденные совпадения: защищают от повторных оповещений и копятся для дайджеста
+CREATE TABLE saved_search_matches (
+                                      search_id UUID NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+                                      project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+                                      sent_at TIMESTAMP,
+                                      created_at TIMESTAMP NOT NULL DEFAULT now(),
+
+                                      PRIMARY KEY (search_id, project_id)
+);
+
+CREATE INDEX idx_saved_search_matches_unsent ON saved_search_matches(search_id) WHERE sent_at IS NULL;
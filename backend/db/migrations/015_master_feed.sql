@@ -0,0 +1,11 @@
+-- Персональная лента мастера
+ALTER TABLE masters ADD COLUMN service_radius_km INTEGER NOT NULL DEFAULT 0;
+
+-- Проекты, скрытые мастером из ленты
+CREATE TABLE project_hides (
+                               master_id UUID NOT NULL REFERENCES masters(id) ON DELETE CASCADE,
+                               project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+                               created_at TIMESTAMP NOT NULL DEFAULT now(),
+
+                               PRIMARY KEY (master_id, project_id)
+);
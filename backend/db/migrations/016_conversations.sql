@@ -0,0 +1,41 @@
+-- Переписка клиента и мастера по проекту
+CREATE TABLE conversations (
+                               id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                               project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+                               client_id UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+                               master_id UUID NOT NULL REFERENCES masters(id) ON DELETE CASCADE,
+                               last_message_at TIMESTAMP,
+                               created_at TIMESTAMP NOT NULL DEFAULT now(),
+
+                               CONSTRAINT uq_conversations_project_master UNIQUE (project_id, master_id)
+);
+
+CREATE INDEX idx_conversations_client ON conversations(client_id, last_message_at DESC);
+CREATE INDEX idx_conversations_master ON conversations(master_id, last_message_at DESC);
+
+CREATE TABLE messages (
+                          id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                          conversation_id UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+                          sender_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                          body TEXT NOT NULL DEFAULT '',
+                          read_at TIMESTAMP,
+                          created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_messages_conversation ON messages(conversation_id, created_at DESC);
+CREATE INDEX idx_messages_unread ON messages(conversation_id) WHERE read_at IS NULL;
+
+-- Вложения загружаются до отправки сообщения и привязываются к нему при отправке
+CREATE TABLE message_attachments (
+                                     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                     conversation_id UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+                                     message_id UUID REFERENCES messages(id) ON DELETE CASCADE,
+                                     uploader_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                     url TEXT NOT NULL,
+                                     file_name TEXT,
+                                     content_type TEXT,
+                                     size BIGINT NOT NULL DEFAULT 0,
+                                     created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_message_attachments_message ON message_attachments(message_id);
@@ -0,0 +1,34 @@
+-- Язык уведомлений пользователя
+ALTER TABLE users ADD COLUMN language TEXT NOT NULL DEFAULT 'ru' CHECK (language IN ('ru', 'en'));
+
+-- Настройки каналов: запись есть только если пользователь изменил значение по умолчанию
+CREATE TABLE notification_preferences (
+                                          user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                          type TEXT NOT NULL,
+                                          channel TEXT NOT NULL CHECK (channel IN ('in_app', 'email', 'sms', 'telegram')),
+                                          enabled BOOLEAN NOT NULL,
+                                          updated_at TIMESTAMP NOT NULL DEFAULT now(),
+
+                                          PRIMARY KEY (user_id, type, channel)
+);
+
+-- Журнал доставки во внешние каналы с повторами
+CREATE TABLE notification_deliveries (
+                                         id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                         notification_id UUID REFERENCES notifications(id) ON DELETE SET NULL,
+                                         user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                         type TEXT NOT NULL,
+                                         channel TEXT NOT NULL,
+                                         address TEXT NOT NULL,
+                                         title TEXT NOT NULL,
+                                         body TEXT NOT NULL DEFAULT '',
+                                         status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+                                         attempts INT NOT NULL DEFAULT 0,
+                                         last_error TEXT,
+                                         next_attempt_at TIMESTAMP NOT NULL DEFAULT now(),
+                                         sent_at TIMESTAMP,
+                                         created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_notification_deliveries_pending ON notification_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_notification_deliveries_user ON notification_deliveries(user_id, created_at DESC);
@@ -0,0 +1,26 @@
+-- Доменные события: пишутся в одной транзакции с изменением состояния
+CREATE TABLE outbox (
+                        id BIGSERIAL PRIMARY KEY,
+                        event_type TEXT NOT NULL,
+                        aggregate_id TEXT NOT NULL,
+                        payload JSONB NOT NULL DEFAULT '{}',
+                        attempts INT NOT NULL DEFAULT 0,
+                        last_error TEXT,
+                        next_attempt_at TIMESTAMP NOT NULL DEFAULT now(),
+                        locked_until TIMESTAMP,
+                        dispatched_at TIMESTAMP,
+                        failed_at TIMESTAMP,
+                        created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_outbox_pending ON outbox(next_attempt_at, id)
+    WHERE dispatched_at IS NULL AND failed_at IS NULL;
+
+-- Какие подписчики уже обработали событие: при повторе они пропускаются
+CREATE TABLE outbox_consumers (
+                                  event_id BIGINT NOT NULL REFERENCES outbox(id) ON DELETE CASCADE,
+                                  subscriber TEXT NOT NULL,
+                                  processed_at TIMESTAMP NOT NULL DEFAULT now(),
+
+                                  PRIMARY KEY (event_id, subscriber)
+);
@@ -0,0 +1,38 @@
+-- Исходящие вебхуки партнёров
+CREATE TABLE webhooks (
+                          id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                          user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                          url TEXT NOT NULL,
+                          event_types JSONB NOT NULL DEFAULT '[]',
+                          secret TEXT NOT NULL,
+                          is_active BOOLEAN NOT NULL DEFAULT true,
+                          consecutive_failures INT NOT NULL DEFAULT 0,
+                          disabled_at TIMESTAMP,
+                          disabled_reason TEXT,
+                          created_at TIMESTAMP NOT NULL DEFAULT now(),
+                          updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhooks_user ON webhooks(user_id) WHERE is_active;
+
+CREATE TABLE webhook_deliveries (
+                                    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+                                    event_id BIGINT,
+                                    redelivery_of UUID REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+                                    event_type TEXT NOT NULL,
+                                    payload JSONB NOT NULL,
+                                    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'success', 'failed')),
+                                    attempts INT NOT NULL DEFAULT 0,
+                                    response_status INT,
+                                    response_body TEXT,
+                                    last_error TEXT,
+                                    next_attempt_at TIMESTAMP NOT NULL DEFAULT now(),
+                                    delivered_at TIMESTAMP,
+                                    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Outbox доставляет события "хотя бы один раз": повтор не создаёт вторую доставку
+CREATE UNIQUE INDEX uq_webhook_deliveries_event ON webhook_deliveries(webhook_id, event_id) WHERE redelivery_of IS NULL;
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at DESC);
@@ -0,0 +1,21 @@
+-- Данные уведомления для внешних каналов (кнопки в Telegram и т.п.)
+ALTER TABLE notification_deliveries ADD COLUMN data JSONB NOT NULL DEFAULT '{}';
+
+-- Привязка аккаунта к чату Telegram
+CREATE TABLE telegram_links (
+                                user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+                                chat_id BIGINT NOT NULL UNIQUE,
+                                username TEXT,
+                                linked_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Одноразовые коды привязки: пользователь отправляет боту /start <код>
+CREATE TABLE telegram_link_codes (
+                                     code TEXT PRIMARY KEY,
+                                     user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                     expires_at TIMESTAMP NOT NULL,
+                                     used_at TIMESTAMP,
+                                     created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_telegram_link_codes_user ON telegram_link_codes(user_id);
@@ -0,0 +1,34 @@
+-- Завершение работ
+ALTER TABLE projects ADD COLUMN completed_at TIMESTAMP;
+
+-- Число отзывов рядом с рейтингом: без него рейтинг не с чем сравнивать
+ALTER TABLE masters ADD COLUMN reviews_count INT NOT NULL DEFAULT 0;
+
+-- Отзывы клиентов о мастерах: один отзыв на завершённый проект
+CREATE TABLE reviews (
+                         id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                         project_id UUID NOT NULL UNIQUE REFERENCES projects(id) ON DELETE CASCADE,
+                         client_id UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+                         master_id UUID NOT NULL REFERENCES masters(id) ON DELETE CASCADE,
+                         rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+                         quality SMALLINT CHECK (quality BETWEEN 1 AND 5),
+                         punctuality SMALLINT CHECK (punctuality BETWEEN 1 AND 5),
+                         communication SMALLINT CHECK (communication BETWEEN 1 AND 5),
+                         text TEXT NOT NULL DEFAULT '',
+                         reply TEXT,
+                         replied_at TIMESTAMP,
+                         created_at TIMESTAMP NOT NULL DEFAULT now(),
+                         updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_reviews_master ON reviews(master_id, created_at DESC);
+
+CREATE TABLE review_photos (
+                               id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                               review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+                               url TEXT NOT NULL,
+                               sort_order INT NOT NULL DEFAULT 0,
+                               created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_review_photos_review ON review_photos(review_id, sort_order);
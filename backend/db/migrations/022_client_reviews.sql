@@ -0,0 +1,24 @@
+-- Отзывы мастеров о клиентах
+CREATE TABLE client_reviews (
+                                id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                project_id UUID NOT NULL UNIQUE REFERENCES projects(id) ON DELETE CASCADE,
+                                master_id UUID NOT NULL REFERENCES masters(id) ON DELETE CASCADE,
+                                client_id UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+                                rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+                                payment SMALLINT CHECK (payment BETWEEN 1 AND 5),
+                                communication SMALLINT CHECK (communication BETWEEN 1 AND 5),
+                                accuracy SMALLINT CHECK (accuracy BETWEEN 1 AND 5),
+                                text TEXT NOT NULL DEFAULT '',
+                                published_at TIMESTAMP,
+                                created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_client_reviews_client ON client_reviews(client_id, created_at DESC) WHERE published_at IS NOT NULL;
+
+-- Отзывы открываются одновременно, когда оба оставлены, или по истечении срока
+ALTER TABLE reviews ADD COLUMN published_at TIMESTAMP;
+UPDATE reviews SET published_at = created_at;
+
+-- Репутация клиента - байесовское среднее отзывов мастеров
+ALTER TABLE clients ADD COLUMN reputation DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE clients ADD COLUMN reviews_count INT NOT NULL DEFAULT 0;
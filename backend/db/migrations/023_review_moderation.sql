@@ -0,0 +1,37 @@
+-- Модерация отзывов: отзывы с автоматическими флагами ждут решения модератора
+ALTER TABLE reviews ADD COLUMN moderation_status VARCHAR(20) NOT NULL DEFAULT 'approved'; -- pending, approved, rejected
+ALTER TABLE reviews ADD COLUMN flags JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE reviews ADD COLUMN ip VARCHAR(45);
+
+CREATE INDEX idx_reviews_pending ON reviews(created_at) WHERE moderation_status = 'pending';
+CREATE INDEX idx_reviews_master_ip ON reviews(master_id, ip);
+
+-- Оспаривание отзыва мастером
+CREATE TABLE review_disputes (
+                                 id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                 review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+                                 master_id UUID NOT NULL REFERENCES masters(id) ON DELETE CASCADE,
+                                 reason TEXT NOT NULL,
+                                 status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, accepted, rejected
+                                 resolution TEXT,
+                                 resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+                                 resolved_at TIMESTAMP,
+                                 created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Не больше одного открытого спора на отзыв
+CREATE UNIQUE INDEX idx_review_disputes_open ON review_disputes(review_id) WHERE status = 'open';
+
+-- Журнал решений модерации. Отзыв может быть удалён вместе с проектом,
+-- журнал остаётся
+CREATE TABLE moderation_actions (
+                                    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                    review_id UUID REFERENCES reviews(id) ON DELETE SET NULL,
+                                    actor_id UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL - автоматическая проверка
+                                    action VARCHAR(30) NOT NULL,
+                                    reason TEXT NOT NULL DEFAULT '',
+                                    details JSONB NOT NULL DEFAULT '{}',
+                                    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_moderation_actions_review ON moderation_actions(review_id, created_at);
@@ -0,0 +1,31 @@
+-- Согласие клиента на публикацию проекта в портфолио мастера
+ALTER TABLE projects ADD COLUMN portfolio_consent BOOLEAN NOT NULL DEFAULT false;
+
+-- Портфолио мастера
+CREATE TABLE portfolio_items (
+                                 id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                 master_id UUID NOT NULL REFERENCES masters(id) ON DELETE CASCADE,
+                                 title VARCHAR(200) NOT NULL,
+                                 category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+                                 description TEXT NOT NULL DEFAULT '',
+                                 price INT,
+                                 completed_on DATE,
+                                 project_id UUID UNIQUE REFERENCES projects(id) ON DELETE SET NULL, -- работа создана из проекта на площадке
+                                 sort_order INT NOT NULL DEFAULT 0,
+                                 created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                 updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_portfolio_items_master ON portfolio_items(master_id, sort_order, completed_on DESC);
+
+-- Фотографии "до" и "после"
+CREATE TABLE portfolio_photos (
+                                  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                  item_id UUID NOT NULL REFERENCES portfolio_items(id) ON DELETE CASCADE,
+                                  url TEXT NOT NULL,
+                                  kind VARCHAR(10) NOT NULL CHECK (kind IN ('before', 'after')),
+                                  sort_order INT NOT NULL DEFAULT 0,
+                                  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_portfolio_photos_item ON portfolio_photos(item_id, kind, sort_order);
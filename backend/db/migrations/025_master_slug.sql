@@ -0,0 +1,3 @@
+-- Короткий адрес публичного профиля мастера: /masters/ivan-petrov.
+-- Заполняется при сохранении профиля, до этого профиль доступен по id
+ALTER TABLE masters ADD COLUMN slug VARCHAR(60) UNIQUE;
@@ -0,0 +1,7 @@
+-- Полнотекстовый поиск мастеров по имени и описанию.
+-- Выражение должно совпадать с masterSearchVector в internal/recommend/search.go
+CREATE INDEX idx_masters_search ON masters
+    USING GIN (to_tsvector('russian', coalesce(name, '') || ' ' || coalesce(description, '')));
+
+CREATE INDEX idx_masters_rating ON masters(rating DESC, reviews_count DESC);
+CREATE INDEX idx_responses_master_created ON responses(master_id, created_at DESC);
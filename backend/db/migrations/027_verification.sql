@@ -0,0 +1,40 @@
+-- Проверка личности и статуса мастера
+ALTER TABLE masters ADD COLUMN verified_at TIMESTAMP;
+ALTER TABLE masters ADD COLUMN inn VARCHAR(12);
+ALTER TABLE masters ADD COLUMN ogrnip VARCHAR(15);
+
+CREATE INDEX idx_masters_verified ON masters(verified_at) WHERE verified_at IS NOT NULL;
+
+-- Заявки на проверку: draft - мастер загружает документы, pending - ждёт
+-- модератора, approved / rejected - решение принято
+CREATE TABLE verification_requests (
+                                       id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                       master_id UUID NOT NULL REFERENCES masters(id) ON DELETE CASCADE,
+                                       status VARCHAR(20) NOT NULL DEFAULT 'draft',
+                                       inn VARCHAR(12),
+                                       ogrnip VARCHAR(15),
+                                       reason TEXT, -- причина отказа
+                                       reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+                                       submitted_at TIMESTAMP,
+                                       reviewed_at TIMESTAMP,
+                                       created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Одна незавершённая заявка на мастера
+CREATE UNIQUE INDEX idx_verification_requests_active ON verification_requests(master_id)
+    WHERE status IN ('draft', 'pending');
+CREATE INDEX idx_verification_requests_pending ON verification_requests(submitted_at) WHERE status = 'pending';
+
+-- Документы хранятся вне публичного каталога загрузок (PRIVATE_UPLOAD_DIR)
+CREATE TABLE verification_documents (
+                                        id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                        request_id UUID NOT NULL REFERENCES verification_requests(id) ON DELETE CASCADE,
+                                        kind VARCHAR(30) NOT NULL, -- passport_selfie, inn_certificate, self_employed_certificate, ogrnip_certificate
+                                        file_name TEXT NOT NULL,
+                                        original_name TEXT NOT NULL DEFAULT '',
+                                        content_type VARCHAR(100) NOT NULL,
+                                        size BIGINT NOT NULL DEFAULT 0,
+                                        created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_verification_documents_request ON verification_documents(request_id);
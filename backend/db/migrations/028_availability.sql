@@ -0,0 +1,26 @@
+-- Сколько проектов мастер готов вести одновременно
+ALTER TABLE masters ADD COLUMN max_concurrent_projects INT NOT NULL DEFAULT 3;
+
+-- Рабочие часы по дням недели (1 - понедельник ... 7 - воскресенье),
+-- время в минутах от полуночи по часовому поясу города мастера.
+-- Нет строк - график по умолчанию: будни 09:00-18:00
+CREATE TABLE master_working_hours (
+                                      master_id UUID NOT NULL REFERENCES masters(id) ON DELETE CASCADE,
+                                      weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 1 AND 7),
+                                      start_minute SMALLINT NOT NULL CHECK (start_minute BETWEEN 0 AND 1440),
+                                      end_minute SMALLINT NOT NULL CHECK (end_minute BETWEEN 0 AND 1440 AND end_minute > start_minute),
+                                      PRIMARY KEY (master_id, weekday)
+);
+
+-- Выходные и отпуска, даты включительно
+CREATE TABLE master_time_off (
+                                 id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                 master_id UUID NOT NULL REFERENCES masters(id) ON DELETE CASCADE,
+                                 kind VARCHAR(20) NOT NULL DEFAULT 'day_off', -- day_off, vacation
+                                 starts_on DATE NOT NULL,
+                                 ends_on DATE NOT NULL CHECK (ends_on >= starts_on),
+                                 note TEXT NOT NULL DEFAULT '',
+                                 created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_master_time_off_master ON master_time_off(master_id, ends_on);
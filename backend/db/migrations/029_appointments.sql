@@ -0,0 +1,52 @@
+-- Когда проекту назначили мастера - для ленты событий проекта
+ALTER TABLE projects ADD COLUMN assigned_at TIMESTAMP;
+UPDATE projects SET assigned_at = updated_at
+WHERE assigned_master IS NOT NULL AND status IN ('assigned', 'completed');
+
+-- Выезды мастера на замер. proposed - мастер предложил варианты времени,
+-- confirmed - клиент выбрал один из них, cancelled - отменён одной из сторон
+CREATE TABLE appointments (
+                              id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                              project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+                              master_id UUID NOT NULL REFERENCES masters(id) ON DELETE CASCADE,
+                              status VARCHAR(20) NOT NULL DEFAULT 'proposed',
+                              starts_at TIMESTAMPTZ, -- выбранное время, заполнено для confirmed
+                              ends_at TIMESTAMPTZ,
+                              address TEXT NOT NULL DEFAULT '',
+                              note TEXT NOT NULL DEFAULT '',
+                              cancel_reason TEXT,
+                              cancelled_by VARCHAR(10), -- client, master
+                              reminded_day_at TIMESTAMPTZ,  -- напоминание за сутки
+                              reminded_hour_at TIMESTAMPTZ, -- напоминание за пару часов
+                              created_at TIMESTAMP NOT NULL DEFAULT now(),
+                              updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Один незавершённый выезд мастера по проекту
+CREATE UNIQUE INDEX idx_appointments_active ON appointments(project_id, master_id)
+    WHERE status IN ('proposed', 'confirmed');
+CREATE INDEX idx_appointments_master ON appointments(master_id, starts_at);
+CREATE INDEX idx_appointments_reminders ON appointments(starts_at) WHERE status = 'confirmed';
+
+-- Предложенные мастером варианты времени
+CREATE TABLE appointment_slots (
+                                   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                   appointment_id UUID NOT NULL REFERENCES appointments(id) ON DELETE CASCADE,
+                                   starts_at TIMESTAMPTZ NOT NULL,
+                                   ends_at TIMESTAMPTZ NOT NULL CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_appointment_slots_appointment ON appointment_slots(appointment_id, starts_at);
+
+-- История выезда: предложение, подтверждение, переносы и отмена с причинами
+CREATE TABLE appointment_changes (
+                                     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                     appointment_id UUID NOT NULL REFERENCES appointments(id) ON DELETE CASCADE,
+                                     actor_role VARCHAR(10) NOT NULL, -- client, master, system
+                                     action VARCHAR(20) NOT NULL,     -- proposed, confirmed, rescheduled, cancelled
+                                     reason TEXT NOT NULL DEFAULT '',
+                                     starts_at TIMESTAMPTZ, -- время выезда после изменения
+                                     created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_appointment_changes_appointment ON appointment_changes(appointment_id, created_at);
@@ -0,0 +1,12 @@
+-- Одноразовые билеты для подключения к realtime: браузер не может передать
+-- заголовок Authorization в WebSocket/EventSource, а JWT в ?token= попадал
+-- в логи запросов. Хранится SHA-256 билета, а не сам билет.
+CREATE TABLE realtime_tickets (
+                                  id VARCHAR(64) PRIMARY KEY,
+                                  user_id UUID NOT NULL,
+                                  role VARCHAR(20) NOT NULL DEFAULT '',
+                                  expires_at TIMESTAMPTZ NOT NULL,
+                                  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_realtime_tickets_expires ON realtime_tickets(expires_at);
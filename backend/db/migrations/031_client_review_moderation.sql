@@ -0,0 +1,30 @@
+-- Модерация отзывов мастеров о клиентах - так же, как отзывов о мастерах
+ALTER TABLE client_reviews ADD COLUMN moderation_status VARCHAR(20) NOT NULL DEFAULT 'approved'; -- pending, approved, rejected
+ALTER TABLE client_reviews ADD COLUMN flags JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE client_reviews ADD COLUMN ip VARCHAR(45);
+
+CREATE INDEX idx_client_reviews_pending ON client_reviews(created_at) WHERE moderation_status = 'pending';
+CREATE INDEX idx_client_reviews_client_ip ON client_reviews(client_id, ip);
+
+-- Споры и журнал модерации общие для обоих видов отзывов. kind: master -
+-- отзыв клиента о мастере (review_id), client - отзыв мастера о клиенте
+-- (client_review_id)
+ALTER TABLE review_disputes ADD COLUMN kind VARCHAR(10) NOT NULL DEFAULT 'master';
+ALTER TABLE review_disputes ALTER COLUMN review_id DROP NOT NULL;
+ALTER TABLE review_disputes ADD COLUMN client_review_id UUID REFERENCES client_reviews(id) ON DELETE CASCADE;
+ALTER TABLE review_disputes ADD CONSTRAINT review_disputes_target CHECK (
+    (kind = 'master' AND review_id IS NOT NULL AND client_review_id IS NULL) OR
+    (kind = 'client' AND client_review_id IS NOT NULL AND review_id IS NULL)
+);
+CREATE UNIQUE INDEX idx_review_disputes_client_open ON review_disputes(client_review_id) WHERE status = 'open';
+
+-- Спор открывает тот, о ком отзыв: мастер или клиент. Храним пользователя
+ALTER TABLE review_disputes ADD COLUMN opened_by UUID REFERENCES users(id) ON DELETE CASCADE;
+UPDATE review_disputes d SET opened_by = m.user_id FROM masters m WHERE m.id = d.master_id;
+ALTER TABLE review_disputes ALTER COLUMN opened_by SET NOT NULL;
+ALTER TABLE review_disputes DROP COLUMN master_id;
+
+ALTER TABLE moderation_actions ADD COLUMN kind VARCHAR(10) NOT NULL DEFAULT 'master';
+ALTER TABLE moderation_actions ADD COLUMN client_review_id UUID REFERENCES client_reviews(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_moderation_actions_client_review ON moderation_actions(client_review_id, created_at);
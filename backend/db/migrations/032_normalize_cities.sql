@@ -0,0 +1,32 @@
+-- Города, сохранённые до появления справочника, приводятся к каноническому
+-- названию так же, как новые записи (cities.Normalize). city_key повторяет
+-- cities.Key. Не найденные в справочнике значения остаются как есть.
+-- На пустой базе справочник ещё не загружен - обновлять там нечего.
+CREATE FUNCTION city_key(s TEXT) RETURNS TEXT AS $$
+SELECT btrim(regexp_replace(
+               translate(
+                       regexp_replace(replace(lower(btrim(s)), 'ё', 'е'), '^(г\.|город )', ''),
+                       '-.,', '   '),
+               '\s+', ' ', 'g'))
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Ключ -> название; при совпадении ключей побеждает крупный город
+CREATE TEMP TABLE city_names AS
+SELECT DISTINCT ON (k) k, name
+FROM cities, unnest(search_keys) AS k
+ORDER BY k, population DESC;
+
+UPDATE projects p SET city = c.name
+FROM city_names c
+WHERE c.k = city_key(p.city) AND p.city IS DISTINCT FROM c.name;
+
+UPDATE masters m SET city = c.name
+FROM city_names c
+WHERE c.k = city_key(m.city) AND m.city IS DISTINCT FROM c.name;
+
+UPDATE saved_searches s SET city = c.name
+FROM city_names c
+WHERE c.k = city_key(s.city) AND s.city IS DISTINCT FROM c.name;
+
+DROP TABLE city_names;
+DROP FUNCTION city_key(TEXT);
@@ -0,0 +1,213 @@
+// internal/alerts/alerts.go
+package alerts
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"refurnish/internal/catalog"
+	"refurnish/internal/cities"
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+	"refurnish/internal/notify"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UnsubscribeURL - ссылка для отписки от сохранённого поиска без входа
+// в систему. Ведёт на страницу подтверждения во фронтенде: сама ссылка
+// ничего не меняет, иначе отписку запускали бы сканеры ссылок в почте.
+func UnsubscribeURL(token string) string {
+	return config.PublicURL() + "/unsubscribe?token=" + url.QueryEscape(token)
+}
+
+// ProjectPublished подбирает сохранённые поиски под только что опубликованный
+// проект: при мгновенной доставке мастер получает уведомление сразу,
+// при ежедневной совпадение копится до дайджеста.
+func ProjectPublished(db *gorm.DB, projectID string) {
+	var project models.Project
+	if err := db.First(&project, "id = ?", projectID).Error; err != nil {
+		log.Printf("⚠️  [ALERTS] Проект %s не найден: %v", projectID, err)
+		return
+	}
+	if project.Status != "published" || project.Visibility != "public" {
+		return
+	}
+
+	var searches []models.SavedSearch
+	err := db.Preload("Master").
+		Where("is_active").
+		Where("budget_min IS NULL OR budget_min <= ?", project.Budget).
+		Where("budget_max IS NULL OR budget_max >= ?", project.Budget).
+		Find(&searches).Error
+	if err != nil {
+		log.Printf("❌ [ALERTS] Ошибка загрузки поисков: %v", err)
+		return
+	}
+
+	m := newMatcher(db, project)
+	matched := 0
+	for _, search := range searches {
+		if search.Master == nil || !m.matches(search) {
+			continue
+		}
+
+		match := models.SavedSearchMatch{SearchID: search.ID, ProjectID: project.ID}
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&match)
+		if result.Error != nil || result.RowsAffected == 0 {
+			continue
+		}
+		matched++
+
+		if search.Delivery != "instant" {
+			continue
+		}
+
+		err := notify.Send(db, search.Master.UserID, notify.NewProjectAlert, map[string]interface{}{
+			"projectId":      project.ID,
+			"projectTitle":   project.Title,
+			"city":           project.City,
+			"budget":         project.Budget,
+			"searchId":       search.ID,
+			"searchName":     search.Name,
+			"unsubscribeUrl": UnsubscribeURL(search.UnsubscribeToken),
+		})
+		if err == nil {
+			db.Model(&models.SavedSearchMatch{}).
+				Where("search_id = ? AND project_id = ?", search.ID, project.ID).
+				Update("sent_at", time.Now())
+		}
+	}
+
+	if matched > 0 {
+		log.Printf("🔎 [ALERTS] Проект %s подошёл под %d сохранённых поисков", project.ID, matched)
+	}
+}
+
+// SendDigests раз в сутки отправляет накопившиеся совпадения по поискам с ежедневной доставкой
+func SendDigests(db *gorm.DB) func(context.Context) error {
+	return func(ctx context.Context) error {
+		now := time.Now()
+
+		var searches []models.SavedSearch
+		err := db.WithContext(ctx).Preload("Master").
+			Where("is_active AND delivery = ?", "daily").
+			Where("last_digest_at IS NULL OR last_digest_at <= ?", now.Add(-24*time.Hour)).
+			Where("EXISTS (SELECT 1 FROM saved_search_matches m WHERE m.search_id = saved_searches.id AND m.sent_at IS NULL)").
+			Find(&searches).Error
+		if err != nil {
+			return err
+		}
+
+		for _, search := range searches {
+			var projectIDs []string
+			db.Model(&models.SavedSearchMatch{}).
+				Where("search_id = ? AND sent_at IS NULL", search.ID).
+				Order("created_at").
+				Pluck("project_id", &projectIDs)
+			if len(projectIDs) == 0 || search.Master == nil {
+				continue
+			}
+
+			err := notify.Send(db, search.Master.UserID, notify.NewProjectsDigest, map[string]interface{}{
+				"searchId":       search.ID,
+				"searchName":     search.Name,
+				"projectIds":     projectIDs,
+				"count":          len(projectIDs),
+				"unsubscribeUrl": UnsubscribeURL(search.UnsubscribeToken),
+			})
+			if err != nil {
+				log.Printf("⚠️  [ALERTS] Не удалось отправить дайджест %s: %v", search.ID, err)
+				continue
+			}
+
+			db.Model(&models.SavedSearchMatch{}).
+				Where("search_id = ? AND project_id IN ?", search.ID, projectIDs).
+				Update("sent_at", now)
+			db.Model(&models.SavedSearch{}).Where("id = ?", search.ID).Update("last_digest_at", now)
+		}
+		return nil
+	}
+}
+
+// matcher кэширует данные проекта, общие для проверки всех поисков
+type matcher struct {
+	db          *gorm.DB
+	project     models.Project
+	projectCity *models.City
+	text        string
+	categories  map[string][]string // поддеревья категорий поисков
+}
+
+func newMatcher(db *gorm.DB, project models.Project) *matcher {
+	m := &matcher{
+		db:         db,
+		project:    project,
+		text:       strings.ToLower(project.Title + " " + project.Description),
+		categories: make(map[string][]string),
+	}
+	if city, err := cities.Find(db, project.City); err == nil {
+		m.projectCity = city
+	}
+	return m
+}
+
+func (m *matcher) matches(search models.SavedSearch) bool {
+	return m.matchesCategory(search) && m.matchesCity(search) && m.matchesKeywords(search)
+}
+
+func (m *matcher) matchesCategory(search models.SavedSearch) bool {
+	if search.CategoryID == nil {
+		return true
+	}
+	if m.project.CategoryID == nil {
+		return false
+	}
+
+	ids, ok := m.categories[*search.CategoryID]
+	if !ok {
+		ids, _ = catalog.SubtreeIDs(m.db, *search.CategoryID)
+		m.categories[*search.CategoryID] = ids
+	}
+	for _, id := range ids {
+		if id == *m.project.CategoryID {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *matcher) matchesCity(search models.SavedSearch) bool {
+	if search.City == "" || search.City == m.project.City {
+		return true
+	}
+	if search.RadiusKm <= 0 || m.projectCity == nil {
+		return false
+	}
+
+	searchCity, err := cities.Find(m.db, search.City)
+	if err != nil {
+		return false
+	}
+	return cities.DistanceKm(*searchCity, *m.projectCity) <= float64(search.RadiusKm)
+}
+
+// matchesKeywords - достаточно одного ключевого слова из списка
+func (m *matcher) matchesKeywords(search models.SavedSearch) bool {
+	keywords := strings.FieldsFunc(strings.ToLower(search.Keywords), func(r rune) bool {
+		return r == ',' || r == ';' || r == ' '
+	})
+	if len(keywords) == 0 {
+		return true
+	}
+	for _, kw := range keywords {
+		if strings.Contains(m.text, kw) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,338 @@
+// internal/appointments/appointments.go
+package appointments
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"refurnish/internal/availability"
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Выезд на замер: мастер предлагает несколько вариантов времени из своего
+// календаря, клиент выбирает один. Предложенные варианты держат время
+// в календаре мастера, пока клиент не выбрал или выезд не отменён.
+
+// Статусы выезда
+const (
+	StatusProposed  = "proposed"
+	StatusConfirmed = "confirmed"
+	StatusCancelled = "cancelled"
+)
+
+// Кто изменил выезд
+const (
+	RoleClient = "client"
+	RoleMaster = "master"
+	RoleSystem = "system"
+)
+
+// Записи истории выезда
+const (
+	ActionProposed    = "proposed"
+	ActionConfirmed   = "confirmed"
+	ActionRescheduled = "rescheduled"
+	ActionCancelled   = "cancelled"
+)
+
+const (
+	// MaxSlots - сколько вариантов времени можно предложить за раз
+	MaxSlots    = 10
+	MinDuration = 15 * time.Minute
+	MaxDuration = 4 * time.Hour
+	// MinLeadTime - время выезда не ближе, чем через столько от текущего момента
+	MinLeadTime = time.Hour
+	// Horizon - насколько вперёд можно назначить выезд
+	Horizon = 60 * 24 * time.Hour
+)
+
+// Напоминания: за сутки и за пару часов до выезда
+const (
+	remindDayBefore  = 24 * time.Hour
+	remindHourBefore = 2 * time.Hour
+)
+
+// WhenLayout - формат времени выезда в уведомлениях
+const WhenLayout = "02.01.2006 15:04"
+
+var (
+	ErrNoSlots         = errors.New("предложите хотя бы один вариант времени")
+	ErrTooManySlots    = errors.New("не больше 10 вариантов времени")
+	ErrSlotDuration    = errors.New("выезд длится от 15 минут до 4 часов")
+	ErrSlotTooSoon     = errors.New("время выезда - не раньше чем через час и не позже чем через 60 дней")
+	ErrSlotsOverlap    = errors.New("варианты времени пересекаются")
+	ErrSlotUnavailable = errors.New("время вне рабочих часов мастера или уже занято")
+)
+
+// Calendar - календарь мастера с занятыми выездами. Подтверждённые выезды
+// и предложенные варианты других выездов попадают в Booked; except -
+// выезд, время которого сейчас меняется, его варианты не мешают сами себе.
+func Calendar(db *gorm.DB, master models.Master, from, to time.Time, except string) (*availability.Calendar, error) {
+	cal, err := availability.Load(db, master, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var booked []struct {
+		StartsAt time.Time
+		EndsAt   time.Time
+	}
+	err = db.Raw(`
+		SELECT a.starts_at, a.ends_at FROM appointments a
+		WHERE a.master_id = ? AND a.status = 'confirmed' AND a.id::text <> ?
+		  AND a.starts_at < ? AND a.ends_at > ?
+		UNION ALL
+		SELECT s.starts_at, s.ends_at FROM appointment_slots s
+		JOIN appointments a ON a.id = s.appointment_id
+		WHERE a.master_id = ? AND a.status = 'proposed' AND a.id::text <> ?
+		  AND s.starts_at < ? AND s.ends_at > ?
+	`, master.ID, except, to.AddDate(0, 0, 1), from,
+		master.ID, except, to.AddDate(0, 0, 1), from).Scan(&booked).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range booked {
+		cal.Booked = append(cal.Booked, availability.Period{Start: b.StartsAt, End: b.EndsAt})
+	}
+	return cal, nil
+}
+
+// CheckSlots проверяет варианты времени по календарю мастера
+func CheckSlots(db *gorm.DB, master models.Master, slots []availability.Period, except string) error {
+	if len(slots) == 0 {
+		return ErrNoSlots
+	}
+	if len(slots) > MaxSlots {
+		return ErrTooManySlots
+	}
+
+	now := time.Now()
+	from, to := slots[0].Start, slots[0].End
+	for i, slot := range slots {
+		duration := slot.End.Sub(slot.Start)
+		if duration < MinDuration || duration > MaxDuration {
+			return ErrSlotDuration
+		}
+		if slot.Start.Before(now.Add(MinLeadTime)) || slot.Start.After(now.Add(Horizon)) {
+			return ErrSlotTooSoon
+		}
+		for _, other := range slots[:i] {
+			if slot.Start.Before(other.End) && other.Start.Before(slot.End) {
+				return ErrSlotsOverlap
+			}
+		}
+		if slot.Start.Before(from) {
+			from = slot.Start
+		}
+		if slot.End.After(to) {
+			to = slot.End
+		}
+	}
+
+	cal, err := Calendar(db, master, from, to, except)
+	if err != nil {
+		return err
+	}
+	for _, slot := range slots {
+		if !cal.Fits(slot) {
+			return ErrSlotUnavailable
+		}
+	}
+	return nil
+}
+
+// Lock сериализует запись в календарь мастера до конца транзакции,
+// чтобы два клиента не заняли одно время
+func Lock(tx *gorm.DB, masterID string) error {
+	return tx.Exec("SELECT 1 FROM masters WHERE id = ? FOR UPDATE", masterID).Error
+}
+
+// Propose создаёт выезд с вариантами времени
+func Propose(tx *gorm.DB, appointment *models.Appointment, slots []availability.Period) error {
+	appointment.Status = StatusProposed
+	if err := tx.Create(appointment).Error; err != nil {
+		return err
+	}
+	if err := replaceSlots(tx, appointment, slots); err != nil {
+		return err
+	}
+	return Record(tx, appointment.ID, RoleMaster, ActionProposed, "", nil)
+}
+
+// Reschedule - мастер предлагает новые варианты времени. Выбранное ранее
+// время освобождается, выезд снова ждёт выбора клиента.
+func Reschedule(tx *gorm.DB, appointment *models.Appointment, slots []availability.Period, reason string) error {
+	if err := tx.Model(appointment).Updates(map[string]interface{}{
+		"status":           StatusProposed,
+		"starts_at":        nil,
+		"ends_at":          nil,
+		"reminded_day_at":  nil,
+		"reminded_hour_at": nil,
+		"updated_at":       time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+	appointment.Status = StatusProposed
+	appointment.StartsAt = nil
+	appointment.EndsAt = nil
+	if err := replaceSlots(tx, appointment, slots); err != nil {
+		return err
+	}
+	return Record(tx, appointment.ID, RoleMaster, ActionRescheduled, reason, nil)
+}
+
+// Reopen - клиент просит другое время: выезд возвращается к выбору
+// из оставшихся вариантов, мастер может предложить новые
+func Reopen(tx *gorm.DB, appointment *models.Appointment, reason string) error {
+	// Updates переписывает поля структуры значениями из map, поэтому
+	// прежнее время запоминаем до него
+	rejected := appointment.StartsAt
+	if err := tx.Model(appointment).Updates(map[string]interface{}{
+		"status":           StatusProposed,
+		"starts_at":        nil,
+		"ends_at":          nil,
+		"reminded_day_at":  nil,
+		"reminded_hour_at": nil,
+		"updated_at":       time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+	// Прежнее время клиенту не подошло, а слишком близкие варианты уже не выбрать
+	stale := tx.Where("appointment_id = ? AND starts_at <= ?", appointment.ID, time.Now().Add(MinLeadTime))
+	if rejected != nil {
+		stale = tx.Where("appointment_id = ? AND (starts_at <= ? OR starts_at = ?)",
+			appointment.ID, time.Now().Add(MinLeadTime), *rejected)
+	}
+	if err := stale.Delete(&models.AppointmentSlot{}).Error; err != nil {
+		return err
+	}
+	appointment.Status = StatusProposed
+	appointment.StartsAt = nil
+	appointment.EndsAt = nil
+	if err := tx.Where("appointment_id = ?", appointment.ID).Order("starts_at").
+		Find(&appointment.Slots).Error; err != nil {
+		return err
+	}
+	return Record(tx, appointment.ID, RoleClient, ActionRescheduled, reason, nil)
+}
+
+// Schedule назначает выезд на выбранный вариант. action - confirmed при
+// первом выборе или rescheduled, если клиент переносит подтверждённый выезд.
+func Schedule(tx *gorm.DB, appointment *models.Appointment, slot models.AppointmentSlot, action, reason string) error {
+	now := time.Now()
+	if err := tx.Model(appointment).Updates(map[string]interface{}{
+		"status":     StatusConfirmed,
+		"starts_at":  slot.StartsAt,
+		"ends_at":    slot.EndsAt,
+		"updated_at": now,
+		// Подтверждение и есть напоминание, если до выезда меньше суток
+		"reminded_day_at":  remindedAt(slot.StartsAt, remindDayBefore, now),
+		"reminded_hour_at": remindedAt(slot.StartsAt, remindHourBefore, now),
+	}).Error; err != nil {
+		return err
+	}
+	appointment.Status = StatusConfirmed
+	appointment.StartsAt = &slot.StartsAt
+	appointment.EndsAt = &slot.EndsAt
+	return Record(tx, appointment.ID, RoleClient, action, reason, &slot.StartsAt)
+}
+
+// Cancel отменяет выезд с причиной
+func Cancel(tx *gorm.DB, appointment *models.Appointment, role, reason string) error {
+	if err := tx.Model(appointment).Updates(map[string]interface{}{
+		"status":        StatusCancelled,
+		"cancel_reason": reason,
+		"cancelled_by":  role,
+		"updated_at":    time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+	appointment.Status = StatusCancelled
+	return Record(tx, appointment.ID, role, ActionCancelled, reason, appointment.StartsAt)
+}
+
+// Record пишет запись в историю выезда
+func Record(tx *gorm.DB, appointmentID, role, action, reason string, startsAt *time.Time) error {
+	return tx.Create(&models.AppointmentChange{
+		AppointmentID: appointmentID,
+		ActorRole:     role,
+		Action:        action,
+		Reason:        reason,
+		StartsAt:      startsAt,
+	}).Error
+}
+
+// Payload - данные выезда для событий и уведомлений. appointment должен
+// быть загружен с Project.Client и Master.
+func Payload(db *gorm.DB, appointment *models.Appointment) map[string]interface{} {
+	loc := time.Local
+	payload := map[string]interface{}{
+		"appointmentId": appointment.ID,
+		"status":        appointment.Status,
+		"address":       appointment.Address,
+	}
+	if appointment.Master != nil {
+		loc = availability.Location(db, *appointment.Master)
+		payload["masterId"] = appointment.Master.ID
+		payload["masterUserId"] = appointment.Master.UserID
+		payload["masterName"] = appointment.Master.Name
+	}
+	if appointment.Project != nil {
+		payload["projectId"] = appointment.Project.ID
+		payload["projectTitle"] = appointment.Project.Title
+		payload["clientUserId"] = appointment.Project.Client.UserID
+	}
+	if appointment.StartsAt != nil {
+		payload["startsAt"] = appointment.StartsAt.Format(time.RFC3339)
+		payload["when"] = appointment.StartsAt.In(loc).Format(WhenLayout)
+	}
+
+	var slots []string
+	for _, slot := range appointment.Slots {
+		slots = append(slots, slot.StartsAt.In(loc).Format(WhenLayout))
+	}
+	payload["slots"] = strings.Join(slots, ", ")
+	return payload
+}
+
+// Load загружает выезд со всем, что нужно для Payload
+func Load(db *gorm.DB, id string) (*models.Appointment, error) {
+	var appointment models.Appointment
+	err := db.Preload("Project.Client").Preload("Master").
+		Preload("Slots", func(db *gorm.DB) *gorm.DB { return db.Order("starts_at") }).
+		First(&appointment, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &appointment, nil
+}
+
+func replaceSlots(tx *gorm.DB, appointment *models.Appointment, slots []availability.Period) error {
+	if err := tx.Where("appointment_id = ?", appointment.ID).Delete(&models.AppointmentSlot{}).Error; err != nil {
+		return err
+	}
+	rows := make([]models.AppointmentSlot, 0, len(slots))
+	for _, slot := range slots {
+		rows = append(rows, models.AppointmentSlot{
+			AppointmentID: appointment.ID,
+			StartsAt:      slot.Start,
+			EndsAt:        slot.End,
+		})
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return err
+	}
+	appointment.Slots = rows
+	return nil
+}
+
+// remindedAt - напоминание считается отправленным, если до выезда уже меньше before
+func remindedAt(startsAt time.Time, before time.Duration, now time.Time) interface{} {
+	if startsAt.Sub(now) <= before {
+		return now
+	}
+	return nil
+}
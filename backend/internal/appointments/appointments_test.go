@@ -0,0 +1,127 @@
+package appointments
+
+import (
+	"testing"
+	"time"
+
+	"refurnish/internal/availability"
+	"refurnish/internal/models"
+	"refurnish/internal/testdb"
+
+	"gorm.io/gorm"
+)
+
+// proposed - выезд с тремя вариантами времени через день, два и три
+func proposed(t *testing.T, db *gorm.DB) (*models.Appointment, []availability.Period) {
+	t.Helper()
+	clientID, _ := testdb.Client(t, db)
+	masterID, _ := testdb.Master(t, db)
+	projectID := testdb.Project(t, db, clientID, nil)
+
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	var slots []availability.Period
+	for day := 0; day < 3; day++ {
+		start := base.AddDate(0, 0, day)
+		slots = append(slots, availability.Period{Start: start, End: start.Add(time.Hour)})
+	}
+	appointment := &models.Appointment{ProjectID: projectID, MasterID: masterID, Address: "Тверская, 1"}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return Propose(tx, appointment, slots)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(db, appointment.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loaded, slots
+}
+
+func slotStarts(t *testing.T, db *gorm.DB, appointmentID string) []time.Time {
+	t.Helper()
+	var slots []models.AppointmentSlot
+	if err := db.Where("appointment_id = ?", appointmentID).Order("starts_at").Find(&slots).Error; err != nil {
+		t.Fatal(err)
+	}
+	starts := make([]time.Time, 0, len(slots))
+	for _, s := range slots {
+		starts = append(starts, s.StartsAt)
+	}
+	return starts
+}
+
+// Клиент переносит подтверждённый выезд: отвергнутое время пропадает из
+// вариантов, остальные остаются, выезд снова ждёт выбора
+func TestReopenDropsRejectedSlot(t *testing.T) {
+	db := testdb.Open(t)
+	appointment, slots := proposed(t, db)
+
+	chosen := appointment.Slots[1]
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return Schedule(tx, appointment, chosen, ActionConfirmed, "")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	confirmed, err := Load(db, appointment.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return Reopen(tx, confirmed, "не успеваю к этому времени")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if confirmed.Status != StatusProposed || confirmed.StartsAt != nil {
+		t.Errorf("после переноса status=%s startsAt=%v, ожидался proposed без времени", confirmed.Status, confirmed.StartsAt)
+	}
+	starts := slotStarts(t, db, appointment.ID)
+	if len(starts) != 2 || !starts[0].Equal(slots[0].Start) || !starts[1].Equal(slots[2].Start) {
+		t.Errorf("варианты после переноса %v, ожидались %v и %v", starts, slots[0].Start, slots[2].Start)
+	}
+	if len(confirmed.Slots) != 2 {
+		t.Errorf("в выезде %d вариантов, ожидалось 2", len(confirmed.Slots))
+	}
+
+	var stored models.Appointment
+	db.First(&stored, "id = ?", appointment.ID)
+	if stored.Status != StatusProposed || stored.StartsAt != nil {
+		t.Errorf("в базе status=%s startsAt=%v", stored.Status, stored.StartsAt)
+	}
+	var history int64
+	db.Model(&models.AppointmentChange{}).
+		Where("appointment_id = ? AND actor_role = ? AND action = ?", appointment.ID, RoleClient, ActionRescheduled).
+		Count(&history)
+	if history != 1 {
+		t.Errorf("записей о переносе клиентом: %d, ожидалась 1", history)
+	}
+}
+
+// Мастер переносит выезд: выбранное время освобождается, варианты заменяются новыми
+func TestRescheduleReplacesSlots(t *testing.T) {
+	db := testdb.Open(t)
+	appointment, slots := proposed(t, db)
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return Schedule(tx, appointment, appointment.Slots[0], ActionConfirmed, "")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	later := slots[2].Start.AddDate(0, 0, 1)
+	fresh := []availability.Period{{Start: later, End: later.Add(90 * time.Minute)}}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return Reschedule(tx, appointment, fresh, "заболел")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if appointment.Status != StatusProposed || appointment.StartsAt != nil {
+		t.Errorf("после переноса status=%s startsAt=%v", appointment.Status, appointment.StartsAt)
+	}
+	starts := slotStarts(t, db, appointment.ID)
+	if len(starts) != 1 || !starts[0].Equal(later) {
+		t.Errorf("варианты после переноса %v, ожидался только %v", starts, later)
+	}
+}
@@ -0,0 +1,68 @@
+// internal/appointments/reminders.go
+package appointments
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"refurnish/internal/events"
+
+	"gorm.io/gorm"
+)
+
+// SendReminders напоминает клиенту и мастеру о выезде за сутки и за пару
+// часов. Отметка о напоминании и событие пишутся в одной транзакции,
+// уведомления рассылают подписчики appointment.reminder.
+func SendReminders(db *gorm.DB) func(context.Context) error {
+	return func(ctx context.Context) error {
+		now := time.Now()
+
+		var sent int
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			// Сначала ближние: за два часа напоминание за сутки уже не нужно
+			var soon []string
+			err := tx.Raw(`
+				UPDATE appointments
+				SET reminded_hour_at = ?, reminded_day_at = COALESCE(reminded_day_at, ?)
+				WHERE status = 'confirmed' AND reminded_hour_at IS NULL
+				  AND starts_at > ? AND starts_at <= ?
+				RETURNING id
+			`, now, now, now, now.Add(remindHourBefore)).Scan(&soon).Error
+			if err != nil {
+				return err
+			}
+			var tomorrow []string
+			err = tx.Raw(`
+				UPDATE appointments SET reminded_day_at = ?
+				WHERE status = 'confirmed' AND reminded_day_at IS NULL
+				  AND starts_at > ? AND starts_at <= ?
+				RETURNING id
+			`, now, now, now.Add(remindDayBefore)).Scan(&tomorrow).Error
+			if err != nil {
+				return err
+			}
+
+			for _, id := range append(soon, tomorrow...) {
+				appointment, err := Load(tx, id)
+				if err != nil {
+					return err
+				}
+				if err := events.Emit(tx, events.AppointmentReminder, id, Payload(tx, appointment)); err != nil {
+					return err
+				}
+				sent++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if sent > 0 {
+			events.Wake()
+			log.Printf("⏰ Напоминания о замерах: %d", sent)
+		}
+		return nil
+	}
+}
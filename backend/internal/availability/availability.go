@@ -0,0 +1,380 @@
+// internal/availability/availability.go
+package availability
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata" // в контейнере может не быть системной базы часовых поясов
+
+	"refurnish/internal/cities"
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Календарь мастера складывается из рабочих часов по дням недели,
+// выходных и отпусков и периодов занятости по назначенным проектам:
+// от даты начала из отклика до дедлайна. День свободен, если он рабочий,
+// не выходной и мастер ведёт меньше MaxConcurrentProjects проектов.
+
+// DefaultTimezone - если город мастера не найден в справочнике
+const DefaultTimezone = "Europe/Moscow"
+
+// Виды нерабочих периодов
+const (
+	DayOff   = "day_off"
+	Vacation = "vacation"
+)
+
+// DefaultHours - график, пока мастер не задал свой: будни 09:00-18:00
+var DefaultHours = map[int]models.WorkingHours{
+	1: {Weekday: 1, StartMinute: 9 * 60, EndMinute: 18 * 60},
+	2: {Weekday: 2, StartMinute: 9 * 60, EndMinute: 18 * 60},
+	3: {Weekday: 3, StartMinute: 9 * 60, EndMinute: 18 * 60},
+	4: {Weekday: 4, StartMinute: 9 * 60, EndMinute: 18 * 60},
+	5: {Weekday: 5, StartMinute: 9 * 60, EndMinute: 18 * 60},
+}
+
+// dateLayout - даты календаря сравниваются как строки YYYY-MM-DD
+const dateLayout = "2006-01-02"
+
+// Busy - период занятости по назначенному проекту, даты включительно
+type Busy struct {
+	ProjectID string
+	StartsOn  string
+	EndsOn    string
+}
+
+// Period - интервал времени, например занятый слот или свободное окно
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Calendar - расписание мастера
+type Calendar struct {
+	Location      *time.Location
+	MaxConcurrent int
+	Hours         map[int]models.WorkingHours // по ISO-дню недели
+	TimeOff       []models.TimeOff
+	Busy          []Busy
+	// Booked - уже занятые интервалы внутри рабочих часов (например, выезды на замер)
+	Booked []Period
+}
+
+// Day - один день календаря
+type Day struct {
+	Date           string
+	Working        bool // рабочий день по графику
+	StartMinute    int
+	EndMinute      int
+	TimeOff        string // вид выходного, если день нерабочий по заявке мастера
+	ActiveProjects int
+}
+
+// Load загружает календарь мастера. Выходные и проекты берутся только
+// пересекающиеся с [from, to].
+func Load(db *gorm.DB, master models.Master, from, to time.Time) (*Calendar, error) {
+	cal := &Calendar{
+		Location:      Location(db, master),
+		MaxConcurrent: master.MaxConcurrentProjects,
+		Hours:         map[int]models.WorkingHours{},
+	}
+	if cal.MaxConcurrent <= 0 {
+		cal.MaxConcurrent = 1
+	}
+
+	var hours []models.WorkingHours
+	if err := db.Where("master_id = ?", master.ID).Find(&hours).Error; err != nil {
+		return nil, err
+	}
+	if len(hours) == 0 {
+		cal.Hours = DefaultHours
+	}
+	for _, h := range hours {
+		cal.Hours[h.Weekday] = h
+	}
+
+	fromDate := from.In(cal.Location).Format(dateLayout)
+	toDate := to.In(cal.Location).Format(dateLayout)
+
+	if err := db.Where("master_id = ? AND starts_on <= ? AND ends_on >= ?", master.ID, toDate, fromDate).
+		Order("starts_on").Find(&cal.TimeOff).Error; err != nil {
+		return nil, err
+	}
+
+	var projects []struct {
+		ProjectID string
+		StartsAt  time.Time
+		Deadline  time.Time
+	}
+	err := db.Raw(`
+		SELECT p.id AS project_id, COALESCE(r.start_date, p.updated_at) AS starts_at, p.deadline
+		FROM projects p
+		LEFT JOIN responses r ON r.project_id = p.id AND r.master_id = p.assigned_master
+		WHERE p.assigned_master = ? AND p.status = 'assigned'
+	`, master.ID).Scan(&projects).Error
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now().In(cal.Location).Format(dateLayout)
+	for _, p := range projects {
+		busy := Busy{
+			ProjectID: p.ProjectID,
+			StartsOn:  p.StartsAt.In(cal.Location).Format(dateLayout),
+			EndsOn:    p.Deadline.In(cal.Location).Format(dateLayout),
+		}
+		// Проект не завершён - мастер занят им и после дедлайна
+		if busy.EndsOn < today {
+			busy.EndsOn = today
+		}
+		if busy.EndsOn < busy.StartsOn {
+			busy.EndsOn = busy.StartsOn
+		}
+		if busy.StartsOn <= toDate && busy.EndsOn >= fromDate {
+			cal.Busy = append(cal.Busy, busy)
+		}
+	}
+
+	return cal, nil
+}
+
+// Location - часовой пояс города мастера
+func Location(db *gorm.DB, master models.Master) *time.Location {
+	name := DefaultTimezone
+	if master.City != "" {
+		if city, err := cities.Find(db, master.City); err == nil && city.Timezone != "" {
+			name = city.Timezone
+		}
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		loc, _ = time.LoadLocation(DefaultTimezone)
+	}
+	return loc
+}
+
+// Days возвращает дни календаря с from по to включительно
+func (c *Calendar) Days(from, to time.Time) []Day {
+	start := startOfDay(from.In(c.Location))
+	end := startOfDay(to.In(c.Location))
+
+	var days []Day
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		date := d.Format(dateLayout)
+		day := Day{Date: date, ActiveProjects: c.activeOn(date)}
+		if h, ok := c.Hours[isoWeekday(d)]; ok {
+			day.Working = true
+			day.StartMinute = h.StartMinute
+			day.EndMinute = h.EndMinute
+		}
+		for _, off := range c.TimeOff {
+			if off.StartsOn.Format(dateLayout) <= date && off.EndsOn.Format(dateLayout) >= date {
+				day.TimeOff = off.Kind
+				break
+			}
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
+// Free - день рабочий, не выходной и у мастера есть место под новый проект
+func (c *Calendar) Free(d Day) bool {
+	return d.Working && d.TimeOff == "" && d.ActiveProjects < c.MaxConcurrent
+}
+
+// Open - день рабочий и не выходной. Загрузка проектами не учитывается:
+// на замер мастер может выехать и когда занят
+func (c *Calendar) Open(d Day) bool {
+	return d.Working && d.TimeOff == ""
+}
+
+// Slots - свободные окна длиной duration в рабочие часы свободных дней,
+// за вычетом Booked и уже прошедшего времени
+func (c *Calendar) Slots(from, to time.Time, duration time.Duration) []Period {
+	return c.slots(from, to, duration, c.Free)
+}
+
+// VisitSlots - окна для выезда на замер: как Slots, но в любой открытый день
+func (c *Calendar) VisitSlots(from, to time.Time, duration time.Duration) []Period {
+	return c.slots(from, to, duration, c.Open)
+}
+
+// Fits - интервал целиком в рабочих часах открытого дня и не пересекается с Booked
+func (c *Calendar) Fits(p Period) bool {
+	start := p.Start.In(c.Location)
+	end := p.End.In(c.Location)
+	if !end.After(start) || !startOfDay(start).Equal(startOfDay(end.Add(-time.Nanosecond))) {
+		return false
+	}
+	days := c.Days(start, start)
+	if len(days) == 0 || !c.Open(days[0]) {
+		return false
+	}
+	midnight := startOfDay(start)
+	if start.Sub(midnight) < time.Duration(days[0].StartMinute)*time.Minute ||
+		end.Sub(midnight) > time.Duration(days[0].EndMinute)*time.Minute {
+		return false
+	}
+	return !c.booked(p)
+}
+
+func (c *Calendar) slots(from, to time.Time, duration time.Duration, free func(Day) bool) []Period {
+	now := time.Now()
+	var slots []Period
+	for _, day := range c.Days(from, to) {
+		if !free(day) {
+			continue
+		}
+		date, _ := time.ParseInLocation(dateLayout, day.Date, c.Location)
+		dayEnd := date.Add(time.Duration(day.EndMinute) * time.Minute)
+		for start := date.Add(time.Duration(day.StartMinute) * time.Minute); !start.Add(duration).After(dayEnd); start = start.Add(duration) {
+			slot := Period{Start: start, End: start.Add(duration)}
+			if slot.Start.Before(now) || slot.Start.Before(from) || slot.End.After(to.Add(24*time.Hour)) {
+				continue
+			}
+			if c.booked(slot) {
+				continue
+			}
+			slots = append(slots, slot)
+		}
+	}
+	return slots
+}
+
+func (c *Calendar) booked(slot Period) bool {
+	for _, b := range c.Booked {
+		if slot.Start.Before(b.End) && b.Start.Before(slot.End) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Calendar) activeOn(date string) int {
+	n := 0
+	for _, b := range c.Busy {
+		if b.StartsOn <= date && b.EndsOn >= date {
+			n++
+		}
+	}
+	return n
+}
+
+// Warning - предупреждение клиенту перед назначением мастера
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Check проверяет, сможет ли мастер взять проект на период [start, end]
+func Check(db *gorm.DB, master models.Master, start, end time.Time) ([]Warning, error) {
+	if end.Before(start) {
+		end = start
+	}
+	cal, err := Load(db, master, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return cal.Warnings(start, end), nil
+}
+
+// Warnings - предупреждения по календарю на период [start, end]
+func (c *Calendar) Warnings(start, end time.Time) []Warning {
+	warnings := []Warning{}
+	maxActive := 0
+	for _, day := range c.Days(start, end) {
+		if day.ActiveProjects > maxActive {
+			maxActive = day.ActiveProjects
+		}
+	}
+	if maxActive >= c.MaxConcurrent {
+		warnings = append(warnings, Warning{
+			Code:    "at_capacity",
+			Message: fmt.Sprintf("В эти даты мастер уже ведёт %d из %d проектов", maxActive, c.MaxConcurrent),
+		})
+	}
+	fromDate := start.In(c.Location).Format(dateLayout)
+	toDate := end.In(c.Location).Format(dateLayout)
+	for _, off := range c.TimeOff {
+		if off.StartsOn.Format(dateLayout) > toDate || off.EndsOn.Format(dateLayout) < fromDate {
+			continue
+		}
+		what := "выходной"
+		if off.Kind == Vacation {
+			what = "отпуск"
+		}
+		warnings = append(warnings, Warning{
+			Code: "time_off",
+			Message: fmt.Sprintf("У мастера %s с %s по %s", what,
+				off.StartsOn.Format("02.01.2006"), off.EndsOn.Format("02.01.2006")),
+		})
+	}
+	return warnings
+}
+
+// Summary - доступность мастера для профиля и каталога
+type Summary struct {
+	MaxConcurrentProjects int     `json:"maxConcurrentProjects"`
+	ActiveProjects        int     `json:"activeProjects"`
+	AvailableToday        bool    `json:"availableToday"`
+	TimeOffUntil          *string `json:"timeOffUntil,omitempty"`
+	NextFreeDate          *string `json:"nextFreeDate,omitempty"`
+}
+
+// summaryHorizon - на сколько дней вперёд ищется ближайший свободный день
+const summaryHorizon = 90
+
+// Summarize считает доступность мастера на сегодня и ближайший свободный день
+func Summarize(db *gorm.DB, master models.Master) (*Summary, error) {
+	now := time.Now()
+	to := now.AddDate(0, 0, summaryHorizon)
+	cal, err := Load(db, master, now, to)
+	if err != nil {
+		return nil, err
+	}
+	return cal.Summary(now, to), nil
+}
+
+// Summary - доступность на день from и ближайший свободный день до to
+func (c *Calendar) Summary(from, to time.Time) *Summary {
+	summary := &Summary{MaxConcurrentProjects: c.MaxConcurrent}
+	days := c.Days(from, to)
+	if len(days) == 0 {
+		return summary
+	}
+	summary.ActiveProjects = days[0].ActiveProjects
+	summary.AvailableToday = c.Free(days[0])
+	if days[0].TimeOff != "" {
+		for _, off := range c.TimeOff {
+			if off.StartsOn.Format(dateLayout) <= days[0].Date && off.EndsOn.Format(dateLayout) >= days[0].Date {
+				until := off.EndsOn.Format(dateLayout)
+				summary.TimeOffUntil = &until
+				break
+			}
+		}
+	}
+	for _, day := range days {
+		if c.Free(day) {
+			date := day.Date
+			summary.NextFreeDate = &date
+			break
+		}
+	}
+	return summary
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// isoWeekday - 1 понедельник ... 7 воскресенье
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
@@ -0,0 +1,194 @@
+package availability
+
+import (
+	"testing"
+	"time"
+
+	"refurnish/internal/models"
+)
+
+// testCalendar - график будни 09:00-18:00, до двух проектов одновременно.
+// Возвращает понедельник недели, которая гарантированно в будущем.
+func testCalendar(t *testing.T) (*Calendar, time.Time) {
+	t.Helper()
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	monday := startOfDay(time.Now().In(loc).AddDate(0, 0, 14))
+	for isoWeekday(monday) != 1 {
+		monday = monday.AddDate(0, 0, 1)
+	}
+	return &Calendar{Location: loc, MaxConcurrent: 2, Hours: DefaultHours}, monday
+}
+
+func at(day time.Time, hour, minute int) time.Time {
+	return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func date(day time.Time, offset int) string {
+	return day.AddDate(0, 0, offset).Format(dateLayout)
+}
+
+func TestDays(t *testing.T) {
+	cal, monday := testCalendar(t)
+	cal.TimeOff = []models.TimeOff{{Kind: Vacation, StartsOn: monday.AddDate(0, 0, 2), EndsOn: monday.AddDate(0, 0, 3)}}
+	cal.Busy = []Busy{{ProjectID: "p1", StartsOn: date(monday, 0), EndsOn: date(monday, 1)}}
+
+	days := cal.Days(monday, monday.AddDate(0, 0, 6))
+	if len(days) != 7 {
+		t.Fatalf("дней %d, want 7", len(days))
+	}
+	if d := days[0]; !d.Working || d.StartMinute != 540 || d.EndMinute != 1080 || d.ActiveProjects != 1 {
+		t.Errorf("понедельник: %+v", d)
+	}
+	if days[2].TimeOff != Vacation || days[3].TimeOff != Vacation || days[4].TimeOff != "" {
+		t.Errorf("отпуск ср-чт: %q %q %q", days[2].TimeOff, days[3].TimeOff, days[4].TimeOff)
+	}
+	if days[2].ActiveProjects != 0 {
+		t.Errorf("проект закончился во вторник, а в среду активных %d", days[2].ActiveProjects)
+	}
+	if days[5].Working || days[6].Working {
+		t.Error("выходные по графику отмечены рабочими")
+	}
+	if got := cal.Days(monday.AddDate(0, 0, 1), monday); len(got) != 0 {
+		t.Errorf("пустой период: %d дней", len(got))
+	}
+}
+
+func TestFree(t *testing.T) {
+	cal, monday := testCalendar(t)
+	cal.TimeOff = []models.TimeOff{{Kind: DayOff, StartsOn: monday.AddDate(0, 0, 1), EndsOn: monday.AddDate(0, 0, 1)}}
+	cal.Busy = []Busy{
+		{ProjectID: "p1", StartsOn: date(monday, 2), EndsOn: date(monday, 3)},
+		{ProjectID: "p2", StartsOn: date(monday, 3), EndsOn: date(monday, 3)},
+	}
+
+	days := cal.Days(monday, monday.AddDate(0, 0, 5))
+	want := []bool{true, false, true, false, true, false}
+	for i, day := range days {
+		if got := cal.Free(day); got != want[i] {
+			t.Errorf("%s: Free = %v, want %v (%+v)", day.Date, got, want[i], day)
+		}
+	}
+	// На замер можно и в загруженный день, но не в выходной
+	if !cal.Open(days[3]) || cal.Open(days[1]) || cal.Open(days[5]) {
+		t.Error("Open: загрузка проектами не должна закрывать день, выходные - должны")
+	}
+}
+
+func TestSlots(t *testing.T) {
+	cal, monday := testCalendar(t)
+	cal.Booked = []Period{{Start: at(monday, 10, 30), End: at(monday, 11, 30)}}
+
+	slots := cal.Slots(monday, monday, time.Hour)
+	// 9 окон с 09:00 до 18:00 минус два пересекающихся с 10:30-11:30
+	if len(slots) != 7 {
+		t.Fatalf("окон %d, want 7: %v", len(slots), slots)
+	}
+	if !slots[0].Start.Equal(at(monday, 9, 0)) || !slots[1].Start.Equal(at(monday, 11, 0).Add(time.Hour)) {
+		t.Errorf("окна: %v, %v", slots[0].Start, slots[1].Start)
+	}
+	if last := slots[len(slots)-1]; !last.End.Equal(at(monday, 18, 0)) {
+		t.Errorf("последнее окно заканчивается в %v", last.End)
+	}
+
+	// Мастер загружен - новых проектов нет, но на замер выехать может
+	cal.Busy = []Busy{
+		{ProjectID: "p1", StartsOn: date(monday, 0), EndsOn: date(monday, 0)},
+		{ProjectID: "p2", StartsOn: date(monday, 0), EndsOn: date(monday, 0)},
+	}
+	if got := cal.Slots(monday, monday, time.Hour); len(got) != 0 {
+		t.Errorf("Slots в загруженный день: %d", len(got))
+	}
+	if got := cal.VisitSlots(monday, monday, time.Hour); len(got) != 7 {
+		t.Errorf("VisitSlots в загруженный день: %d, want 7", len(got))
+	}
+
+	// Прошедшее время не предлагается
+	past := startOfDay(time.Now().In(cal.Location).AddDate(0, 0, -7))
+	if got := cal.VisitSlots(past, past.AddDate(0, 0, 1), time.Hour); len(got) != 0 {
+		t.Errorf("окна в прошлом: %v", got)
+	}
+}
+
+func TestFits(t *testing.T) {
+	cal, monday := testCalendar(t)
+	cal.Booked = []Period{{Start: at(monday, 12, 0), End: at(monday, 13, 0)}}
+	cal.TimeOff = []models.TimeOff{{Kind: DayOff, StartsOn: monday.AddDate(0, 0, 1), EndsOn: monday.AddDate(0, 0, 1)}}
+	tuesday := monday.AddDate(0, 0, 1)
+	saturday := monday.AddDate(0, 0, 5)
+
+	tests := []struct {
+		name string
+		p    Period
+		want bool
+	}{
+		{"в рабочие часы", Period{at(monday, 9, 0), at(monday, 10, 0)}, true},
+		{"вплотную к занятому", Period{at(monday, 11, 0), at(monday, 12, 0)}, true},
+		{"до конца дня", Period{at(monday, 17, 0), at(monday, 18, 0)}, true},
+		{"раньше начала", Period{at(monday, 8, 30), at(monday, 9, 30)}, false},
+		{"позже конца", Period{at(monday, 17, 30), at(monday, 18, 30)}, false},
+		{"пересекает занятое", Period{at(monday, 12, 30), at(monday, 13, 30)}, false},
+		{"через полночь", Period{at(monday, 17, 0), at(tuesday, 10, 0)}, false},
+		{"пустой интервал", Period{at(monday, 10, 0), at(monday, 10, 0)}, false},
+		{"выходной по заявке", Period{at(tuesday, 10, 0), at(tuesday, 11, 0)}, false},
+		{"суббота", Period{at(saturday, 10, 0), at(saturday, 11, 0)}, false},
+	}
+	for _, tt := range tests {
+		if got := cal.Fits(tt.p); got != tt.want {
+			t.Errorf("%s: Fits = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	cal, monday := testCalendar(t)
+	friday := monday.AddDate(0, 0, 4)
+
+	if got := cal.Warnings(monday, friday); len(got) != 0 {
+		t.Errorf("свободный мастер: %v", got)
+	}
+
+	cal.Busy = []Busy{
+		{ProjectID: "p1", StartsOn: date(monday, 0), EndsOn: date(monday, 4)},
+		{ProjectID: "p2", StartsOn: date(monday, 3), EndsOn: date(monday, 3)},
+	}
+	cal.TimeOff = []models.TimeOff{
+		{Kind: Vacation, StartsOn: monday.AddDate(0, 0, 1), EndsOn: monday.AddDate(0, 0, 2)},
+		{Kind: DayOff, StartsOn: monday.AddDate(0, 0, 10), EndsOn: monday.AddDate(0, 0, 10)},
+	}
+	got := cal.Warnings(monday, friday)
+	if len(got) != 2 || got[0].Code != "at_capacity" || got[1].Code != "time_off" {
+		t.Fatalf("предупреждения: %+v", got)
+	}
+
+	// До четверга мастер ведёт один проект из двух - места хватает
+	if got := cal.Warnings(monday, monday); len(got) != 0 {
+		t.Errorf("понедельник: %+v", got)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	cal, monday := testCalendar(t)
+	cal.MaxConcurrent = 1
+	cal.TimeOff = []models.TimeOff{{Kind: Vacation, StartsOn: monday, EndsOn: monday.AddDate(0, 0, 1)}}
+	cal.Busy = []Busy{{ProjectID: "p1", StartsOn: date(monday, 0), EndsOn: date(monday, 2)}}
+
+	s := cal.Summary(monday, monday.AddDate(0, 0, 14))
+	if s.AvailableToday || s.ActiveProjects != 1 || s.MaxConcurrentProjects != 1 {
+		t.Errorf("сегодня: %+v", s)
+	}
+	if s.TimeOffUntil == nil || *s.TimeOffUntil != date(monday, 1) {
+		t.Errorf("TimeOffUntil = %v, want %s", s.TimeOffUntil, date(monday, 1))
+	}
+	if s.NextFreeDate == nil || *s.NextFreeDate != date(monday, 3) {
+		t.Errorf("NextFreeDate = %v, want %s", s.NextFreeDate, date(monday, 3))
+	}
+
+	// Пустой период не должен ронять расчёт
+	empty := cal.Summary(monday.AddDate(0, 0, 1), monday)
+	if empty.AvailableToday || empty.NextFreeDate != nil || empty.MaxConcurrentProjects != 1 {
+		t.Errorf("пустой период: %+v", empty)
+	}
+}
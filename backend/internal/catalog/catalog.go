@@ -0,0 +1,99 @@
+// internal/catalog/catalog.go
+package catalog
+
+import (
+	"errors"
+	"strings"
+
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrUnknownCategory - значение не найдено среди активных категорий
+var ErrUnknownCategory = errors.New("unknown category")
+
+// Resolve находит активную категорию по slug или локализованному названию
+func Resolve(db *gorm.DB, value string) (*models.Category, error) {
+	v := strings.ToLower(strings.TrimSpace(value))
+	if v == "" {
+		return nil, ErrUnknownCategory
+	}
+
+	var category models.Category
+	err := db.Where("is_active AND (slug = ? OR lower(name_ru) = ? OR lower(name_en) = ?)", v, v, v).
+		First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUnknownCategory
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// ResolveSlugs проверяет список значений и возвращает уникальные slug'и категорий
+func ResolveSlugs(db *gorm.DB, values []string) ([]string, error) {
+	seen := make(map[string]bool)
+	slugs := []string{}
+	for _, v := range values {
+		category, err := Resolve(db, v)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[category.Slug] {
+			seen[category.Slug] = true
+			slugs = append(slugs, category.Slug)
+		}
+	}
+	return slugs, nil
+}
+
+// SubtreeIDs возвращает ID категории и всех её потомков
+func SubtreeIDs(db *gorm.DB, categoryID string) ([]string, error) {
+	var ids []string
+	err := db.Raw(`
+		WITH RECURSIVE tree AS (
+			SELECT id FROM categories WHERE id = ?
+			UNION ALL
+			SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+		)
+		SELECT id FROM tree
+	`, categoryID).Scan(&ids).Error
+	return ids, err
+}
+
+// RelatedSlugs возвращает slug'и категории, её предков и потомков.
+// Мастер со специализацией "upholstery" подходит для "sofas-leather" и наоборот.
+func RelatedSlugs(db *gorm.DB, categoryID string) ([]string, error) {
+	var slugs []string
+	err := db.Raw(`
+		WITH RECURSIVE up AS (
+			SELECT id, parent_id, slug FROM categories WHERE id = ?
+			UNION ALL
+			SELECT c.id, c.parent_id, c.slug FROM categories c JOIN up u ON c.id = u.parent_id
+		), down AS (
+			SELECT id, slug FROM categories WHERE id = ?
+			UNION ALL
+			SELECT c.id, c.slug FROM categories c JOIN down d ON c.parent_id = d.id
+		)
+		SELECT slug FROM up
+		UNION
+		SELECT slug FROM down
+	`, categoryID, categoryID).Scan(&slugs).Error
+	return slugs, err
+}
+
+// IsDescendant сообщает, лежит ли candidateID в поддереве ancestorID (включая сам узел)
+func IsDescendant(db *gorm.DB, ancestorID, candidateID string) (bool, error) {
+	ids, err := SubtreeIDs(db, ancestorID)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range ids {
+		if id == candidateID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,156 @@
+// internal/cities/cities.go
+package cities
+
+import (
+	"embed"
+	"encoding/json"
+	"log"
+	"math"
+	"strings"
+
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:embed data/cities.json
+var dataFS embed.FS
+
+type cityRecord struct {
+	Name       string   `json:"name"`
+	Region     string   `json:"region"`
+	Timezone   string   `json:"timezone"`
+	Lat        float64  `json:"lat"`
+	Lon        float64  `json:"lon"`
+	Population int      `json:"population"`
+	Aliases    []string `json:"aliases"`
+}
+
+// Seed загружает встроенный справочник городов в таблицу cities.
+// Повторный вызов обновляет существующие записи по названию.
+func Seed(db *gorm.DB) error {
+	raw, err := dataFS.ReadFile("data/cities.json")
+	if err != nil {
+		return err
+	}
+
+	var records []cityRecord
+	if err := json.Unmarshal(raw, &records); err != nil {
+		return err
+	}
+
+	cities := make([]models.City, 0, len(records))
+	for _, rec := range records {
+		cities = append(cities, models.City{
+			Name:       rec.Name,
+			Region:     rec.Region,
+			Timezone:   rec.Timezone,
+			Latitude:   rec.Lat,
+			Longitude:  rec.Lon,
+			Population: rec.Population,
+			Aliases:    rec.Aliases,
+			SearchKeys: searchKeys(rec.Name, rec.Aliases),
+		})
+	}
+
+	err = db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"region", "timezone", "latitude", "longitude", "population", "aliases", "search_keys",
+		}),
+	}).Create(&cities).Error
+	if err != nil {
+		return err
+	}
+
+	log.Printf("🏙️  Справочник городов загружен: %d записей", len(cities))
+	return nil
+}
+
+// Key приводит название города к виду для сравнения:
+// нижний регистр, ё→е, без префикса "г.", дефисы и лишние пробелы схлопнуты.
+func Key(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.ReplaceAll(s, "ё", "е")
+	for _, prefix := range []string{"г.", "город "} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	s = strings.NewReplacer("-", " ", ".", " ", ",", " ").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Normalize возвращает каноническое название города из справочника.
+// Если город не найден, возвращается исходная строка без лишних пробелов.
+func Normalize(db *gorm.DB, input string) string {
+	trimmed := strings.TrimSpace(input)
+	key := Key(trimmed)
+	if key == "" {
+		return ""
+	}
+
+	var city models.City
+	if err := db.Where("? = ANY(search_keys)", key).
+		Order("population DESC").
+		First(&city).Error; err != nil {
+		return trimmed
+	}
+	return city.Name
+}
+
+// Search ищет города, у которых название или алиас начинается с prefix
+func Search(db *gorm.DB, prefix string, limit int) ([]models.City, error) {
+	key := Key(prefix)
+
+	query := db.Model(&models.City{})
+	if key != "" {
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM unnest(search_keys) AS k WHERE k LIKE ?)",
+			escapeLike(key)+"%",
+		)
+	}
+
+	var cities []models.City
+	err := query.Order("population DESC").Limit(limit).Find(&cities).Error
+	return cities, err
+}
+
+func searchKeys(name string, aliases []string) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, v := range append([]string{name}, aliases...) {
+		k := Key(v)
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// Find возвращает город из справочника по названию или алиасу
+func Find(db *gorm.DB, name string) (*models.City, error) {
+	var city models.City
+	err := db.Where("? = ANY(search_keys)", Key(name)).Order("population DESC").First(&city).Error
+	if err != nil {
+		return nil, err
+	}
+	return &city, nil
+}
+
+// DistanceKm - расстояние между городами по большой окружности
+func DistanceKm(a, b models.City) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(b.Latitude - a.Latitude)
+	dLon := toRad(b.Longitude - a.Longitude)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(a.Latitude))*math.Cos(toRad(b.Latitude))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,84 @@
+package cities
+
+import (
+	"os"
+	"testing"
+
+	"refurnish/internal/models"
+	"refurnish/internal/testdb"
+)
+
+// Алиасы и ключи поиска - text[]: справочник должен читаться обратно
+func TestSeedFindRoundTrip(t *testing.T) {
+	db := testdb.Open(t)
+	if err := Seed(db); err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+
+	city, err := Find(db, "Питер")
+	if err != nil {
+		t.Fatalf("Find по алиасу: %v", err)
+	}
+	if city.Name != "Санкт-Петербург" {
+		t.Errorf("Find(Питер) = %s", city.Name)
+	}
+	if len(city.SearchKeys) == 0 || city.SearchKeys[0] != Key(city.Name) {
+		t.Errorf("ключи поиска не прочитались: %q", city.SearchKeys)
+	}
+	if city.Timezone == "" {
+		t.Error("часовой пояс не прочитался")
+	}
+
+	if got := Normalize(db, "  г. москва "); got != "Москва" {
+		t.Errorf("Normalize = %q", got)
+	}
+
+	found, err := Search(db, "екат", 5)
+	if err != nil || len(found) == 0 || found[0].Name != "Екатеринбург" {
+		t.Errorf("Search(екат) = %v, %v", found, err)
+	}
+
+	var stored models.City
+	if err := db.First(&stored, "name = ?", "Санкт-Петербург").Error; err != nil {
+		t.Fatal(err)
+	}
+	stored.Aliases = append(stored.Aliases, `с "кавычками", и запятой`)
+	if err := db.Save(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	var reread models.City
+	db.First(&reread, "id = ?", stored.ID)
+	if len(reread.Aliases) != len(stored.Aliases) || reread.Aliases[len(reread.Aliases)-1] != `с "кавычками", и запятой` {
+		t.Errorf("алиасы после сохранения: %q", reread.Aliases)
+	}
+}
+
+// Миграция 032 приводит к справочнику города, сохранённые до него
+func TestNormalizeMigration(t *testing.T) {
+	db := testdb.Open(t)
+	if err := Seed(db); err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+	clientID, _ := testdb.Client(t, db)
+	projects := map[string]string{
+		testdb.Project(t, db, clientID, map[string]interface{}{"city": "г. москва"}): "Москва",
+		testdb.Project(t, db, clientID, map[string]interface{}{"city": "Питер"}):     "Санкт-Петербург",
+		testdb.Project(t, db, clientID, map[string]interface{}{"city": "Урюпинск"}):  "Урюпинск",
+	}
+
+	sql, err := os.ReadFile("../../db/migrations/032_normalize_cities.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec(string(sql)).Error; err != nil {
+		t.Fatalf("миграция: %v", err)
+	}
+
+	for id, want := range projects {
+		var city string
+		db.Raw("SELECT city FROM projects WHERE id = ?", id).Scan(&city)
+		if city != want {
+			t.Errorf("город проекта = %q, want %q", city, want)
+		}
+	}
+}
@@ -29,7 +29,9 @@ func GetDB() *gorm.DB {
 	return db
 }
 
-func connectDB() (*gorm.DB, error) {
+// DSN - строка подключения к базе. Нужна и gorm, и отдельным
+// соединениям (например, LISTEN для realtime).
+func DSN() string {
 	// Определяем хост в зависимости от окружения
 	host := getDBHost()
 	port := "5432"
@@ -38,12 +40,14 @@ func connectDB() (*gorm.DB, error) {
 	dbname := "refurnish"
 	sslmode := "disable"
 
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		host, port, user, password, dbname, sslmode)
+}
 
-	log.Printf("Connecting to database: %s@%s:%s/%s", user, host, port, dbname)
+func connectDB() (*gorm.DB, error) {
+	log.Printf("Connecting to database: postgres@%s:5432/refurnish", getDBHost())
 
-	return gorm.Open(postgres.Open(dsn), &gorm.Config{
+	return gorm.Open(postgres.Open(DSN()), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
 }
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListingLifetime - сколько проект может висеть опубликованным
+// (PROJECT_LISTING_DAYS, по умолчанию 30 дней)
+func ListingLifetime() time.Duration {
+	return time.Duration(envInt("PROJECT_LISTING_DAYS", 30)) * 24 * time.Hour
+}
+
+// ReviewRevealWindow - через сколько после завершения проекта отзыв
+// открывается, даже если вторая сторона свой не оставила. REVIEW_REVEAL_DAYS
+func ReviewRevealWindow() time.Duration {
+	return time.Duration(envInt("REVIEW_REVEAL_DAYS", 14)) * 24 * time.Hour
+}
+
+func envInt(name string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(name)); err == nil && v > 0 {
+		return v
+	}
+	return fallback
+}
+
+// RealtimeBackend - шина realtime-событий: "postgres" (LISTEN/NOTIFY,
+// несколько экземпляров) или "memory" (один экземпляр). REALTIME_BACKEND
+func RealtimeBackend() string {
+	if v := os.Getenv("REALTIME_BACKEND"); v == "memory" {
+		return v
+	}
+	return "postgres"
+}
+
+// PublicURL - адрес фронтенда для ссылок в письмах и Telegram (PUBLIC_URL)
+func PublicURL() string {
+	if v := os.Getenv("PUBLIC_URL"); v != "" {
+		return strings.TrimRight(v, "/")
+	}
+	return "http://localhost:5173"
+}
+
+// TrustedProxies - адреса reverse proxy (IP или CIDR через запятую), которым
+// можно верить в X-Forwarded-For и X-Real-IP. TRUSTED_PROXIES; по умолчанию
+// пусто - заголовки игнорируются и клиентом считается адрес соединения
+func TrustedProxies() []string {
+	var proxies []string
+	for _, v := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			proxies = append(proxies, v)
+		}
+	}
+	return proxies
+}
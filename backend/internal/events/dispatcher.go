@@ -0,0 +1,169 @@
+// internal/events/dispatcher.go
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// lease - сколько событие закреплено за экземпляром. Если процесс
+	// упал посреди обработки, после lease событие заберёт другой.
+	lease = time.Minute
+	// maxAttempts - после стольких неудач событие помечается failed
+	maxAttempts = 10
+	batchSize   = 100
+	pollEvery   = 5 * time.Second
+)
+
+// Run раздаёт события подписчикам, пока не отменён ctx
+func Run(ctx context.Context, db *gorm.DB) {
+	log.Printf("📤 Диспетчер событий запущен")
+
+	ticker := time.NewTicker(pollEvery)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := DispatchBatch(ctx, db)
+			if err != nil {
+				log.Printf("❌ Диспетчер событий: %v", err)
+				break
+			}
+			if n < batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-kick:
+		}
+	}
+}
+
+// DispatchBatch забирает пачку готовых событий и раздаёт подписчикам.
+// Возвращает число обработанных событий.
+func DispatchBatch(ctx context.Context, db *gorm.DB) (int, error) {
+	var batch []models.OutboxEvent
+	err := db.WithContext(ctx).Raw(`
+		UPDATE outbox SET locked_until = ?
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE dispatched_at IS NULL AND failed_at IS NULL
+			  AND next_attempt_at <= now()
+			  AND (locked_until IS NULL OR locked_until < now())
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, time.Now().Add(lease), batchSize).Scan(&batch).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, row := range batch {
+		dispatch(ctx, db, row)
+	}
+	return len(batch), nil
+}
+
+func dispatch(ctx context.Context, db *gorm.DB, row models.OutboxEvent) {
+	event := Event{
+		ID:          row.ID,
+		Type:        row.EventType,
+		AggregateID: row.AggregateID,
+		CreatedAt:   row.CreatedAt,
+	}
+	if err := json.Unmarshal(row.Payload, &event.Payload); err != nil {
+		markFailed(db, row, fmt.Errorf("неверный payload: %w", err))
+		return
+	}
+
+	var done []string
+	db.Table("outbox_consumers").Where("event_id = ?", row.ID).Pluck("subscriber", &done)
+	processed := make(map[string]bool, len(done))
+	for _, name := range done {
+		processed[name] = true
+	}
+
+	var failures []string
+	for _, s := range subscribersFor(row.EventType) {
+		if processed[s.name] {
+			continue
+		}
+		if err := call(ctx, db, s, event); err != nil {
+			log.Printf("⚠️  Подписчик %s не обработал событие %d (%s): %v", s.name, row.ID, row.EventType, err)
+			failures = append(failures, s.name+": "+err.Error())
+			continue
+		}
+		// Отметка после успешной обработки: упадём между ними - подписчик
+		// получит событие повторно, но не потеряет его
+		db.Exec(`
+			INSERT INTO outbox_consumers (event_id, subscriber) VALUES (?, ?)
+			ON CONFLICT DO NOTHING
+		`, row.ID, s.name)
+	}
+
+	if len(failures) == 0 {
+		db.Model(&models.OutboxEvent{}).Where("id = ?", row.ID).Updates(map[string]interface{}{
+			"dispatched_at": time.Now(),
+			"locked_until":  nil,
+			"last_error":    nil,
+		})
+		return
+	}
+
+	attempts := row.Attempts + 1
+	errText := strings.Join(failures, "; ")
+	if attempts >= maxAttempts {
+		markFailed(db, row, fmt.Errorf("%s", errText))
+		return
+	}
+	db.Model(&models.OutboxEvent{}).Where("id = ?", row.ID).Updates(map[string]interface{}{
+		"attempts":        attempts,
+		"last_error":      errText,
+		"locked_until":    nil,
+		"next_attempt_at": time.Now().Add(backoff(attempts)),
+	})
+}
+
+// call изолирует панику подписчика, чтобы она не уронила диспетчер
+func call(ctx context.Context, db *gorm.DB, s subscriber, e Event) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("паника: %v", p)
+		}
+	}()
+	return s.handler(ctx, db, e)
+}
+
+func markFailed(db *gorm.DB, row models.OutboxEvent, err error) {
+	log.Printf("❌ Событие %d (%s) не доставлено окончательно: %v", row.ID, row.EventType, err)
+	db.Model(&models.OutboxEvent{}).Where("id = ?", row.ID).Updates(map[string]interface{}{
+		"attempts":     row.Attempts + 1,
+		"last_error":   err.Error(),
+		"locked_until": nil,
+		"failed_at":    time.Now(),
+	})
+}
+
+// backoff - 10с, 20с, 40с ... но не больше часа
+func backoff(attempts int) time.Duration {
+	d := 10 * time.Second << (attempts - 1)
+	if d > time.Hour || d <= 0 {
+		return time.Hour
+	}
+	return d
+}
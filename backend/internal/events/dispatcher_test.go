@@ -0,0 +1,207 @@
+package events
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"refurnish/internal/models"
+	"refurnish/internal/testdb"
+
+	"gorm.io/gorm"
+)
+
+// calls считает вызовы подписчиков из тестов
+type calls struct {
+	mu sync.Mutex
+	n  map[string]int
+}
+
+func (c *calls) add(name string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.n == nil {
+		c.n = map[string]int{}
+	}
+	c.n[name]++
+	return c.n[name]
+}
+
+func (c *calls) get(name string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n[name]
+}
+
+// dispatchUntilExit вызывает DispatchBatch в отдельной горутине: подписчик
+// может завершить её через runtime.Goexit, как будто процесс упал
+func dispatchUntilExit(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		DispatchBatch(context.Background(), db)
+	}()
+	<-done
+}
+
+func emit(t *testing.T, db *gorm.DB, eventType string) models.OutboxEvent {
+	t.Helper()
+	if err := Emit(db, eventType, "aggregate-1", map[string]interface{}{"k": "v"}); err != nil {
+		t.Fatal(err)
+	}
+	var row models.OutboxEvent
+	if err := db.Where("event_type = ?", eventType).Last(&row).Error; err != nil {
+		t.Fatal(err)
+	}
+	return row
+}
+
+func reload(t *testing.T, db *gorm.DB, id int64) models.OutboxEvent {
+	t.Helper()
+	var row models.OutboxEvent
+	if err := db.First(&row, "id = ?", id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return row
+}
+
+func consumers(t *testing.T, db *gorm.DB, id int64) []string {
+	t.Helper()
+	var names []string
+	db.Table("outbox_consumers").Where("event_id = ?", id).Order("subscriber").Pluck("subscriber", &names)
+	return names
+}
+
+func TestDispatchBatchRedeliversAfterCrash(t *testing.T) {
+	db := testdb.Open(t)
+	const eventType = "test.crash"
+
+	var c calls
+	Subscribe("crash-first", func(ctx context.Context, db *gorm.DB, e Event) error {
+		c.add("crash-first")
+		return nil
+	}, eventType)
+	Subscribe("crash-second", func(ctx context.Context, db *gorm.DB, e Event) error {
+		// Первый вызов: подписчик отработал, но процесс "упал" до записи
+		// в outbox_consumers - горутина диспетчера просто прекращается
+		if c.add("crash-second") == 1 {
+			runtime.Goexit()
+		}
+		return nil
+	}, eventType)
+
+	row := emit(t, db, eventType)
+	dispatchUntilExit(t, db)
+
+	if got := consumers(t, db, row.ID); strings.Join(got, ",") != "crash-first" {
+		t.Fatalf("после падения отмечены %v, want [crash-first]", got)
+	}
+	if row = reload(t, db, row.ID); row.LockedUntil == nil || row.DispatchedAt != nil {
+		t.Fatalf("событие должно остаться закреплённым и недоставленным: %+v", row)
+	}
+
+	// Пока lease не истёк, другой экземпляр событие не берёт
+	if n, err := DispatchBatch(context.Background(), db); err != nil || n != 0 {
+		t.Fatalf("DispatchBatch во время lease = %d, %v; want 0", n, err)
+	}
+
+	db.Exec("UPDATE outbox SET locked_until = now() - interval '1 second' WHERE id = ?", row.ID)
+
+	if n, err := DispatchBatch(context.Background(), db); err != nil || n != 1 {
+		t.Fatalf("DispatchBatch после lease = %d, %v; want 1", n, err)
+	}
+	if got := c.get("crash-first"); got != 1 {
+		t.Errorf("crash-first вызван %d раз, want 1: уже отмеченный подписчик пропускается", got)
+	}
+	if got := c.get("crash-second"); got != 2 {
+		t.Errorf("crash-second вызван %d раз, want 2", got)
+	}
+	if got := consumers(t, db, row.ID); strings.Join(got, ",") != "crash-first,crash-second" {
+		t.Errorf("отмечены %v", got)
+	}
+	if row = reload(t, db, row.ID); row.DispatchedAt == nil || row.LockedUntil != nil {
+		t.Errorf("событие не помечено доставленным: %+v", row)
+	}
+}
+
+func TestDispatchBatchSkipsRecordedSubscribers(t *testing.T) {
+	db := testdb.Open(t)
+	const eventType = "test.recorded"
+
+	var c calls
+	for _, name := range []string{"recorded-done", "recorded-new"} {
+		name := name
+		Subscribe(name, func(ctx context.Context, db *gorm.DB, e Event) error {
+			c.add(name)
+			return nil
+		}, eventType)
+	}
+
+	row := emit(t, db, eventType)
+	db.Exec("INSERT INTO outbox_consumers (event_id, subscriber) VALUES (?, ?)", row.ID, "recorded-done")
+
+	if n, err := DispatchBatch(context.Background(), db); err != nil || n != 1 {
+		t.Fatalf("DispatchBatch = %d, %v; want 1", n, err)
+	}
+	if got := c.get("recorded-done"); got != 0 {
+		t.Errorf("recorded-done вызван %d раз, want 0", got)
+	}
+	if got := c.get("recorded-new"); got != 1 {
+		t.Errorf("recorded-new вызван %d раз, want 1", got)
+	}
+	if row = reload(t, db, row.ID); row.DispatchedAt == nil {
+		t.Error("событие не помечено доставленным")
+	}
+}
+
+func TestDispatchBatchRetriesPanickingSubscriber(t *testing.T) {
+	db := testdb.Open(t)
+	const eventType = "test.panic"
+
+	var c calls
+	Subscribe("panic-ok", func(ctx context.Context, db *gorm.DB, e Event) error {
+		c.add("panic-ok")
+		return nil
+	}, eventType)
+	Subscribe("panic-once", func(ctx context.Context, db *gorm.DB, e Event) error {
+		if c.add("panic-once") == 1 {
+			panic("сбой подписчика")
+		}
+		return nil
+	}, eventType)
+
+	row := emit(t, db, eventType)
+	if n, err := DispatchBatch(context.Background(), db); err != nil || n != 1 {
+		t.Fatalf("DispatchBatch = %d, %v; want 1", n, err)
+	}
+
+	row = reload(t, db, row.ID)
+	if row.Attempts != 1 || row.LastError == nil || !strings.Contains(*row.LastError, "паника") {
+		t.Fatalf("после паники attempts=%d last_error=%v", row.Attempts, row.LastError)
+	}
+	if row.DispatchedAt != nil || row.FailedAt != nil || row.LockedUntil != nil {
+		t.Fatalf("событие должно ждать повтора: %+v", row)
+	}
+
+	// Повтор отложен на backoff
+	if n, _ := DispatchBatch(context.Background(), db); n != 0 {
+		t.Fatalf("повтор до next_attempt_at: %d событий", n)
+	}
+	db.Exec("UPDATE outbox SET next_attempt_at = now() - interval '1 second' WHERE id = ?", row.ID)
+
+	if n, err := DispatchBatch(context.Background(), db); err != nil || n != 1 {
+		t.Fatalf("повторный DispatchBatch = %d, %v; want 1", n, err)
+	}
+	if got := c.get("panic-ok"); got != 1 {
+		t.Errorf("panic-ok вызван %d раз, want 1", got)
+	}
+	if got := c.get("panic-once"); got != 2 {
+		t.Errorf("panic-once вызван %d раз, want 2", got)
+	}
+	if row = reload(t, db, row.ID); row.DispatchedAt == nil || row.LastError != nil {
+		t.Errorf("событие не доставлено после повтора: %+v", row)
+	}
+}
@@ -0,0 +1,132 @@
+// internal/events/events.go
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Доменные события
+const (
+	ProjectCreated   = "project.created"
+	ProjectPublished = "project.published"
+	ProjectExpired   = "project.expired"
+	ResponseCreated  = "response.created"
+	MasterAssigned   = "project.assigned"
+	ProjectCompleted = "project.completed"
+	ReviewCreated    = "review.created"
+	// ClientReviewCreated - мастер оставил отзыв о клиенте
+	ClientReviewCreated = "client_review.created"
+	// Приглашение мастера в проект и ответ на него
+	InvitationCreated  = "invitation.created"
+	InvitationAnswered = "invitation.answered"
+	// Выезд мастера на замер
+	AppointmentProposed    = "appointment.proposed"
+	AppointmentConfirmed   = "appointment.confirmed"
+	AppointmentRescheduled = "appointment.rescheduled"
+	AppointmentCancelled   = "appointment.cancelled"
+	AppointmentReminder    = "appointment.reminder"
+)
+
+// Event - событие, переданное подписчику
+type Event struct {
+	ID          int64
+	Type        string
+	AggregateID string
+	Payload     map[string]interface{}
+	CreatedAt   time.Time
+}
+
+// String / Int - поля payload без паники на отсутствующих значениях
+func (e Event) String(key string) string {
+	s, _ := e.Payload[key].(string)
+	return s
+}
+
+func (e Event) Int(key string) int {
+	switch v := e.Payload[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+// Handler обрабатывает событие. Доставка "хотя бы один раз":
+// обработчик может быть вызван повторно и должен это переносить.
+type Handler func(ctx context.Context, db *gorm.DB, e Event) error
+
+type subscriber struct {
+	name    string
+	types   map[string]bool
+	handler Handler
+}
+
+var (
+	mu          sync.RWMutex
+	subscribers []subscriber
+	kick        = make(chan struct{}, 1)
+)
+
+// Subscribe регистрирует подписчика. name - стабильный идентификатор:
+// по нему запоминается, какие события уже обработаны.
+func Subscribe(name string, handler Handler, types ...string) {
+	set := make(map[string]bool, len(types))
+	for _, t := range types {
+		set[t] = true
+	}
+	mu.Lock()
+	subscribers = append(subscribers, subscriber{name: name, types: set, handler: handler})
+	mu.Unlock()
+}
+
+func subscribersFor(eventType string) []subscriber {
+	mu.RLock()
+	defer mu.RUnlock()
+	var result []subscriber
+	for _, s := range subscribers {
+		if s.types[eventType] {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// Emit записывает событие в outbox. tx должен быть транзакцией,
+// в которой меняется состояние, иначе теряется атомарность.
+func Emit(tx *gorm.DB, eventType, aggregateID string, payload map[string]interface{}) error {
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	event := models.OutboxEvent{
+		EventType:   eventType,
+		AggregateID: aggregateID,
+		Payload:     models.JSONB(raw),
+	}
+	if err := tx.Create(&event).Error; err != nil {
+		return err
+	}
+	log.Printf("📤 Событие %s (%s) записано в outbox", eventType, aggregateID)
+	return nil
+}
+
+// Wake будит диспетчер, не дожидаясь очередного опроса.
+// Вызывается после коммита транзакции с Emit.
+func Wake() {
+	select {
+	case kick <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,573 @@
+// internal/handlers/appointments.go
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"refurnish/internal/appointments"
+	"refurnish/internal/availability"
+	"refurnish/internal/config"
+	"refurnish/internal/events"
+	"refurnish/internal/models"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+const maxAppointmentReasonLength = 500
+
+// slotInput - вариант времени выезда в запросе, RFC3339
+type slotInput struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// ProposeAppointment - POST /api/master/project/{id}/appointments
+// {slots: [{start, end}], address, note}. Предложить замер может мастер,
+// откликнувшийся на проект или назначенный на него.
+func ProposeAppointment(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var project models.Project
+	if err := db.Preload("Client").First(&project, "id = ?", chi.URLParam(r, "id")).Error; err != nil {
+		http.Error(w, "Проект не найден", http.StatusNotFound)
+		return
+	}
+	if project.Status != "published" && project.Status != "assigned" {
+		http.Error(w, "Замер можно назначить только по опубликованному проекту или проекту в работе", http.StatusConflict)
+		return
+	}
+	if !masterOnProject(db, &project, master.ID) {
+		http.Error(w, "Сначала откликнитесь на проект", http.StatusForbidden)
+		return
+	}
+
+	var req struct {
+		Slots   []slotInput `json:"slots"`
+		Address string      `json:"address"`
+		Note    string      `json:"note"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат данных", http.StatusBadRequest)
+		return
+	}
+	slots, ok := parseSlots(w, req.Slots)
+	if !ok {
+		return
+	}
+
+	var active int64
+	db.Model(&models.Appointment{}).
+		Where("project_id = ? AND master_id = ? AND status IN ?", project.ID, master.ID,
+			[]string{appointments.StatusProposed, appointments.StatusConfirmed}).
+		Count(&active)
+	if active > 0 {
+		http.Error(w, "Замер по проекту уже назначен. Перенесите или отмените его", http.StatusConflict)
+		return
+	}
+
+	appointment := models.Appointment{
+		ProjectID: project.ID,
+		MasterID:  master.ID,
+		Address:   strings.TrimSpace(req.Address),
+		Note:      strings.TrimSpace(req.Note),
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := appointments.Lock(tx, master.ID); err != nil {
+			return err
+		}
+		if err := appointments.CheckSlots(tx, *master, slots, ""); err != nil {
+			return err
+		}
+		if err := appointments.Propose(tx, &appointment, slots); err != nil {
+			return err
+		}
+		appointment.Project = &project
+		appointment.Master = master
+		return events.Emit(tx, events.AppointmentProposed, appointment.ID, appointments.Payload(tx, &appointment))
+	})
+	if !appointmentSaved(w, err) {
+		return
+	}
+	events.Wake()
+
+	jsonResponse(w, appointmentJSON(db, appointment))
+}
+
+// MyAppointments - GET /api/master/appointments?status=
+// Ближайшие выезды мастера и предложения, ждущие выбора клиента
+func MyAppointments(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	query := db.Preload("Project.Client").Preload("Master").
+		Preload("Slots", func(db *gorm.DB) *gorm.DB { return db.Order("starts_at") }).
+		Where("master_id = ?", master.ID)
+	switch status := r.URL.Query().Get("status"); status {
+	case "":
+		query = query.Where("(status = ? OR (status = ? AND starts_at > ?))",
+			appointments.StatusProposed, appointments.StatusConfirmed, time.Now().Add(-24*time.Hour))
+	case appointments.StatusProposed, appointments.StatusConfirmed, appointments.StatusCancelled:
+		query = query.Where("status = ?", status)
+	default:
+		http.Error(w, "status - proposed, confirmed или cancelled", http.StatusBadRequest)
+		return
+	}
+
+	var list []models.Appointment
+	if err := query.Order("starts_at NULLS LAST, created_at DESC").Limit(100).Find(&list).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	items := []map[string]interface{}{}
+	for _, appointment := range list {
+		items = append(items, appointmentJSON(db, appointment))
+	}
+	jsonResponse(w, items)
+}
+
+// MyVisitSlots - GET /api/master/appointments/slots?from=&to=&duration=
+// Свободные окна для выезда: рабочие часы открытых дней без занятых выездов
+func MyVisitSlots(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	from, to, duration, ok := slotsRange(w, r, availability.Location(db, *master))
+	if !ok {
+		return
+	}
+	cal, err := appointments.Calendar(db, *master, from, to, "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	earliest := time.Now().Add(appointments.MinLeadTime)
+	slots := []map[string]string{}
+	for _, slot := range cal.VisitSlots(from, to, time.Duration(duration)*time.Minute) {
+		if slot.Start.Before(earliest) {
+			continue
+		}
+		slots = append(slots, map[string]string{
+			"start": slot.Start.Format(time.RFC3339),
+			"end":   slot.End.Format(time.RFC3339),
+		})
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"timezone":        cal.Location.String(),
+		"durationMinutes": duration,
+		"slots":           slots,
+	})
+}
+
+// RescheduleAppointmentByMaster - POST /api/master/appointments/{id}/reschedule
+// {slots, reason}: новые варианты времени, клиент выбирает заново
+func RescheduleAppointmentByMaster(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	appointment, ok := loadMasterAppointment(w, r, db)
+	if !ok {
+		return
+	}
+	if appointment.Status == appointments.StatusCancelled {
+		http.Error(w, "Замер отменён", http.StatusConflict)
+		return
+	}
+
+	var req struct {
+		Slots  []slotInput `json:"slots"`
+		Reason string      `json:"reason"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат данных", http.StatusBadRequest)
+		return
+	}
+	reason, ok := appointmentReason(w, req.Reason)
+	if !ok {
+		return
+	}
+	slots, ok := parseSlots(w, req.Slots)
+	if !ok {
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := appointments.Lock(tx, appointment.MasterID); err != nil {
+			return err
+		}
+		if err := appointments.CheckSlots(tx, *appointment.Master, slots, appointment.ID); err != nil {
+			return err
+		}
+		if err := appointments.Reschedule(tx, appointment, slots, reason); err != nil {
+			return err
+		}
+		payload := appointments.Payload(tx, appointment)
+		payload["by"] = appointments.RoleMaster
+		payload["reason"] = reason
+		return events.Emit(tx, events.AppointmentRescheduled, appointment.ID, payload)
+	})
+	if !appointmentSaved(w, err) {
+		return
+	}
+	events.Wake()
+
+	jsonResponse(w, appointmentJSON(db, *appointment))
+}
+
+// CancelAppointmentByMaster - POST /api/master/appointments/{id}/cancel {reason}
+func CancelAppointmentByMaster(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	appointment, ok := loadMasterAppointment(w, r, db)
+	if !ok {
+		return
+	}
+	cancelAppointment(w, r, db, appointment, appointments.RoleMaster)
+}
+
+// ProjectAppointments - GET /api/client/project/{id}/appointments
+// Все выезды по проекту, включая отменённые
+func ProjectAppointments(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
+		return
+	}
+
+	var list []models.Appointment
+	err := db.Preload("Project.Client").Preload("Master").
+		Preload("Slots", func(db *gorm.DB) *gorm.DB { return db.Order("starts_at") }).
+		Where("project_id = ?", project.ID).
+		Order("created_at DESC").Find(&list).Error
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	items := []map[string]interface{}{}
+	for _, appointment := range list {
+		items = append(items, appointmentJSON(db, appointment))
+	}
+	jsonResponse(w, items)
+}
+
+// ConfirmAppointment - POST /api/client/appointments/{id}/confirm {slotId}
+func ConfirmAppointment(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	appointment, ok := loadClientAppointment(w, r, db)
+	if !ok {
+		return
+	}
+	if appointment.Status != appointments.StatusProposed {
+		http.Error(w, "Время замера уже выбрано или замер отменён", http.StatusConflict)
+		return
+	}
+
+	var req struct {
+		SlotID string `json:"slotId"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	scheduleAppointment(w, db, appointment, req.SlotID, appointments.ActionConfirmed, "")
+}
+
+// RescheduleAppointmentByClient - POST /api/client/appointments/{id}/reschedule
+// {reason, slotId}: перенос на другой из предложенных вариантов или,
+// без slotId, просьба к мастеру предложить новое время
+func RescheduleAppointmentByClient(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	appointment, ok := loadClientAppointment(w, r, db)
+	if !ok {
+		return
+	}
+	if appointment.Status != appointments.StatusConfirmed {
+		http.Error(w, "Перенести можно только назначенный замер", http.StatusConflict)
+		return
+	}
+
+	var req struct {
+		SlotID string `json:"slotId"`
+		Reason string `json:"reason"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат данных", http.StatusBadRequest)
+		return
+	}
+	reason, ok := appointmentReason(w, req.Reason)
+	if !ok {
+		return
+	}
+
+	if req.SlotID != "" {
+		scheduleAppointment(w, db, appointment, req.SlotID, appointments.ActionRescheduled, reason)
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := appointments.Reopen(tx, appointment, reason); err != nil {
+			return err
+		}
+		payload := appointments.Payload(tx, appointment)
+		payload["by"] = appointments.RoleClient
+		payload["reason"] = reason
+		return events.Emit(tx, events.AppointmentRescheduled, appointment.ID, payload)
+	})
+	if !appointmentSaved(w, err) {
+		return
+	}
+	events.Wake()
+
+	jsonResponse(w, appointmentJSON(db, *appointment))
+}
+
+// CancelAppointmentByClient - POST /api/client/appointments/{id}/cancel {reason}
+func CancelAppointmentByClient(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	appointment, ok := loadClientAppointment(w, r, db)
+	if !ok {
+		return
+	}
+	cancelAppointment(w, r, db, appointment, appointments.RoleClient)
+}
+
+// scheduleAppointment назначает выезд на один из предложенных вариантов
+func scheduleAppointment(w http.ResponseWriter, db *gorm.DB, appointment *models.Appointment, slotID, action, reason string) {
+	var slot *models.AppointmentSlot
+	for i := range appointment.Slots {
+		if appointment.Slots[i].ID == slotID {
+			slot = &appointment.Slots[i]
+			break
+		}
+	}
+	if slot == nil {
+		http.Error(w, "Выберите один из предложенных вариантов времени", http.StatusBadRequest)
+		return
+	}
+	if appointment.StartsAt != nil && appointment.StartsAt.Equal(slot.StartsAt) {
+		http.Error(w, "Замер уже назначен на это время", http.StatusConflict)
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := appointments.Lock(tx, appointment.MasterID); err != nil {
+			return err
+		}
+		// Календарь мастера мог измениться после предложения
+		period := availability.Period{Start: slot.StartsAt, End: slot.EndsAt}
+		if err := appointments.CheckSlots(tx, *appointment.Master, []availability.Period{period}, appointment.ID); err != nil {
+			return err
+		}
+		if err := appointments.Schedule(tx, appointment, *slot, action, reason); err != nil {
+			return err
+		}
+
+		eventType := events.AppointmentConfirmed
+		payload := appointments.Payload(tx, appointment)
+		if action == appointments.ActionRescheduled {
+			eventType = events.AppointmentRescheduled
+			payload["by"] = appointments.RoleClient
+			payload["reason"] = reason
+		}
+		return events.Emit(tx, eventType, appointment.ID, payload)
+	})
+	if !appointmentSaved(w, err) {
+		return
+	}
+	events.Wake()
+
+	jsonResponse(w, appointmentJSON(db, *appointment))
+}
+
+func cancelAppointment(w http.ResponseWriter, r *http.Request, db *gorm.DB, appointment *models.Appointment, role string) {
+	if appointment.Status == appointments.StatusCancelled {
+		http.Error(w, "Замер уже отменён", http.StatusConflict)
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат данных", http.StatusBadRequest)
+		return
+	}
+	reason, ok := appointmentReason(w, req.Reason)
+	if !ok {
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := appointments.Cancel(tx, appointment, role, reason); err != nil {
+			return err
+		}
+		payload := appointments.Payload(tx, appointment)
+		payload["by"] = role
+		payload["reason"] = reason
+		return events.Emit(tx, events.AppointmentCancelled, appointment.ID, payload)
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	events.Wake()
+
+	appointment.CancelReason = &reason
+	appointment.CancelledBy = &role
+	jsonResponse(w, appointmentJSON(db, *appointment))
+}
+
+// masterOnProject - мастер откликнулся на проект или назначен на него
+func masterOnProject(db *gorm.DB, project *models.Project, masterID string) bool {
+	if project.MasterID != nil && *project.MasterID == masterID {
+		return true
+	}
+	var responses int64
+	db.Model(&models.Response{}).Where("project_id = ? AND master_id = ?", project.ID, masterID).Count(&responses)
+	return responses > 0
+}
+
+func loadMasterAppointment(w http.ResponseWriter, r *http.Request, db *gorm.DB) (*models.Appointment, bool) {
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return nil, false
+	}
+	appointment, err := appointments.Load(db, chi.URLParam(r, "id"))
+	if err != nil || appointment.MasterID != master.ID {
+		http.Error(w, "Замер не найден", http.StatusNotFound)
+		return nil, false
+	}
+	return appointment, true
+}
+
+func loadClientAppointment(w http.ResponseWriter, r *http.Request, db *gorm.DB) (*models.Appointment, bool) {
+	userID := r.Context().Value("user_id").(string)
+	appointment, err := appointments.Load(db, chi.URLParam(r, "id"))
+	if err != nil || appointment.Project == nil || appointment.Project.Client.UserID != userID {
+		http.Error(w, "Замер не найден", http.StatusNotFound)
+		return nil, false
+	}
+	return appointment, true
+}
+
+// parseSlots разбирает варианты времени; ошибку уже отправил клиенту
+func parseSlots(w http.ResponseWriter, input []slotInput) ([]availability.Period, bool) {
+	slots := make([]availability.Period, 0, len(input))
+	for _, item := range input {
+		start, err1 := time.Parse(time.RFC3339, item.Start)
+		end, err2 := time.Parse(time.RFC3339, item.End)
+		if err1 != nil || err2 != nil {
+			http.Error(w, "Время указывается в формате RFC3339: 2026-05-20T10:00:00+03:00", http.StatusBadRequest)
+			return nil, false
+		}
+		slots = append(slots, availability.Period{Start: start, End: end})
+	}
+	return slots, true
+}
+
+func appointmentReason(w http.ResponseWriter, value string) (string, bool) {
+	reason := strings.TrimSpace(value)
+	if reason == "" {
+		http.Error(w, "Укажите причину", http.StatusBadRequest)
+		return "", false
+	}
+	if len([]rune(reason)) > maxAppointmentReasonLength {
+		http.Error(w, "Причина - не длиннее 500 символов", http.StatusBadRequest)
+		return "", false
+	}
+	return reason, true
+}
+
+// appointmentSaved отвечает на ошибку транзакции: ошибки проверки
+// времени - 422, занятый выезд по проекту - 409
+func appointmentSaved(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, appointments.ErrNoSlots), errors.Is(err, appointments.ErrTooManySlots),
+		errors.Is(err, appointments.ErrSlotDuration), errors.Is(err, appointments.ErrSlotTooSoon),
+		errors.Is(err, appointments.ErrSlotsOverlap), errors.Is(err, appointments.ErrSlotUnavailable):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "duplicate key"):
+		http.Error(w, "Замер по проекту уже назначен", http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+	return false
+}
+
+func appointmentJSON(db *gorm.DB, appointment models.Appointment) map[string]interface{} {
+	loc := time.Local
+	item := map[string]interface{}{
+		"id":           appointment.ID,
+		"projectId":    appointment.ProjectID,
+		"status":       appointment.Status,
+		"address":      appointment.Address,
+		"note":         appointment.Note,
+		"cancelReason": appointment.CancelReason,
+		"cancelledBy":  appointment.CancelledBy,
+		"startsAt":     nil,
+		"endsAt":       nil,
+		"createdAt":    appointment.CreatedAt.Format(time.RFC3339),
+	}
+	if appointment.Project != nil {
+		item["projectTitle"] = appointment.Project.Title
+	}
+	if appointment.Master != nil {
+		loc = availability.Location(db, *appointment.Master)
+		item["master"] = map[string]interface{}{
+			"id":   appointment.Master.ID,
+			"name": appointment.Master.Name,
+			"slug": appointment.Master.Slug,
+		}
+		item["timezone"] = loc.String()
+	}
+	if appointment.StartsAt != nil && appointment.EndsAt != nil {
+		item["startsAt"] = appointment.StartsAt.In(loc).Format(time.RFC3339)
+		item["endsAt"] = appointment.EndsAt.In(loc).Format(time.RFC3339)
+	}
+
+	slots := []map[string]interface{}{}
+	// У назначенного выезда варианты остаются: на них клиент может перенести замер
+	if appointment.Status != appointments.StatusCancelled {
+		earliest := time.Now().Add(appointments.MinLeadTime)
+		for _, slot := range appointment.Slots {
+			slots = append(slots, map[string]interface{}{
+				"id":        slot.ID,
+				"start":     slot.StartsAt.In(loc).Format(time.RFC3339),
+				"end":       slot.EndsAt.In(loc).Format(time.RFC3339),
+				"available": slot.StartsAt.After(earliest),
+			})
+		}
+	}
+	item["slots"] = slots
+	return item
+}
@@ -0,0 +1,364 @@
+// internal/handlers/availability.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"refurnish/internal/appointments"
+	"refurnish/internal/availability"
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	maxConcurrentProjectsLimit = 20
+	// Максимальный период, за который отдаются свободные слоты
+	availabilityMaxDays = 62
+)
+
+// MyAvailability - GET /api/master/availability
+// Лимит проектов, рабочие часы и выходные/отпуска, которые ещё не закончились
+func MyAvailability(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var hours []models.WorkingHours
+	db.Where("master_id = ?", master.ID).Order("weekday").Find(&hours)
+	custom := len(hours) > 0
+	if !custom {
+		for weekday := 1; weekday <= 7; weekday++ {
+			if h, ok := availability.DefaultHours[weekday]; ok {
+				hours = append(hours, h)
+			}
+		}
+	}
+
+	var timeOff []models.TimeOff
+	db.Where("master_id = ? AND ends_on >= CURRENT_DATE", master.ID).Order("starts_on").Find(&timeOff)
+
+	summary, err := availability.Summarize(db, *master)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"maxConcurrentProjects": master.MaxConcurrentProjects,
+		"workingHours":          workingHoursJSON(hours),
+		"defaultHours":          !custom,
+		"timezone":              availability.Location(db, *master).String(),
+		"timeOff":               timeOffJSON(timeOff),
+		"summary":               summary,
+	})
+}
+
+// UpdateAvailability - PUT /api/master/availability
+// workingHours заменяет график целиком: дни, которых нет в списке, - выходные.
+// Пустой список возвращает график по умолчанию.
+func UpdateAvailability(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var req struct {
+		MaxConcurrentProjects *int `json:"maxConcurrentProjects"`
+		WorkingHours          *[]struct {
+			Weekday int    `json:"weekday"`
+			Start   string `json:"start"` // HH:MM
+			End     string `json:"end"`
+		} `json:"workingHours"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	if req.MaxConcurrentProjects != nil &&
+		(*req.MaxConcurrentProjects < 1 || *req.MaxConcurrentProjects > maxConcurrentProjectsLimit) {
+		http.Error(w, "Лимит одновременных проектов - от 1 до 20", http.StatusBadRequest)
+		return
+	}
+
+	var hours []models.WorkingHours
+	if req.WorkingHours != nil {
+		seen := map[int]bool{}
+		for _, item := range *req.WorkingHours {
+			if item.Weekday < 1 || item.Weekday > 7 || seen[item.Weekday] {
+				http.Error(w, "День недели - число от 1 (пн) до 7 (вс), без повторов", http.StatusBadRequest)
+				return
+			}
+			start, okStart := parseClock(item.Start)
+			end, okEnd := parseClock(item.End)
+			if !okStart || !okEnd || start >= end {
+				http.Error(w, "Рабочие часы задаются как HH:MM, начало раньше конца", http.StatusBadRequest)
+				return
+			}
+			seen[item.Weekday] = true
+			hours = append(hours, models.WorkingHours{
+				MasterID:    master.ID,
+				Weekday:     item.Weekday,
+				StartMinute: start,
+				EndMinute:   end,
+			})
+		}
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if req.MaxConcurrentProjects != nil {
+			if err := tx.Model(master).Update("max_concurrent_projects", *req.MaxConcurrentProjects).Error; err != nil {
+				return err
+			}
+		}
+		if req.WorkingHours == nil {
+			return nil
+		}
+		if err := tx.Where("master_id = ?", master.ID).Delete(&models.WorkingHours{}).Error; err != nil {
+			return err
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		return tx.Create(&hours).Error
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	MyAvailability(w, r)
+}
+
+// CreateTimeOff - POST /api/master/availability/time-off
+// {kind: day_off|vacation, startsOn, endsOn, note}, даты YYYY-MM-DD включительно
+func CreateTimeOff(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var req struct {
+		Kind     string `json:"kind"`
+		StartsOn string `json:"startsOn"`
+		EndsOn   string `json:"endsOn"`
+		Note     string `json:"note"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	if req.Kind == "" {
+		req.Kind = availability.DayOff
+	}
+	if req.Kind != availability.DayOff && req.Kind != availability.Vacation {
+		http.Error(w, "Вид - day_off или vacation", http.StatusBadRequest)
+		return
+	}
+	if req.EndsOn == "" {
+		req.EndsOn = req.StartsOn
+	}
+	startsOn, err1 := time.Parse("2006-01-02", req.StartsOn)
+	endsOn, err2 := time.Parse("2006-01-02", req.EndsOn)
+	if err1 != nil || err2 != nil || endsOn.Before(startsOn) {
+		http.Error(w, "Укажите даты в формате YYYY-MM-DD, конец не раньше начала", http.StatusBadRequest)
+		return
+	}
+	if endsOn.Sub(startsOn) > 365*24*time.Hour {
+		http.Error(w, "Период не может быть длиннее года", http.StatusBadRequest)
+		return
+	}
+
+	timeOff := models.TimeOff{
+		MasterID: master.ID,
+		Kind:     req.Kind,
+		StartsOn: startsOn,
+		EndsOn:   endsOn,
+		Note:     req.Note,
+	}
+	if err := db.Create(&timeOff).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, timeOffJSON([]models.TimeOff{timeOff})[0])
+}
+
+// DeleteTimeOff - DELETE /api/master/availability/time-off/{id}
+func DeleteTimeOff(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	result := db.Where("id = ? AND master_id = ?", chi.URLParam(r, "id"), master.ID).Delete(&models.TimeOff{})
+	if result.Error != nil {
+		http.Error(w, result.Error.Error(), http.StatusInternalServerError)
+		return
+	}
+	if result.RowsAffected == 0 {
+		http.Error(w, "Период не найден", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MasterAvailability - GET /api/masters/{id}/availability?from=&to=&duration=
+// Дни с from по to (по умолчанию две недели от сегодня) и свободные окна
+// длиной duration минут (по умолчанию 60) в рабочие часы свободных дней.
+// Причины занятости не раскрываются.
+func MasterAvailability(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+	master, err := findMaster(db, chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	loc := availability.Location(db, *master)
+	from, to, duration, ok := slotsRange(w, r, loc)
+	if !ok {
+		return
+	}
+
+	// Выезды на замер тоже занимают время
+	cal, err := appointments.Calendar(db, *master, from, to, "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	slots := cal.Slots(from, to, time.Duration(duration)*time.Minute)
+
+	slotsByDay := map[string][]map[string]string{}
+	for _, slot := range slots {
+		date := slot.Start.Format("2006-01-02")
+		slotsByDay[date] = append(slotsByDay[date], map[string]string{
+			"start": slot.Start.Format(time.RFC3339),
+			"end":   slot.End.Format(time.RFC3339),
+		})
+	}
+
+	days := []map[string]interface{}{}
+	for _, day := range cal.Days(from, to) {
+		item := map[string]interface{}{
+			"date":    day.Date,
+			"working": day.Working && day.TimeOff == "",
+			"free":    cal.Free(day),
+			"slots":   slotsByDay[day.Date],
+		}
+		if item["slots"] == nil {
+			item["slots"] = []map[string]string{}
+		}
+		if day.Working {
+			item["start"] = formatClock(day.StartMinute)
+			item["end"] = formatClock(day.EndMinute)
+		}
+		days = append(days, item)
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"masterId":        master.ID,
+		"timezone":        loc.String(),
+		"durationMinutes": duration,
+		"days":            days,
+	})
+}
+
+// slotsRange - from, to и duration из запроса в часовом поясе мастера
+func slotsRange(w http.ResponseWriter, r *http.Request, loc *time.Location) (time.Time, time.Time, int, bool) {
+	params := r.URL.Query()
+	var err error
+
+	from := time.Now().In(loc)
+	if v := params.Get("from"); v != "" {
+		if from, err = time.ParseInLocation("2006-01-02", v, loc); err != nil {
+			http.Error(w, "from - дата в формате YYYY-MM-DD", http.StatusBadRequest)
+			return from, from, 0, false
+		}
+	}
+	to := from.AddDate(0, 0, 13)
+	if v := params.Get("to"); v != "" {
+		if to, err = time.ParseInLocation("2006-01-02", v, loc); err != nil {
+			http.Error(w, "to - дата в формате YYYY-MM-DD", http.StatusBadRequest)
+			return from, to, 0, false
+		}
+	}
+	if to.Before(from) || to.Sub(from) > availabilityMaxDays*24*time.Hour {
+		http.Error(w, "Период - не больше 62 дней, to не раньше from", http.StatusBadRequest)
+		return from, to, 0, false
+	}
+
+	duration := 60
+	if v := params.Get("duration"); v != "" {
+		if duration, err = strconv.Atoi(v); err != nil || duration < 15 || duration > 8*60 {
+			http.Error(w, "duration - от 15 до 480 минут", http.StatusBadRequest)
+			return from, to, 0, false
+		}
+	}
+	return from, to, duration, true
+}
+
+// parseClock разбирает HH:MM в минуты от начала суток; 24:00 - конец суток
+func parseClock(value string) (int, bool) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		if value == "24:00" {
+			return 24 * 60, true
+		}
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+func formatClock(minutes int) string {
+	if minutes >= 24*60 {
+		return "24:00"
+	}
+	return time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(minutes) * time.Minute).Format("15:04")
+}
+
+func workingHoursJSON(hours []models.WorkingHours) []map[string]interface{} {
+	items := []map[string]interface{}{}
+	for _, h := range hours {
+		items = append(items, map[string]interface{}{
+			"weekday": h.Weekday,
+			"start":   formatClock(h.StartMinute),
+			"end":     formatClock(h.EndMinute),
+		})
+	}
+	return items
+}
+
+func timeOffJSON(periods []models.TimeOff) []map[string]interface{} {
+	items := []map[string]interface{}{}
+	for _, p := range periods {
+		items = append(items, map[string]interface{}{
+			"id":       p.ID,
+			"kind":     p.Kind,
+			"startsOn": p.StartsOn.Format("2006-01-02"),
+			"endsOn":   p.EndsOn.Format("2006-01-02"),
+			"note":     p.Note,
+		})
+	}
+	return items
+}
@@ -0,0 +1,309 @@
+// internal/handlers/categories.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"refurnish/internal/catalog"
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+	"refurnish/internal/spec"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ListCategories - GET /api/categories (дерево категорий)
+func ListCategories(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(r)
+	db := config.GetDB()
+
+	query := db.Order("sort_order, slug")
+	if r.URL.Query().Get("all") != "true" {
+		query = query.Where("is_active")
+	}
+
+	var categories []models.Category
+	if err := query.Find(&categories).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, categoryTree(categories, lang))
+}
+
+// categoryTree собирает плоский список в дерево с локализованными названиями
+func categoryTree(categories []models.Category, lang string) []map[string]interface{} {
+	children := make(map[string][]models.Category)
+	var roots []models.Category
+	known := make(map[string]bool)
+	for _, c := range categories {
+		known[c.ID] = true
+	}
+	for _, c := range categories {
+		if c.ParentID != nil && known[*c.ParentID] {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+
+	var build func(nodes []models.Category) []map[string]interface{}
+	build = func(nodes []models.Category) []map[string]interface{} {
+		result := []map[string]interface{}{}
+		for _, c := range nodes {
+			result = append(result, map[string]interface{}{
+				"id":       c.ID,
+				"slug":     c.Slug,
+				"parentId": c.ParentID,
+				"name":     c.LocalizedName(lang),
+				"names": map[string]string{
+					"ru": c.NameRu,
+					"en": c.NameEn,
+				},
+				"sortOrder": c.SortOrder,
+				"isActive":  c.IsActive,
+				"children":  build(children[c.ID]),
+			})
+		}
+		return result
+	}
+
+	return build(roots)
+}
+
+// GetCategorySpec - GET /api/categories/{slug}/spec
+// Итоговая схема спецификации с учётом родительских категорий
+func GetCategorySpec(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(r)
+	db := config.GetDB()
+
+	category, err := catalog.Resolve(db, chi.URLParam(r, "slug"))
+	if err != nil {
+		http.Error(w, "Категория не найдена", http.StatusNotFound)
+		return
+	}
+
+	schema, err := spec.ForCategory(db, category.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fields := []map[string]interface{}{}
+	for _, f := range schema.Fields {
+		fields = append(fields, map[string]interface{}{
+			"key":      f.Key,
+			"type":     f.Type,
+			"label":    f.Label(lang),
+			"unit":     f.Unit,
+			"required": f.Required,
+			"min":      f.Min,
+			"max":      f.Max,
+			"options":  f.Options,
+		})
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"category": category.Slug,
+		"fields":   fields,
+	})
+}
+
+type categoryInput struct {
+	Slug      string  `json:"slug"`
+	ParentID  *string `json:"parentId"`
+	NameRu    string  `json:"nameRu"`
+	NameEn    string  `json:"nameEn"`
+	SortOrder int     `json:"sortOrder"`
+	IsActive  *bool   `json:"isActive"`
+	// Схема спецификации; null или отсутствие - без изменений при обновлении
+	SpecSchema json.RawMessage `json:"specSchema"`
+}
+
+func (in *categoryInput) validate() string {
+	in.Slug = strings.ToLower(strings.TrimSpace(in.Slug))
+	in.NameRu = strings.TrimSpace(in.NameRu)
+	in.NameEn = strings.TrimSpace(in.NameEn)
+	if in.ParentID != nil && *in.ParentID == "" {
+		in.ParentID = nil
+	}
+
+	if !slugPattern.MatchString(in.Slug) {
+		return "Slug должен состоять из латинских букв, цифр и дефисов"
+	}
+	if in.NameRu == "" || in.NameEn == "" {
+		return "Укажите названия на русском и английском"
+	}
+	if _, err := spec.Parse(in.SpecSchema); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// CreateCategory - POST /api/admin/categories
+func CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var input categoryInput
+	if err := parseJSON(r, &input); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	if msg := input.validate(); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	db := config.GetDB()
+
+	if input.ParentID != nil {
+		var parent models.Category
+		if err := db.First(&parent, "id = ?", *input.ParentID).Error; err != nil {
+			http.Error(w, "Родительская категория не найдена", http.StatusBadRequest)
+			return
+		}
+	}
+
+	category := models.Category{
+		Slug:      input.Slug,
+		ParentID:  input.ParentID,
+		NameRu:    input.NameRu,
+		NameEn:    input.NameEn,
+		SortOrder: input.SortOrder,
+		IsActive:  input.IsActive == nil || *input.IsActive,
+	}
+	if len(input.SpecSchema) > 0 && string(input.SpecSchema) != "null" {
+		category.SpecSchema = models.JSONB(input.SpecSchema)
+	}
+
+	if err := db.Create(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "duplicate key") {
+			http.Error(w, "Категория с таким slug уже существует", http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("🗂️  Создана категория %s (ID: %s)", category.Slug, category.ID)
+
+	jsonResponse(w, category)
+}
+
+// UpdateCategory - PUT /api/admin/categories/{id}
+func UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID := chi.URLParam(r, "id")
+	db := config.GetDB()
+
+	var category models.Category
+	if err := db.First(&category, "id = ?", categoryID).Error; err != nil {
+		http.Error(w, "Категория не найдена", http.StatusNotFound)
+		return
+	}
+
+	var input categoryInput
+	if err := parseJSON(r, &input); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	if msg := input.validate(); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	// Нельзя сделать категорию потомком самой себя
+	if input.ParentID != nil {
+		cycle, err := catalog.IsDescendant(db, category.ID, *input.ParentID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if cycle {
+			http.Error(w, "Категория не может быть вложена в саму себя", http.StatusBadRequest)
+			return
+		}
+		var parent models.Category
+		if err := db.First(&parent, "id = ?", *input.ParentID).Error; err != nil {
+			http.Error(w, "Родительская категория не найдена", http.StatusBadRequest)
+			return
+		}
+	}
+
+	oldSlug := category.Slug
+	category.Slug = input.Slug
+	category.ParentID = input.ParentID
+	category.NameRu = input.NameRu
+	category.NameEn = input.NameEn
+	category.SortOrder = input.SortOrder
+	if input.IsActive != nil {
+		category.IsActive = *input.IsActive
+	}
+	if len(input.SpecSchema) > 0 && string(input.SpecSchema) != "null" {
+		category.SpecSchema = models.JSONB(input.SpecSchema)
+	}
+	category.UpdatedAt = time.Now()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&category).Error; err != nil {
+			return err
+		}
+		if oldSlug == category.Slug {
+			return nil
+		}
+		// Slug хранится в проектах и специализациях мастеров - переименовываем везде
+		if err := tx.Model(&models.Project{}).
+			Where("category_id = ?", category.ID).
+			Update("furniture_type", category.Slug).Error; err != nil {
+			return err
+		}
+		return tx.Exec(
+			"UPDATE masters SET specializations = array_replace(specializations, ?, ?) WHERE ? = ANY(specializations)",
+			oldSlug, category.Slug, oldSlug,
+		).Error
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, category)
+}
+
+// DeleteCategory - DELETE /api/admin/categories/{id}
+func DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID := chi.URLParam(r, "id")
+	db := config.GetDB()
+
+	var category models.Category
+	if err := db.First(&category, "id = ?", categoryID).Error; err != nil {
+		http.Error(w, "Категория не найдена", http.StatusNotFound)
+		return
+	}
+
+	var children, projects, masters int64
+	db.Model(&models.Category{}).Where("parent_id = ?", category.ID).Count(&children)
+	db.Model(&models.Project{}).Where("category_id = ?", category.ID).Count(&projects)
+	db.Model(&models.Master{}).Where("? = ANY(specializations)", category.Slug).Count(&masters)
+
+	if children > 0 || projects > 0 || masters > 0 {
+		http.Error(w, "Категория используется. Отключите её через isActive=false", http.StatusConflict)
+		return
+	}
+
+	if err := db.Delete(&category).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, map[string]string{
+		"status":     "deleted",
+		"categoryId": category.ID,
+	})
+}
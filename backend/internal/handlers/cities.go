@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"refurnish/internal/cities"
+	"refurnish/internal/config"
+)
+
+// ListCities - GET /api/cities?prefix=
+func ListCities(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("prefix")
+
+	limit := 10
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 50 {
+		limit = l
+	}
+
+	found, err := cities.Search(config.GetDB(), prefix, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := []map[string]interface{}{}
+	for _, city := range found {
+		response = append(response, map[string]interface{}{
+			"id":        city.ID,
+			"name":      city.Name,
+			"region":    city.Region,
+			"timezone":  city.Timezone,
+			"latitude":  city.Latitude,
+			"longitude": city.Longitude,
+		})
+	}
+
+	jsonResponse(w, response)
+}
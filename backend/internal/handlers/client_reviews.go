@@ -0,0 +1,217 @@
+// internal/handlers/client_reviews.go
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"refurnish/internal/config"
+	"refurnish/internal/events"
+	"refurnish/internal/models"
+	"refurnish/internal/moderation"
+	"refurnish/internal/ratings"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// CreateClientReview - POST /api/master/project/{id}/client-review
+// Назначенный мастер оценивает клиента после завершения проекта.
+// Отзыв скрыт, пока клиент не оставит свой или не истечёт REVIEW_REVEAL_DAYS.
+func CreateClientReview(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var project models.Project
+	if err := db.Preload("Client").First(&project, "id = ?", chi.URLParam(r, "id")).Error; err != nil {
+		http.Error(w, "Проект не найден", http.StatusNotFound)
+		return
+	}
+	if project.MasterID == nil || *project.MasterID != master.ID {
+		http.Error(w, "Нет доступа", http.StatusForbidden)
+		return
+	}
+	if project.Status != "completed" {
+		http.Error(w, "Отзыв можно оставить только по завершённому проекту", http.StatusConflict)
+		return
+	}
+	if ratings.WindowClosed(project.CompletedAt, config.ReviewRevealWindow()) {
+		http.Error(w, "Срок для отзыва по этому проекту истёк", http.StatusConflict)
+		return
+	}
+
+	var req struct {
+		Rating        int    `json:"rating"`
+		Payment       *int   `json:"payment"`
+		Communication *int   `json:"communication"`
+		Accuracy      *int   `json:"accuracy"`
+		Text          string `json:"text"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	if !validScore(&req.Rating) || !validScore(req.Payment) || !validScore(req.Communication) || !validScore(req.Accuracy) {
+		http.Error(w, "Оценки должны быть от 1 до 5", http.StatusBadRequest)
+		return
+	}
+	text := strings.TrimSpace(req.Text)
+	if len([]rune(text)) > maxReviewLength {
+		http.Error(w, "Отзыв не длиннее 2000 символов", http.StatusBadRequest)
+		return
+	}
+
+	var existing int64
+	db.Model(&models.ClientReview{}).Where("project_id = ?", project.ID).Count(&existing)
+	if existing > 0 {
+		http.Error(w, "Отзыв по этому проекту уже оставлен", http.StatusConflict)
+		return
+	}
+
+	review := models.ClientReview{
+		ProjectID:     project.ID,
+		MasterID:      master.ID,
+		ClientID:      project.ClientID,
+		Rating:        req.Rating,
+		Payment:       req.Payment,
+		Communication: req.Communication,
+		Accuracy:      req.Accuracy,
+		Text:          text,
+	}
+	if ip := clientIP(r); ip != "" {
+		review.IP = &ip
+	}
+
+	// Отзыв с автоматическими флагами ждёт решения модератора
+	review.Flags = moderation.Check(db, moderation.ClientReview, moderation.Submission{
+		Text:      review.Text,
+		IP:        review.IP,
+		AuthorID:  review.MasterID,
+		SubjectID: review.ClientID,
+	})
+	review.ModerationStatus = moderation.StatusApproved
+	if len(review.Flags) > 0 {
+		review.ModerationStatus = moderation.StatusPending
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&review).Error; err != nil {
+			return err
+		}
+		if len(review.Flags) > 0 {
+			if err := moderation.Record(tx, moderation.ClientReview, review.ID, nil, moderation.ActionFlagged, "", map[string]interface{}{
+				"flags": review.Flags,
+			}); err != nil {
+				return err
+			}
+		}
+		published, err := ratings.Reveal(tx, project.ID, config.ReviewRevealWindow())
+		if err != nil {
+			return err
+		}
+		if published {
+			now := time.Now()
+			review.PublishedAt = &now
+		}
+		return events.Emit(tx, events.ClientReviewCreated, review.ID, map[string]interface{}{
+			"projectId":    project.ID,
+			"projectTitle": project.Title,
+			"clientId":     project.ClientID,
+			"clientUserId": project.Client.UserID,
+		})
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	events.Wake()
+
+	log.Printf("⭐ Отзыв на клиента %s: %d (модерация: %s %v)", project.ClientID, review.Rating, review.ModerationStatus, review.Flags)
+
+	jsonResponse(w, clientReviewJSON(review))
+}
+
+// ClientReviews - GET /api/master/clients/{id}/reviews?limit=&offset=
+// Открытые отзывы мастеров о клиенте и его репутация.
+func ClientReviews(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	var client models.Client
+	if err := db.First(&client, "id = ?", chi.URLParam(r, "id")).Error; err != nil {
+		http.Error(w, "Клиент не найден", http.StatusNotFound)
+		return
+	}
+
+	limit := 20
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	var reviews []models.ClientReview
+	err := db.Preload("Master").
+		Where("client_id = ? AND "+ratings.VisibleReview, client.ID).
+		Order("created_at DESC").Limit(limit).Offset(offset).
+		Find(&reviews).Error
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var averages struct {
+		Payment       *float64
+		Communication *float64
+		Accuracy      *float64
+	}
+	db.Raw(`
+		SELECT AVG(payment)::float8 AS payment, AVG(communication)::float8 AS communication,
+		       AVG(accuracy)::float8 AS accuracy
+		FROM client_reviews WHERE client_id = ? AND `+ratings.VisibleReview, client.ID).Scan(&averages)
+
+	items := []map[string]interface{}{}
+	for _, review := range reviews {
+		item := clientReviewJSON(review)
+		if review.Master != nil {
+			item["masterName"] = review.Master.Name
+		}
+		items = append(items, item)
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"clientId":     client.ID,
+		"reputation":   client.Reputation,
+		"reviewsCount": client.ReviewsCount,
+		"averages": map[string]interface{}{
+			"payment":       averages.Payment,
+			"communication": averages.Communication,
+			"accuracy":      averages.Accuracy,
+		},
+		"items": items,
+	})
+}
+
+func clientReviewJSON(review models.ClientReview) map[string]interface{} {
+	return map[string]interface{}{
+		"id":               review.ID,
+		"projectId":        review.ProjectID,
+		"rating":           review.Rating,
+		"payment":          review.Payment,
+		"communication":    review.Communication,
+		"accuracy":         review.Accuracy,
+		"text":             review.Text,
+		"published":        review.PublishedAt != nil,
+		"moderationStatus": review.ModerationStatus,
+		"createdAt":        review.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"refurnish/internal/config"
 	"refurnish/internal/models"
+	"refurnish/internal/spec"
 
 	"github.com/go-chi/chi/v5"
 	"gorm.io/gorm"
@@ -45,6 +46,21 @@ func GetProjectDetails(w http.ResponseWriter, r *http.Request) {
 		client = models.Client{}
 	}
 
+	userID, _ := r.Context().Value("user_id").(string)
+	isOwner := userID == client.UserID
+
+	// Черновики и запланированные проекты видит только владелец
+	if !isOwner && (project.Status == "draft" || project.Status == "scheduled") {
+		http.Error(w, "Проект не найден", http.StatusNotFound)
+		return
+	}
+
+	// Проект по приглашениям видят только владелец и приглашённые мастера
+	if !isOwner && project.Visibility == "invite_only" && !isInvited(db, project.ID, userID) {
+		http.Error(w, "Проект не найден", http.StatusNotFound)
+		return
+	}
+
 	// Загружаем пользователя клиента
 	var clientUser models.User
 	if client.UserID != "" {
@@ -61,13 +77,42 @@ func GetProjectDetails(w http.ResponseWriter, r *http.Request) {
 		"deadline":      project.Deadline.Format("2006-01-02"),
 		"city":          project.City,
 		"status":        project.Status,
+		"visibility":    project.Visibility,
 		"createdAt":     project.CreatedAt.Format(time.RFC3339),
+		"spec":          []map[string]interface{}{},
+		"photos":        projectPhotos(project.ID),
+		"questions":     projectQuestions(db, project.ID, userID, isOwner),
+	}
+
+	if project.PublishAt != nil {
+		response["publishAt"] = project.PublishAt.Format(time.RFC3339)
+	}
+
+	// Категория и спецификация в структурированном виде
+	if project.CategoryID != nil {
+		lang := requestLang(r)
+		var category models.Category
+		if err := db.First(&category, "id = ?", *project.CategoryID).Error; err == nil {
+			response["category"] = map[string]interface{}{
+				"id":   category.ID,
+				"slug": category.Slug,
+				"name": category.LocalizedName(lang),
+			}
+		}
+		if schema, err := spec.ForCategory(db, *project.CategoryID); err == nil {
+			response["spec"] = spec.Describe(schema, project.Spec, lang)
+		} else {
+			log.Printf("⚠️  Не удалось загрузить схему спецификации: %v", err)
+		}
 	}
 
 	if clientUser.ID != "" && clientUser.Client != nil { // Проверяем и clientUser.Client
 		response["clientName"] = clientUser.Client.Name
 		response["clientEmail"] = clientUser.Email
 		response["clientPhone"] = "+79213946509"
+		response["clientId"] = clientUser.Client.ID
+		response["clientReputation"] = clientUser.Client.Reputation
+		response["clientReviewsCount"] = clientUser.Client.ReviewsCount
 	} else {
 		response["clientName"] = "Неизвестный клиент"
 		response["clientEmail"] = "email@example.com"
@@ -0,0 +1,514 @@
+// internal/handlers/conversations.go
+package handlers
+
+import (
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+	"refurnish/internal/notify"
+	"refurnish/internal/realtime"
+	"refurnish/internal/uploads"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxMessageLength      = 4000
+	maxMessageAttachments = 5
+)
+
+// StartConversation - POST /api/conversations
+// Клиент указывает projectId и masterId, мастер - только projectId.
+func StartConversation(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+	db := config.GetDB()
+
+	var req struct {
+		ProjectID string `json:"projectId"`
+		MasterID  string `json:"masterId"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	var project models.Project
+	if err := db.Preload("Client").First(&project, "id = ?", req.ProjectID).Error; err != nil {
+		http.Error(w, "Проект не найден", http.StatusNotFound)
+		return
+	}
+
+	var masterID string
+	if project.Client.UserID == userID {
+		var master models.Master
+		if err := db.First(&master, "id = ?", req.MasterID).Error; err != nil {
+			http.Error(w, "Мастер не найден", http.StatusNotFound)
+			return
+		}
+		masterID = master.ID
+	} else {
+		master, err := currentMaster(db, r)
+		if err != nil {
+			http.Error(w, "Нет доступа", http.StatusForbidden)
+			return
+		}
+		if !canMasterContact(db, project, master.ID, userID) {
+			http.Error(w, "Проект недоступен для переписки", http.StatusForbidden)
+			return
+		}
+		masterID = master.ID
+	}
+
+	conversation := models.Conversation{
+		ProjectID: project.ID,
+		ClientID:  project.ClientID,
+		MasterID:  masterID,
+	}
+	// Для пары проект-мастер переписка одна: возвращаем существующую
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&conversation).Error
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := db.First(&conversation, "project_id = ? AND master_id = ?", project.ID, masterID).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"id":        conversation.ID,
+		"projectId": conversation.ProjectID,
+		"masterId":  conversation.MasterID,
+		"clientId":  conversation.ClientID,
+	})
+}
+
+// canMasterContact - мастер может написать по опубликованному доступному ему
+// проекту, а также по проекту, где он откликнулся, приглашён или назначен.
+func canMasterContact(db *gorm.DB, project models.Project, masterID, userID string) bool {
+	if project.MasterID != nil && *project.MasterID == masterID {
+		return true
+	}
+	var responded int64
+	db.Model(&models.Response{}).Where("project_id = ? AND master_id = ?", project.ID, masterID).Count(&responded)
+	if responded > 0 || isInvited(db, project.ID, userID) {
+		return true
+	}
+	return project.Status == "published" && project.Visibility != "invite_only"
+}
+
+// MyConversations - GET /api/conversations
+func MyConversations(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+	db := config.GetDB()
+
+	var conversations []models.Conversation
+	err := db.Preload("Project").Preload("Client").Preload("Master").
+		Joins("JOIN clients c ON c.id = conversations.client_id").
+		Joins("JOIN masters m ON m.id = conversations.master_id").
+		Where("c.user_id = ? OR m.user_id = ?", userID, userID).
+		Order("COALESCE(conversations.last_message_at, conversations.created_at) DESC").
+		Find(&conversations).Error
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ids := make([]string, 0, len(conversations))
+	for _, c := range conversations {
+		ids = append(ids, c.ID)
+	}
+	unread := unreadByConversation(db, ids, userID)
+
+	result := []map[string]interface{}{}
+	for _, c := range conversations {
+		item := map[string]interface{}{
+			"id":          c.ID,
+			"projectId":   c.ProjectID,
+			"unreadCount": unread[c.ID],
+			"createdAt":   c.CreatedAt.Format(time.RFC3339),
+		}
+		if c.Project != nil {
+			item["projectTitle"] = c.Project.Title
+		}
+		// Собеседник
+		if c.Client != nil && c.Client.UserID == userID {
+			if c.Master != nil {
+				item["with"] = map[string]interface{}{"role": "master", "id": c.Master.ID, "name": c.Master.Name}
+			}
+		} else if c.Client != nil {
+			item["with"] = map[string]interface{}{"role": "client", "id": c.Client.ID, "name": c.Client.Name}
+		}
+		if c.LastMessageAt != nil {
+			item["lastMessageAt"] = c.LastMessageAt.Format(time.RFC3339)
+			var last models.Message
+			if err := db.Where("conversation_id = ?", c.ID).Order("created_at DESC").First(&last).Error; err == nil {
+				item["lastMessage"] = last.Body
+			}
+		}
+		result = append(result, item)
+	}
+
+	jsonResponse(w, result)
+}
+
+// UnreadMessagesCount - GET /api/conversations/unread
+func UnreadMessagesCount(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+
+	var count int64
+	config.GetDB().Model(&models.Message{}).
+		Joins("JOIN conversations cv ON cv.id = messages.conversation_id").
+		Joins("JOIN clients c ON c.id = cv.client_id").
+		Joins("JOIN masters m ON m.id = cv.master_id").
+		Where("(c.user_id = ? OR m.user_id = ?) AND messages.sender_id <> ? AND messages.read_at IS NULL", userID, userID, userID).
+		Count(&count)
+
+	jsonResponse(w, map[string]int64{"unread": count})
+}
+
+// ConversationMessages - GET /api/conversations/{id}/messages?before=&limit=
+// Сообщения от новых к старым; before - createdAt самого старого загруженного.
+func ConversationMessages(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	conversation, ok := loadConversation(w, r, db)
+	if !ok {
+		return
+	}
+
+	limit := 50
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	query := db.Preload("Attachments").Where("conversation_id = ?", conversation.ID)
+	if before := r.URL.Query().Get("before"); before != "" {
+		t, err := time.Parse(time.RFC3339Nano, before)
+		if err != nil {
+			http.Error(w, "Неверный параметр before", http.StatusBadRequest)
+			return
+		}
+		query = query.Where("created_at < ?", t)
+	}
+
+	var messages []models.Message
+	if err := query.Order("created_at DESC").Limit(limit + 1).Find(&messages).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+
+	items := []map[string]interface{}{}
+	for _, m := range messages {
+		items = append(items, messageJSON(m))
+	}
+
+	response := map[string]interface{}{
+		"items":   items,
+		"hasMore": hasMore,
+	}
+	if hasMore {
+		response["nextBefore"] = messages[len(messages)-1].CreatedAt.Format(time.RFC3339Nano)
+	}
+	jsonResponse(w, response)
+}
+
+// SendMessage - POST /api/conversations/{id}/messages
+func SendMessage(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+	db := config.GetDB()
+
+	conversation, ok := loadConversation(w, r, db)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Body          string   `json:"body"`
+		AttachmentIDs []string `json:"attachmentIds"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" && len(req.AttachmentIDs) == 0 {
+		http.Error(w, "Пустое сообщение", http.StatusBadRequest)
+		return
+	}
+	if len([]rune(body)) > maxMessageLength || len(req.AttachmentIDs) > maxMessageAttachments {
+		http.Error(w, "Сообщение слишком длинное или слишком много вложений", http.StatusBadRequest)
+		return
+	}
+
+	message := models.Message{
+		ConversationID: conversation.ID,
+		SenderID:       userID,
+		Body:           body,
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&message).Error; err != nil {
+			return err
+		}
+		if len(req.AttachmentIDs) > 0 {
+			// Привязываем только свои ещё не отправленные вложения из этой переписки
+			result := tx.Model(&models.MessageAttachment{}).
+				Where("id IN ? AND conversation_id = ? AND uploader_id = ? AND message_id IS NULL",
+					req.AttachmentIDs, conversation.ID, userID).
+				Update("message_id", message.ID)
+			if result.Error != nil {
+				return result.Error
+			}
+			if int(result.RowsAffected) != len(req.AttachmentIDs) {
+				return errInvalidAttachments
+			}
+		}
+		return tx.Model(&models.Conversation{}).Where("id = ?", conversation.ID).
+			Update("last_message_at", message.CreatedAt).Error
+	})
+	if errors.Is(err, errInvalidAttachments) {
+		http.Error(w, "Вложение не найдено", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	db.Preload("Attachments").First(&message, "id = ?", message.ID)
+
+	// Уведомляем собеседника
+	recipient := conversation.Client.UserID
+	senderName := conversation.Master.Name
+	if recipient == userID {
+		recipient = conversation.Master.UserID
+		senderName = conversation.Client.Name
+	}
+	preview := body
+	if len([]rune(preview)) > 100 {
+		preview = string([]rune(preview)[:100]) + "…"
+	}
+	// Событие получают обе стороны: у отправителя могут быть открыты другие устройства
+	event := map[string]interface{}{
+		"conversationId": conversation.ID,
+		"projectId":      conversation.ProjectID,
+		"messageId":      message.ID,
+		"senderId":       userID,
+		"preview":        preview,
+	}
+	realtime.Publish(recipient, realtime.MessageCreated, event)
+	realtime.Publish(userID, realtime.MessageCreated, event)
+
+	notify.Send(db, recipient, notify.MessageReceived, map[string]interface{}{
+		"conversationId": conversation.ID,
+		"projectId":      conversation.ProjectID,
+		"messageId":      message.ID,
+		"senderName":     strings.TrimSpace(senderName),
+		"preview":        preview,
+	})
+
+	jsonResponse(w, messageJSON(message))
+}
+
+var errInvalidAttachments = errors.New("invalid attachments")
+
+// MarkConversationRead - POST /api/conversations/{id}/read
+// Отмечает прочитанными все входящие сообщения
+func MarkConversationRead(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+	db := config.GetDB()
+
+	conversation, ok := loadConversation(w, r, db)
+	if !ok {
+		return
+	}
+
+	result := db.Model(&models.Message{}).
+		Where("conversation_id = ? AND sender_id <> ? AND read_at IS NULL", conversation.ID, userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		http.Error(w, result.Error.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if result.RowsAffected > 0 {
+		counterpart := conversation.Client.UserID
+		if counterpart == userID {
+			counterpart = conversation.Master.UserID
+		}
+		realtime.Publish(counterpart, realtime.MessagesRead, map[string]interface{}{
+			"conversationId": conversation.ID,
+			"readBy":         userID,
+		})
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"status": "read",
+		"marked": result.RowsAffected,
+	})
+}
+
+// UploadMessageAttachment - POST /api/conversations/{id}/attachments (multipart, поле "file")
+func UploadMessageAttachment(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+	db := config.GetDB()
+
+	conversation, ok := loadConversation(w, r, db)
+	if !ok {
+		return
+	}
+
+	// Вложения видят только участники переписки, поэтому они хранятся
+	// вне публичного /uploads/ и отдаются через ConversationAttachment
+	file, err := uploads.SavePrivate(w, r, "file")
+	if err != nil {
+		if uploads.IsClientError(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("❌ Ошибка сохранения вложения: %v", err)
+		http.Error(w, "Ошибка сохранения файла", http.StatusInternalServerError)
+		return
+	}
+
+	attachment := models.MessageAttachment{
+		ConversationID: conversation.ID,
+		UploaderID:     userID,
+		URL:            file.URL,
+		FileName:       file.Name,
+		ContentType:    file.ContentType,
+		Size:           file.Size,
+	}
+	if err := db.Create(&attachment).Error; err != nil {
+		uploads.RemovePrivate(file.URL)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, attachmentJSON(attachment))
+}
+
+// ConversationAttachment - GET /api/conversations/{id}/attachments/{attachmentId}
+// Файл вложения для участника переписки
+func ConversationAttachment(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	conversation, ok := loadConversation(w, r, db)
+	if !ok {
+		return
+	}
+
+	var attachment models.MessageAttachment
+	if err := db.First(&attachment, "id = ? AND conversation_id = ?",
+		chi.URLParam(r, "attachmentId"), conversation.ID).Error; err != nil {
+		http.Error(w, "Вложение не найдено", http.StatusNotFound)
+		return
+	}
+
+	// Вложения, загруженные до переноса в закрытый каталог, лежат в /uploads/
+	var f *os.File
+	var err error
+	if strings.HasPrefix(attachment.URL, uploads.URLPrefix) {
+		f, err = uploads.Open(attachment.URL)
+	} else {
+		f, err = uploads.OpenPrivate(attachment.URL)
+	}
+	if err != nil {
+		http.Error(w, "Файл не найден", http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("Cache-Control", "private, no-store")
+	http.ServeContent(w, r, attachment.FileName, attachment.CreatedAt, f)
+}
+
+// loadConversation загружает переписку из URL и проверяет, что текущий
+// пользователь - её участник. При ошибке сам пишет ответ.
+func loadConversation(w http.ResponseWriter, r *http.Request, db *gorm.DB) (*models.Conversation, bool) {
+	userID := r.Context().Value("user_id").(string)
+
+	var conversation models.Conversation
+	if err := db.Preload("Client").Preload("Master").
+		First(&conversation, "id = ?", chi.URLParam(r, "id")).Error; err != nil {
+		http.Error(w, "Переписка не найдена", http.StatusNotFound)
+		return nil, false
+	}
+
+	if conversation.Client == nil || conversation.Master == nil ||
+		(conversation.Client.UserID != userID && conversation.Master.UserID != userID) {
+		http.Error(w, "Нет доступа", http.StatusForbidden)
+		return nil, false
+	}
+
+	return &conversation, true
+}
+
+func unreadByConversation(db *gorm.DB, ids []string, userID string) map[string]int64 {
+	result := make(map[string]int64)
+	if len(ids) == 0 {
+		return result
+	}
+
+	var rows []struct {
+		ConversationID string
+		Count          int64
+	}
+	db.Model(&models.Message{}).
+		Select("conversation_id, count(*) AS count").
+		Where("conversation_id IN ? AND sender_id <> ? AND read_at IS NULL", ids, userID).
+		Group("conversation_id").
+		Scan(&rows)
+	for _, row := range rows {
+		result[row.ConversationID] = row.Count
+	}
+	return result
+}
+
+func messageJSON(m models.Message) map[string]interface{} {
+	attachments := []map[string]interface{}{}
+	for _, a := range m.Attachments {
+		attachments = append(attachments, attachmentJSON(a))
+	}
+
+	result := map[string]interface{}{
+		"id":          m.ID,
+		"senderId":    m.SenderID,
+		"body":        m.Body,
+		"attachments": attachments,
+		"read":        m.ReadAt != nil,
+		"createdAt":   m.CreatedAt.Format(time.RFC3339Nano),
+	}
+	if m.ReadAt != nil {
+		result["readAt"] = m.ReadAt.Format(time.RFC3339)
+	}
+	return result
+}
+
+func attachmentJSON(a models.MessageAttachment) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          a.ID,
+		"url":         "/api/conversations/" + a.ConversationID + "/attachments/" + a.ID,
+		"fileName":    a.FileName,
+		"contentType": a.ContentType,
+		"size":        a.Size,
+	}
+}
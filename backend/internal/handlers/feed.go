@@ -0,0 +1,133 @@
+// internal/handlers/feed.go
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+	"refurnish/internal/recommend"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm/clause"
+)
+
+// MasterFeed - GET /api/master/feed?limit=&offset=
+func MasterFeed(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	limit := 20
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+	offset := 0
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && o > 0 {
+		offset = o
+	}
+
+	items, limited, err := recommend.Feed(db, *master)
+	if err != nil {
+		log.Printf("❌ Ошибка построения ленты мастера %s: %v", master.ID, err)
+		http.Error(w, "Ошибка построения ленты", http.StatusInternalServerError)
+		return
+	}
+
+	total := len(items)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	result := []map[string]interface{}{}
+	for _, item := range items[offset:end] {
+		p := item.Project
+		result = append(result, map[string]interface{}{
+			"id":            p.ID,
+			"title":         p.Title,
+			"description":   p.Description,
+			"furnitureType": p.FurnitureType,
+			"budget":        p.Budget,
+			"deadline":      p.Deadline.Format("2006-01-02"),
+			"city":          p.City,
+			"createdAt":     p.CreatedAt.Format(time.RFC3339),
+			"score":         item.Score,
+			"breakdown":     item.Factors,
+		})
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"items":  result,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+		// В ленте только FeedWindow самых свежих проектов: при limited
+		// total - размер окна, а не число всех подходящих проектов
+		"limited": limited,
+		"window":  recommend.FeedWindow,
+	})
+}
+
+// HideFeedProject - POST /api/master/feed/{projectId}/hide
+func HideFeedProject(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	hide := models.ProjectHide{
+		MasterID:  master.ID,
+		ProjectID: chi.URLParam(r, "projectId"),
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&hide).Error; err != nil {
+		// 23503 - проекта нет (внешний ключ), 22P02 - id не UUID
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && (pgErr.Code == "23503" || pgErr.Code == "22P02") {
+			http.Error(w, "Проект не найден", http.StatusNotFound)
+			return
+		}
+		log.Printf("❌ Ошибка скрытия проекта %s: %v", hide.ProjectID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, map[string]string{
+		"status":    "hidden",
+		"projectId": hide.ProjectID,
+	})
+}
+
+// UnhideFeedProject - DELETE /api/master/feed/{projectId}/hide
+func UnhideFeedProject(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	projectID := chi.URLParam(r, "projectId")
+	db.Where("master_id = ? AND project_id = ?", master.ID, projectID).Delete(&models.ProjectHide{})
+
+	jsonResponse(w, map[string]string{
+		"status":    "visible",
+		"projectId": projectID,
+	})
+}
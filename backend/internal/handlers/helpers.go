@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"net"
 	"net/http"
+	"strings"
 )
 
 func jsonResponse(w http.ResponseWriter, data interface{}) {
@@ -14,3 +18,33 @@ func jsonResponse(w http.ResponseWriter, data interface{}) {
 func parseJSON(r *http.Request, v interface{}) error {
 	return json.NewDecoder(r.Body).Decode(v)
 }
+
+// Язык ответа: ?lang=en или заголовок Accept-Language, по умолчанию ru
+func requestLang(r *http.Request) string {
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		lang = r.Header.Get("Accept-Language")
+	}
+	if strings.HasPrefix(strings.ToLower(lang), "en") {
+		return "en"
+	}
+	return "ru"
+}
+
+// Случайный токен для ссылок (отписка, привязка аккаунтов и т.п.)
+func randomToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// IP клиента без порта. За прокси RemoteAddr подменяет middleware.RealIP
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,260 @@
+// internal/handlers/invitations.go
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"refurnish/internal/config"
+	"refurnish/internal/events"
+	"refurnish/internal/models"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InviteMasters - POST /api/client/project/{id}/invitations
+func InviteMasters(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		MasterIDs []string `json:"masterIds"`
+		Message   string   `json:"message"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	if len(req.MasterIDs) == 0 || len(req.MasterIDs) > 20 {
+		http.Error(w, "Выберите от 1 до 20 мастеров", http.StatusBadRequest)
+		return
+	}
+	if project.Status != "published" {
+		http.Error(w, "Приглашать можно только в опубликованный проект", http.StatusConflict)
+		return
+	}
+
+	var masters []models.Master
+	if err := db.Where("id IN ?", req.MasterIDs).Find(&masters).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(masters) == 0 {
+		http.Error(w, "Мастера не найдены", http.StatusNotFound)
+		return
+	}
+
+	invited := []string{}
+	for _, master := range masters {
+		invitation := models.Invitation{
+			ProjectID: project.ID,
+			MasterID:  master.ID,
+			Status:    "pending",
+			Message:   strings.TrimSpace(req.Message),
+		}
+		// Приглашение и событие для уведомления пишутся в одной транзакции.
+		// Повторное приглашение того же мастера ничего не меняет.
+		created := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&invitation)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			created = true
+			return events.Emit(tx, events.InvitationCreated, invitation.ID, map[string]interface{}{
+				"projectId":    project.ID,
+				"projectTitle": project.Title,
+				"masterId":     master.ID,
+				"masterUserId": master.UserID,
+			})
+		})
+		if err != nil {
+			log.Printf("❌ Ошибка приглашения мастера %s: %v", master.ID, err)
+			continue
+		}
+		if created {
+			invited = append(invited, master.ID)
+		}
+	}
+	if len(invited) > 0 {
+		events.Wake()
+	}
+
+	log.Printf("✉️  Проект %s: приглашено мастеров %d", project.ID, len(invited))
+
+	jsonResponse(w, map[string]interface{}{
+		"status":    "invited",
+		"projectId": project.ID,
+		"invited":   invited,
+	})
+}
+
+// ProjectInvitations - GET /api/client/project/{id}/invitations
+func ProjectInvitations(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
+		return
+	}
+
+	var invitations []models.Invitation
+	db.Preload("Master").Where("project_id = ?", project.ID).Order("created_at").Find(&invitations)
+
+	result := []map[string]interface{}{}
+	for _, inv := range invitations {
+		item := map[string]interface{}{
+			"id":            inv.ID,
+			"masterId":      inv.MasterID,
+			"status":        inv.Status,
+			"declineReason": inv.DeclineReason,
+			"createdAt":     inv.CreatedAt.Format(time.RFC3339),
+		}
+		if inv.Master != nil {
+			item["masterName"] = inv.Master.Name
+		}
+		result = append(result, item)
+	}
+
+	jsonResponse(w, result)
+}
+
+// MasterInvitations - GET /api/master/invitations (входящие приглашения)
+func MasterInvitations(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	query := db.Preload("Project").Where("master_id = ?", master.ID)
+	if status := r.URL.Query().Get("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var invitations []models.Invitation
+	if err := query.Order("created_at DESC").Find(&invitations).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := []map[string]interface{}{}
+	for _, inv := range invitations {
+		if inv.Project == nil {
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"id":        inv.ID,
+			"status":    inv.Status,
+			"message":   inv.Message,
+			"createdAt": inv.CreatedAt.Format(time.RFC3339),
+			"project": map[string]interface{}{
+				"id":            inv.Project.ID,
+				"title":         inv.Project.Title,
+				"furnitureType": inv.Project.FurnitureType,
+				"budget":        inv.Project.Budget,
+				"deadline":      inv.Project.Deadline.Format("2006-01-02"),
+				"city":          inv.Project.City,
+				"status":        inv.Project.Status,
+			},
+		})
+	}
+
+	jsonResponse(w, result)
+}
+
+// AcceptInvitation - POST /api/master/invitations/{id}/accept
+func AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	answerInvitation(w, r, "accepted")
+}
+
+// DeclineInvitation - POST /api/master/invitations/{id}/decline
+func DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	answerInvitation(w, r, "declined")
+}
+
+func answerInvitation(w http.ResponseWriter, r *http.Request, status string) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := parseJSON(r, &req); err != nil {
+			http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var invitation models.Invitation
+	if err := db.Preload("Project.Client").
+		First(&invitation, "id = ? AND master_id = ?", chi.URLParam(r, "id"), master.ID).Error; err != nil {
+		http.Error(w, "Приглашение не найдено", http.StatusNotFound)
+		return
+	}
+	if invitation.Status != "pending" {
+		http.Error(w, "Вы уже ответили на это приглашение", http.StatusConflict)
+		return
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":       status,
+		"responded_at": now,
+	}
+	if status == "declined" {
+		updates["decline_reason"] = strings.TrimSpace(req.Reason)
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&invitation).Updates(updates).Error; err != nil {
+			return err
+		}
+		if invitation.Project == nil {
+			return nil
+		}
+		return events.Emit(tx, events.InvitationAnswered, invitation.ID, map[string]interface{}{
+			"projectId":    invitation.ProjectID,
+			"projectTitle": invitation.Project.Title,
+			"clientUserId": invitation.Project.Client.UserID,
+			"masterId":     master.ID,
+			"masterName":   master.Name,
+			"status":       status,
+		})
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	events.Wake()
+
+	jsonResponse(w, map[string]string{
+		"status":       status,
+		"invitationId": invitation.ID,
+	})
+}
+
+// isInvited проверяет, есть ли у пользователя-мастера действующее приглашение в проект
+func isInvited(db *gorm.DB, projectID, userID string) bool {
+	var count int64
+	db.Model(&models.Invitation{}).
+		Joins("JOIN masters ON masters.id = invitations.master_id").
+		Where("invitations.project_id = ? AND masters.user_id = ? AND invitations.status <> ?", projectID, userID, "declined").
+		Count(&count)
+	return count > 0
+}
@@ -0,0 +1,190 @@
+// internal/handlers/master_profile.go
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"refurnish/internal/availability"
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+	"refurnish/internal/ratings"
+	"refurnish/internal/slug"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// Значки публичного профиля
+const (
+	BadgeVerified      = "verified"       // документы проверены модератором
+	BadgeTopRated      = "top_rated"      // рейтинг от 4.7 при 10+ отзывах
+	BadgeFastResponder = "fast_responder" // медианное время отклика до часа при 5+ откликах
+	BadgeExperienced   = "experienced"    // 20+ завершённых проектов
+	BadgeVeteran       = "veteran"        // 3+ года на площадке
+)
+
+const profileReviewsLimit = 5
+
+// masterStats - агрегаты для публичного профиля
+type masterStats struct {
+	CompletedProjects     int
+	ResponsesCount        int
+	MedianResponseMinutes *float64
+}
+
+// PublicMasterProfile - GET /api/masters/{id}, {id} - id или адрес профиля.
+// Email и телефон мастера не отдаются: связь только через площадку.
+func PublicMasterProfile(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+	lang := requestLang(r)
+
+	master, err := findMaster(db, chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	stats := loadMasterStats(db, master.ID)
+	years := int(time.Since(master.CreatedAt).Hours() / (24 * 365.25))
+
+	var reviews []models.Review
+	db.Preload("Client").Preload("Project").
+		Preload("Photos", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order") }).
+		Where("master_id = ? AND "+ratings.VisibleReview, master.ID).
+		Order("created_at DESC").Limit(profileReviewsLimit).
+		Find(&reviews)
+
+	latest := []map[string]interface{}{}
+	for _, review := range reviews {
+		clientName := ""
+		if review.Client != nil {
+			clientName = review.Client.Name
+		}
+		item := reviewJSON(review, clientName)
+		if review.Project != nil {
+			item["projectTitle"] = review.Project.Title
+		}
+		latest = append(latest, item)
+	}
+
+	// Без календаря профиль всё равно отдаётся
+	summary, err := availability.Summarize(db, *master)
+	if err != nil {
+		log.Printf("⚠️  Доступность мастера %s: %v", master.ID, err)
+	}
+
+	var medianResponse interface{}
+	if stats.MedianResponseMinutes != nil {
+		medianResponse = int(math.Round(*stats.MedianResponseMinutes))
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"id":              master.ID,
+		"slug":            master.Slug,
+		"name":            master.Name,
+		"description":     master.Description,
+		"city":            master.City,
+		"specializations": master.Specializations,
+		"priceFrom":       master.PriceFrom,
+		"serviceRadiusKm": master.ServiceRadiusKm,
+		"rating":          master.Rating,
+		"reviewsCount":    master.ReviewsCount,
+		"verified":        master.VerifiedAt != nil,
+		"memberSince":     master.CreatedAt.Format("2006-01-02"),
+		"yearsOnPlatform": years,
+		"stats": map[string]interface{}{
+			"completedProjects":     stats.CompletedProjects,
+			"responsesCount":        stats.ResponsesCount,
+			"medianResponseMinutes": medianResponse,
+		},
+		"badges":       masterBadges(master, stats, years),
+		"availability": summary,
+		"portfolio":    portfolioItems(db, master.ID, lang),
+		"reviews":      latest,
+	})
+}
+
+// findMaster ищет мастера по id или адресу профиля
+func findMaster(db *gorm.DB, idOrSlug string) (*models.Master, error) {
+	var master models.Master
+	var err error
+	if uuidPattern.MatchString(idOrSlug) {
+		err = db.First(&master, "id = ?", idOrSlug).Error
+	} else {
+		err = db.First(&master, "slug = ?", idOrSlug).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &master, nil
+}
+
+func loadMasterStats(db *gorm.DB, masterID string) masterStats {
+	var stats masterStats
+	db.Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM projects WHERE assigned_master = ? AND status = 'completed') AS completed_projects,
+			(SELECT COUNT(*) FROM responses WHERE master_id = ?) AS responses_count,
+			(SELECT percentile_cont(0.5) WITHIN GROUP (
+				ORDER BY EXTRACT(EPOCH FROM r.created_at - COALESCE(p.published_at, p.created_at)) / 60)
+			 FROM responses r JOIN projects p ON p.id = r.project_id
+			 WHERE r.master_id = ?) AS median_response_minutes
+	`, masterID, masterID, masterID).Scan(&stats)
+	return stats
+}
+
+func masterBadges(master *models.Master, stats masterStats, years int) []string {
+	badges := []string{}
+	if master.VerifiedAt != nil {
+		badges = append(badges, BadgeVerified)
+	}
+	if master.Rating >= 4.7 && master.ReviewsCount >= 10 {
+		badges = append(badges, BadgeTopRated)
+	}
+	if stats.MedianResponseMinutes != nil && *stats.MedianResponseMinutes <= 60 && stats.ResponsesCount >= 5 {
+		badges = append(badges, BadgeFastResponder)
+	}
+	if stats.CompletedProjects >= 20 {
+		badges = append(badges, BadgeExperienced)
+	}
+	if years >= 3 {
+		badges = append(badges, BadgeVeteran)
+	}
+	return badges
+}
+
+// validMasterSlug - адрес, заданный мастером вручную. Похожие на UUID
+// запрещены: по ним профиль ищется как по id
+func validMasterSlug(value string) bool {
+	return len(value) >= 3 && len(value) <= slug.MaxLength &&
+		slugPattern.MatchString(value) && !uuidPattern.MatchString(value)
+}
+
+// uniqueMasterSlug строит свободный адрес из имени: ivan-petrov, ivan-petrov-2, ...
+func uniqueMasterSlug(db *gorm.DB, name, masterID string) *string {
+	base := slug.Make(name)
+	if len(base) < 3 {
+		return nil
+	}
+	for i := 1; i <= 100; i++ {
+		candidate := base
+		if i > 1 {
+			suffix := fmt.Sprintf("-%d", i)
+			candidate = strings.TrimRight(base[:min(len(base), slug.MaxLength-len(suffix))], "-") + suffix
+		}
+		var taken int64
+		db.Model(&models.Master{}).Where("slug = ? AND id <> ?", candidate, masterID).Count(&taken)
+		if taken == 0 {
+			return &candidate
+		}
+	}
+	return nil
+}
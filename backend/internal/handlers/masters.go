@@ -2,38 +2,114 @@ package handlers
 
 import (
 	"encoding/json"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"refurnish/internal/catalog"
+	"refurnish/internal/cities"
 	"refurnish/internal/config"
 	"refurnish/internal/models"
+	"refurnish/internal/notify"
+	"refurnish/internal/recommend"
+
+	"gorm.io/gorm"
 )
 
+// ListMasters - GET /api/masters
+// Фильтры: city, radius (км), category, priceMin, priceMax, minRating,
+// verified=true, available=true, q.
+// Сортировка sort=rank|rating|reviews|price_asc|price_desc|newest|distance,
+// страницы limit/offset.
 func ListMasters(w http.ResponseWriter, r *http.Request) {
 	db := config.GetDB()
+	params := r.URL.Query()
+
+	filter := recommend.SearchFilter{
+		Query:         params.Get("q"),
+		Sort:          params.Get("sort"),
+		VerifiedOnly:  params.Get("verified") == "true",
+		AvailableOnly: params.Get("available") == "true",
+		Limit:         20,
+	}
+	if l, err := strconv.Atoi(params.Get("limit")); err == nil && l > 0 && l <= 100 {
+		filter.Limit = l
+	}
+	if o, err := strconv.Atoi(params.Get("offset")); err == nil && o > 0 {
+		filter.Offset = o
+	}
+	filter.PriceMin, _ = strconv.Atoi(params.Get("priceMin"))
+	filter.PriceMax, _ = strconv.Atoi(params.Get("priceMax"))
+	filter.MinRating, _ = strconv.ParseFloat(params.Get("minRating"), 64)
+	if radius, err := strconv.ParseFloat(params.Get("radius"), 64); err == nil && radius > 0 && radius <= 500 {
+		filter.RadiusKm = radius
+	}
+
+	if value := params.Get("city"); value != "" {
+		if city, err := cities.Find(db, value); err == nil {
+			filter.City = city
+		} else {
+			filter.CityName = cities.Normalize(db, value)
+		}
+	}
 
-	var masters []models.Master
-	if err := db.Preload("User").
-		Find(&masters).Error; err != nil {
+	// Фильтр по специализации: подходят мастера родительских и дочерних категорий
+	if value := params.Get("category"); value != "" {
+		category, err := catalog.Resolve(db, value)
+		if err != nil {
+			jsonResponse(w, map[string]interface{}{"items": []interface{}{}, "total": 0})
+			return
+		}
+		slugs, err := catalog.RelatedSlugs(db, category.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		filter.Specializations = slugs
+	}
+
+	results, total, err := recommend.Search(db, filter)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	var response []map[string]interface{}
-	for _, master := range masters {
-		response = append(response, map[string]interface{}{
+	items := []map[string]interface{}{}
+	for _, result := range results {
+		master := result.Master
+		item := map[string]interface{}{
 			"id":              master.ID,
 			"userId":          master.UserID,
+			"slug":            master.Slug,
 			"name":            master.Name,
 			"description":     master.Description,
 			"city":            master.City,
 			"specializations": master.Specializations,
 			"priceFrom":       master.PriceFrom,
+			"serviceRadiusKm": master.ServiceRadiusKm,
 			"rating":          master.Rating,
-			"email":           master.User.Email,
-		})
+			"reviewsCount":    master.ReviewsCount,
+			"verified":        master.VerifiedAt != nil,
+			"score":           result.Score,
+		}
+		if result.DistanceKm != nil {
+			item["distanceKm"] = math.Round(*result.DistanceKm)
+		}
+		if result.LastActiveAt != nil {
+			item["lastActiveAt"] = result.LastActiveAt.Format(time.RFC3339)
+		}
+		items = append(items, item)
 	}
 
-	json.NewEncoder(w).Encode(response)
+	jsonResponse(w, map[string]interface{}{
+		"items":  items,
+		"total":  total,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
 }
 
 // Обновление профиля мастера
@@ -46,6 +122,8 @@ func UpdateMasterProfile(w http.ResponseWriter, r *http.Request) {
 		City            string   `json:"city"`
 		Specializations []string `json:"specializations"`
 		PriceFrom       int      `json:"priceFrom"`
+		ServiceRadiusKm int      `json:"serviceRadiusKm"`
+		Slug            *string  `json:"slug"` // пустая строка - сгенерировать из имени
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -55,6 +133,12 @@ func UpdateMasterProfile(w http.ResponseWriter, r *http.Request) {
 
 	db := config.GetDB()
 
+	specializations, err := catalog.ResolveSlugs(db, req.Specializations)
+	if err != nil {
+		http.Error(w, "Неизвестная специализация", http.StatusBadRequest)
+		return
+	}
+
 	// Находим мастера по user_id
 	var master models.Master
 	if err := db.Where("user_id = ?", userID).First(&master).Error; err != nil {
@@ -62,21 +146,58 @@ func UpdateMasterProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Проверка подтверждала имя по документам: после смены имени её нужно пройти заново
+	reverify := master.VerifiedAt != nil && strings.TrimSpace(req.Name) != strings.TrimSpace(master.Name)
+
 	// Обновляем поля
 	master.Name = req.Name
 	master.Description = req.Description
-	master.City = req.City
-	master.Specializations = req.Specializations
+	master.City = cities.Normalize(db, req.City)
+	master.Specializations = specializations
 	master.PriceFrom = req.PriceFrom
+	if req.ServiceRadiusKm >= 0 && req.ServiceRadiusKm <= 500 {
+		master.ServiceRadiusKm = req.ServiceRadiusKm
+	}
+
+	if req.Slug != nil {
+		value := strings.ToLower(strings.TrimSpace(*req.Slug))
+		master.Slug = nil
+		if value != "" {
+			if !validMasterSlug(value) {
+				http.Error(w, "Адрес профиля: 3-60 латинских букв, цифр и дефисов", http.StatusBadRequest)
+				return
+			}
+			var taken int64
+			db.Model(&models.Master{}).Where("slug = ? AND id <> ?", value, master.ID).Count(&taken)
+			if taken > 0 {
+				http.Error(w, "Этот адрес профиля уже занят", http.StatusConflict)
+				return
+			}
+			master.Slug = &value
+		}
+	}
+	if master.Slug == nil && master.Name != "" {
+		master.Slug = uniqueMasterSlug(db, master.Name, master.ID)
+	}
+
+	if reverify {
+		master.VerifiedAt = nil
+	}
 
 	if err := db.Save(&master).Error; err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if reverify {
+		notify.Send(db, userID, notify.VerificationStatus, map[string]interface{}{"status": "reset"})
+		log.Printf("🪪 Мастер %s сменил имя, отметка о проверке снята", master.ID)
+	}
 
-	json.NewEncoder(w).Encode(map[string]string{
+	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":   "updated",
 		"masterId": master.ID,
+		"slug":     master.Slug,
+		"verified": master.VerifiedAt != nil,
 	})
 }
 
@@ -95,12 +216,14 @@ func GetMasterProfile(w http.ResponseWriter, r *http.Request) {
 	db.First(&user, userID)
 
 	response := map[string]interface{}{
-		"id":     master.ID,
-		"name":   user.Email,
-		"email":  user.Email,
-		"phone":  "+79213946509",
-		"city":   master.City,
-		"rating": master.Rating,
+		"id":           master.ID,
+		"slug":         master.Slug,
+		"name":         user.Email,
+		"email":        user.Email,
+		"phone":        "+79213946509",
+		"city":         master.City,
+		"rating":       master.Rating,
+		"reviewsCount": master.ReviewsCount,
 	}
 
 	jsonResponse(w, response)
@@ -118,7 +241,7 @@ func MasterAssignedProjects(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var projects []models.Project
-	db.Preload("Client.User").Where("master_id = ?", master.ID).Find(&projects)
+	db.Preload("Client.User").Where("assigned_master = ?", master.ID).Find(&projects)
 
 	var result []map[string]interface{}
 	for _, project := range projects {
@@ -138,3 +261,14 @@ func MasterAssignedProjects(w http.ResponseWriter, r *http.Request) {
 
 	jsonResponse(w, result)
 }
+
+// currentMaster возвращает профиль мастера текущего пользователя
+func currentMaster(db *gorm.DB, r *http.Request) (*models.Master, error) {
+	userID, _ := r.Context().Value("user_id").(string)
+
+	var master models.Master
+	if err := db.Where("user_id = ?", userID).First(&master).Error; err != nil {
+		return nil, err
+	}
+	return &master, nil
+}
@@ -0,0 +1,547 @@
+// internal/handlers/moderation.go
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+	"refurnish/internal/moderation"
+	"refurnish/internal/notify"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// reviewKind - вид отзыва и то, как показать его модератору. Отзывы
+// о мастерах и о клиентах модерируются одними обработчиками ниже.
+type reviewKind struct {
+	moderation.Kind
+	// find загружает отзывы по условиям query и отдаёт их для модератора
+	find func(query *gorm.DB) ([]map[string]interface{}, error)
+	// subject - профиль текущего пользователя, которому адресованы отзывы этого вида
+	subject func(db *gorm.DB, r *http.Request) (string, error)
+}
+
+var (
+	masterReviews = reviewKind{
+		Kind: moderation.MasterReview,
+		find: func(query *gorm.DB) ([]map[string]interface{}, error) {
+			var reviews []models.Review
+			if err := query.Preload("Client").Preload("Project").Preload("Photos").Find(&reviews).Error; err != nil {
+				return nil, err
+			}
+			items := []map[string]interface{}{}
+			for _, review := range reviews {
+				items = append(items, moderatedReviewJSON(review))
+			}
+			return items, nil
+		},
+		subject: func(db *gorm.DB, r *http.Request) (string, error) {
+			master, err := currentMaster(db, r)
+			if err != nil {
+				return "", err
+			}
+			return master.ID, nil
+		},
+	}
+
+	clientReviews = reviewKind{
+		Kind: moderation.ClientReview,
+		find: func(query *gorm.DB) ([]map[string]interface{}, error) {
+			var reviews []models.ClientReview
+			if err := query.Preload("Master").Preload("Client").Preload("Project").Find(&reviews).Error; err != nil {
+				return nil, err
+			}
+			items := []map[string]interface{}{}
+			for _, review := range reviews {
+				items = append(items, moderatedClientReviewJSON(review))
+			}
+			return items, nil
+		},
+		subject: func(db *gorm.DB, r *http.Request) (string, error) {
+			var client models.Client
+			if err := db.Where("user_id = ?", r.Context().Value("user_id").(string)).First(&client).Error; err != nil {
+				return "", err
+			}
+			return client.ID, nil
+		},
+	}
+)
+
+// item - один отзыв для модератора
+func (k reviewKind) item(db *gorm.DB, id string) map[string]interface{} {
+	items, err := k.find(db.Where("id = ?", id))
+	if err != nil || len(items) == 0 {
+		return map[string]interface{}{"id": id}
+	}
+	return items[0]
+}
+
+func reviewKindByName(name string) reviewKind {
+	if name == moderation.ClientReview.Name {
+		return clientReviews
+	}
+	return masterReviews
+}
+
+// ModerationQueue - GET /api/admin/reviews?status=pending&limit=&offset=
+// Очередь модерации: по умолчанию отзывы с флагами, ждущие решения
+func ModerationQueue(w http.ResponseWriter, r *http.Request) {
+	moderationQueue(w, r, masterReviews)
+}
+
+// ClientReviewModerationQueue - GET /api/admin/client-reviews?status=pending&limit=&offset=
+// То же для отзывов мастеров о клиентах
+func ClientReviewModerationQueue(w http.ResponseWriter, r *http.Request) {
+	moderationQueue(w, r, clientReviews)
+}
+
+func moderationQueue(w http.ResponseWriter, r *http.Request, kind reviewKind) {
+	db := config.GetDB()
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = moderation.StatusPending
+	}
+	limit := 50
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 200 {
+		limit = l
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	items, err := kind.find(db.Where("moderation_status = ?", status).
+		Order("created_at").Limit(limit).Offset(offset))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, items)
+}
+
+// ApproveReview - POST /api/admin/reviews/{id}/approve
+func ApproveReview(w http.ResponseWriter, r *http.Request) {
+	setReviewStatus(w, r, masterReviews, moderation.StatusApproved, moderation.ActionApproved)
+}
+
+// RejectReview - POST /api/admin/reviews/{id}/reject
+func RejectReview(w http.ResponseWriter, r *http.Request) {
+	setReviewStatus(w, r, masterReviews, moderation.StatusRejected, moderation.ActionRejected)
+}
+
+// ApproveClientReview - POST /api/admin/client-reviews/{id}/approve
+func ApproveClientReview(w http.ResponseWriter, r *http.Request) {
+	setReviewStatus(w, r, clientReviews, moderation.StatusApproved, moderation.ActionApproved)
+}
+
+// RejectClientReview - POST /api/admin/client-reviews/{id}/reject
+func RejectClientReview(w http.ResponseWriter, r *http.Request) {
+	setReviewStatus(w, r, clientReviews, moderation.StatusRejected, moderation.ActionRejected)
+}
+
+func setReviewStatus(w http.ResponseWriter, r *http.Request, kind reviewKind, status, action string) {
+	db := config.GetDB()
+	adminID := r.Context().Value("user_id").(string)
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	parseJSON(r, &req) // причина необязательна при одобрении
+	reason := strings.TrimSpace(req.Reason)
+	if status == moderation.StatusRejected && reason == "" {
+		http.Error(w, "Укажите причину отклонения", http.StatusBadRequest)
+		return
+	}
+
+	review, err := moderation.Load(db, kind.Kind, chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Отзыв не найден", http.StatusNotFound)
+		return
+	}
+	if review.ModerationStatus == status {
+		http.Error(w, "Отзыв уже в этом статусе", http.StatusConflict)
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		return moderation.SetStatus(tx, kind.Kind, review, status, &adminID, action, reason)
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("🛡️ Отзыв %s (%s): %s (модератор %s)", review.ID, kind.Name, status, adminID)
+
+	jsonResponse(w, kind.item(db, review.ID))
+}
+
+// EditReview - PUT /api/admin/reviews/{id}
+// Модератор правит текст (например, убирает оскорбление), сохраняя оценку.
+// Прежний текст остаётся в журнале.
+func EditReview(w http.ResponseWriter, r *http.Request) {
+	editReview(w, r, masterReviews)
+}
+
+// EditClientReview - PUT /api/admin/client-reviews/{id}
+func EditClientReview(w http.ResponseWriter, r *http.Request) {
+	editReview(w, r, clientReviews)
+}
+
+func editReview(w http.ResponseWriter, r *http.Request, kind reviewKind) {
+	db := config.GetDB()
+	adminID := r.Context().Value("user_id").(string)
+
+	var req struct {
+		Text   string `json:"text"`
+		Reason string `json:"reason"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	text := strings.TrimSpace(req.Text)
+	if len([]rune(text)) > maxReviewLength {
+		http.Error(w, "Отзыв не длиннее 2000 символов", http.StatusBadRequest)
+		return
+	}
+
+	review, err := moderation.Load(db, kind.Kind, chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Отзыв не найден", http.StatusNotFound)
+		return
+	}
+	if review.Text != text {
+		previous := review.Text
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Table(kind.Table).Where("id = ?", review.ID).Update("text", text).Error; err != nil {
+				return err
+			}
+			return moderation.Record(tx, kind.Kind, review.ID, &adminID, moderation.ActionEdited, strings.TrimSpace(req.Reason),
+				map[string]interface{}{"before": previous, "after": text})
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	jsonResponse(w, kind.item(db, review.ID))
+}
+
+// ReviewModerationLog - GET /api/admin/reviews/{id}/log
+func ReviewModerationLog(w http.ResponseWriter, r *http.Request) {
+	moderationLog(w, r, masterReviews)
+}
+
+// ClientReviewModerationLog - GET /api/admin/client-reviews/{id}/log
+func ClientReviewModerationLog(w http.ResponseWriter, r *http.Request) {
+	moderationLog(w, r, clientReviews)
+}
+
+func moderationLog(w http.ResponseWriter, r *http.Request, kind reviewKind) {
+	db := config.GetDB()
+
+	var actions []models.ModerationAction
+	if err := db.Where(kind.Column+" = ?", chi.URLParam(r, "id")).Order("created_at").Find(&actions).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	items := []map[string]interface{}{}
+	for _, a := range actions {
+		items = append(items, map[string]interface{}{
+			"id":        a.ID,
+			"actorId":   a.ActorID,
+			"action":    a.Action,
+			"reason":    a.Reason,
+			"details":   a.Details,
+			"createdAt": a.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	jsonResponse(w, items)
+}
+
+// DisputeReview - POST /api/master/reviews/{id}/dispute
+// Мастер оспаривает отзыв о себе. Отзыв остаётся опубликованным,
+// пока модератор не примет решение.
+func DisputeReview(w http.ResponseWriter, r *http.Request) {
+	openDispute(w, r, masterReviews)
+}
+
+// DisputeClientReview - POST /api/client/reviews/{id}/dispute
+// Клиент оспаривает отзыв мастера о себе
+func DisputeClientReview(w http.ResponseWriter, r *http.Request) {
+	openDispute(w, r, clientReviews)
+}
+
+func openDispute(w http.ResponseWriter, r *http.Request, kind reviewKind) {
+	db := config.GetDB()
+	userID := r.Context().Value("user_id").(string)
+
+	subjectID, err := kind.subject(db, r)
+	if err != nil {
+		http.Error(w, "Профиль не найден", http.StatusNotFound)
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" || len([]rune(reason)) > maxReviewLength {
+		http.Error(w, "Причина должна быть от 1 до 2000 символов", http.StatusBadRequest)
+		return
+	}
+
+	review, err := moderation.Load(db, kind.Kind, chi.URLParam(r, "id"), kind.Subject+" = ?", subjectID)
+	if err != nil {
+		http.Error(w, "Отзыв не найден", http.StatusNotFound)
+		return
+	}
+	if review.ModerationStatus == moderation.StatusRejected {
+		http.Error(w, "Отзыв уже снят с публикации", http.StatusConflict)
+		return
+	}
+
+	var open int64
+	db.Model(&models.ReviewDispute{}).Where(kind.Column+" = ? AND status = 'open'", review.ID).Count(&open)
+	if open > 0 {
+		http.Error(w, "Спор по этому отзыву уже рассматривается", http.StatusConflict)
+		return
+	}
+
+	dispute := models.ReviewDispute{
+		Kind:     kind.Name,
+		OpenedBy: userID,
+		Reason:   reason,
+		Status:   "open",
+	}
+	if kind.Kind == moderation.ClientReview {
+		dispute.ClientReviewID = &review.ID
+	} else {
+		dispute.ReviewID = &review.ID
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&dispute).Error; err != nil {
+			return err
+		}
+		return moderation.Record(tx, kind.Kind, review.ID, &userID, moderation.ActionDisputeOpened, reason,
+			map[string]interface{}{"disputeId": dispute.ID})
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("⚖️ Пользователь %s оспаривает отзыв %s (%s)", userID, review.ID, kind.Name)
+
+	jsonResponse(w, disputeJSON(dispute))
+}
+
+// ReviewDisputes - GET /api/admin/disputes?status=open&kind=
+// Споры по отзывам обоих видов; kind=master или client оставляет один вид
+func ReviewDisputes(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = "open"
+	}
+
+	query := db.Where("status = ?", status)
+	if name := r.URL.Query().Get("kind"); name != "" {
+		if _, ok := moderation.KindByName(name); !ok {
+			http.Error(w, "kind должен быть master или client", http.StatusBadRequest)
+			return
+		}
+		query = query.Where("kind = ?", name)
+	}
+
+	var disputes []models.ReviewDispute
+	err := query.Preload("Review").Preload("Review.Client").Preload("Review.Project").Preload("Review.Photos").
+		Preload("ClientReview").Preload("ClientReview.Master").Preload("ClientReview.Client").Preload("ClientReview.Project").
+		Order("created_at").Limit(200).
+		Find(&disputes).Error
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	items := []map[string]interface{}{}
+	for _, d := range disputes {
+		item := disputeJSON(d)
+		switch {
+		case d.Review != nil:
+			item["review"] = moderatedReviewJSON(*d.Review)
+		case d.ClientReview != nil:
+			item["review"] = moderatedClientReviewJSON(*d.ClientReview)
+		}
+		items = append(items, item)
+	}
+
+	jsonResponse(w, items)
+}
+
+// ResolveDispute - POST /api/admin/disputes/{id}/resolve
+// accept=true снимает отзыв с публикации, false - оставляет как есть
+func ResolveDispute(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+	adminID := r.Context().Value("user_id").(string)
+
+	var req struct {
+		Accept     bool   `json:"accept"`
+		Resolution string `json:"resolution"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	resolution := strings.TrimSpace(req.Resolution)
+	if resolution == "" {
+		http.Error(w, "Укажите решение", http.StatusBadRequest)
+		return
+	}
+
+	var dispute models.ReviewDispute
+	if err := db.First(&dispute, "id = ?", chi.URLParam(r, "id")).Error; err != nil {
+		http.Error(w, "Спор не найден", http.StatusNotFound)
+		return
+	}
+	kind := reviewKindByName(dispute.Kind)
+	review, err := moderation.Load(db, kind.Kind, disputeReviewID(dispute))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Спор не найден", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if dispute.Status != "open" {
+		http.Error(w, "Спор уже рассмотрен", http.StatusConflict)
+		return
+	}
+
+	status, action := "rejected", moderation.ActionDisputeDecline
+	if req.Accept {
+		status, action = "accepted", moderation.ActionDisputeAccept
+	}
+
+	now := time.Now()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&dispute).Updates(map[string]interface{}{
+			"status":      status,
+			"resolution":  resolution,
+			"resolved_by": adminID,
+			"resolved_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		if req.Accept && review.ModerationStatus != moderation.StatusRejected {
+			return moderation.SetStatus(tx, kind.Kind, review, moderation.StatusRejected, &adminID, action, resolution)
+		}
+		return moderation.Record(tx, kind.Kind, review.ID, &adminID, action, resolution,
+			map[string]interface{}{"disputeId": dispute.ID})
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dispute.Status = status
+	dispute.Resolution = &resolution
+	dispute.ResolvedBy = &adminID
+	dispute.ResolvedAt = &now
+
+	var projectTitle string
+	db.Raw("SELECT title FROM projects WHERE id = ?", review.ProjectID).Scan(&projectTitle)
+	notify.Send(db, dispute.OpenedBy, notify.DisputeResolved, map[string]interface{}{
+		"reviewId":     review.ID,
+		"kind":         kind.Name,
+		"projectTitle": projectTitle,
+		"accepted":     req.Accept,
+		"resolution":   resolution,
+	})
+
+	log.Printf("⚖️ Спор %s по отзыву %s (%s): %s", dispute.ID, review.ID, kind.Name, status)
+
+	jsonResponse(w, disputeJSON(dispute))
+}
+
+// Отзыв для модератора: с флагами, IP и статусом
+func moderatedReviewJSON(review models.Review) map[string]interface{} {
+	clientName := ""
+	if review.Client != nil {
+		clientName = review.Client.Name
+	}
+	item := reviewJSON(review, clientName)
+	item["masterId"] = review.MasterID
+	item["clientId"] = review.ClientID
+	item["flags"] = review.Flags
+	item["ip"] = review.IP
+	if review.Project != nil {
+		item["projectTitle"] = review.Project.Title
+	}
+	return item
+}
+
+// То же для отзыва мастера о клиенте
+func moderatedClientReviewJSON(review models.ClientReview) map[string]interface{} {
+	item := clientReviewJSON(review)
+	item["masterId"] = review.MasterID
+	item["clientId"] = review.ClientID
+	item["flags"] = review.Flags
+	item["ip"] = review.IP
+	if review.Master != nil {
+		item["masterName"] = review.Master.Name
+	}
+	if review.Client != nil {
+		item["clientName"] = review.Client.Name
+	}
+	if review.Project != nil {
+		item["projectTitle"] = review.Project.Title
+	}
+	return item
+}
+
+// disputeReviewID - id оспариваемого отзыва любого вида
+func disputeReviewID(d models.ReviewDispute) string {
+	if d.ClientReviewID != nil {
+		return *d.ClientReviewID
+	}
+	if d.ReviewID != nil {
+		return *d.ReviewID
+	}
+	return ""
+}
+
+func disputeJSON(d models.ReviewDispute) map[string]interface{} {
+	result := map[string]interface{}{
+		"id":        d.ID,
+		"kind":      d.Kind,
+		"reviewId":  disputeReviewID(d),
+		"openedBy":  d.OpenedBy,
+		"reason":    d.Reason,
+		"status":    d.Status,
+		"createdAt": d.CreatedAt.Format(time.RFC3339),
+	}
+	if d.ResolvedAt != nil {
+		result["resolution"] = d.Resolution
+		result["resolvedAt"] = d.ResolvedAt.Format(time.RFC3339)
+	}
+	return result
+}
@@ -0,0 +1,261 @@
+// internal/handlers/notifications.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+	"refurnish/internal/notify"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm/clause"
+)
+
+// MyNotifications - GET /api/notifications
+func MyNotifications(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+	db := config.GetDB()
+
+	limit := 50
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := db.Where("user_id = ?", userID)
+	if r.URL.Query().Get("unread") == "true" {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&notifications).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var unread int64
+	db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&unread)
+
+	items := []map[string]interface{}{}
+	for _, n := range notifications {
+		items = append(items, map[string]interface{}{
+			"id":        n.ID,
+			"type":      n.Type,
+			"title":     n.Title,
+			"body":      n.Body,
+			"data":      n.Data,
+			"read":      n.ReadAt != nil,
+			"createdAt": n.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"items":  items,
+		"unread": unread,
+	})
+}
+
+// MarkNotificationRead - POST /api/notifications/{id}/read
+func MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+
+	result := config.GetDB().Model(&models.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", chi.URLParam(r, "id"), userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		http.Error(w, result.Error.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"status":  "read",
+		"updated": result.RowsAffected,
+	})
+}
+
+// MarkNotificationUnread - POST /api/notifications/{id}/unread
+func MarkNotificationUnread(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+
+	result := config.GetDB().Model(&models.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NOT NULL", chi.URLParam(r, "id"), userID).
+		Update("read_at", nil)
+	if result.Error != nil {
+		http.Error(w, result.Error.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"status":  "unread",
+		"updated": result.RowsAffected,
+	})
+}
+
+// MarkAllNotificationsRead - POST /api/notifications/read-all
+func MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+
+	result := config.GetDB().Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		http.Error(w, result.Error.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"status":  "read",
+		"updated": result.RowsAffected,
+	})
+}
+
+// NotificationPreferences - GET /api/notifications/preferences
+func NotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+	db := config.GetDB()
+
+	var user models.User
+	if err := db.Select("id", "language").First(&user, "id = ?", userID).Error; err != nil {
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+		return
+	}
+
+	matrix := notify.Preferences(db, userID)
+	types := []map[string]interface{}{}
+	for _, kind := range notify.Kinds {
+		types = append(types, map[string]interface{}{
+			"type":     kind,
+			"channels": matrix[kind],
+		})
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"language": user.Language,
+		"channels": notify.Channels,
+		"types":    types,
+	})
+}
+
+// UpdateNotificationPreferences - PUT /api/notifications/preferences
+// {"language": "en", "preferences": [{"type": "...", "channel": "email", "enabled": false}]}
+func UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+	db := config.GetDB()
+
+	var req struct {
+		Language    string `json:"language"`
+		Preferences []struct {
+			Type    string `json:"type"`
+			Channel string `json:"channel"`
+			Enabled bool   `json:"enabled"`
+		} `json:"preferences"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	if req.Language != "" {
+		if req.Language != "ru" && req.Language != "en" {
+			http.Error(w, "Поддерживаются языки ru и en", http.StatusBadRequest)
+			return
+		}
+		if err := db.Model(&models.User{}).Where("id = ?", userID).Update("language", req.Language).Error; err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	for _, p := range req.Preferences {
+		if !notify.IsKind(p.Type) || !notify.IsChannel(p.Channel) {
+			http.Error(w, "Неизвестный тип уведомления или канал: "+p.Type+"/"+p.Channel, http.StatusBadRequest)
+			return
+		}
+	}
+
+	now := time.Now()
+	for _, p := range req.Preferences {
+		pref := models.NotificationPreference{
+			UserID:    userID,
+			Type:      p.Type,
+			Channel:   p.Channel,
+			Enabled:   p.Enabled,
+			UpdatedAt: now,
+		}
+		err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}, {Name: "channel"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+		}).Create(&pref).Error
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	NotificationPreferences(w, r)
+}
+
+// NotificationDeliveries - GET /api/admin/notifications/deliveries?status=&userId=
+func NotificationDeliveries(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	query := db.Model(&models.NotificationDelivery{})
+	if status := r.URL.Query().Get("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if userID := r.URL.Query().Get("userId"); userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var deliveries []models.NotificationDelivery
+	if err := query.Order("created_at DESC").Limit(200).Find(&deliveries).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	items := []map[string]interface{}{}
+	for _, d := range deliveries {
+		item := map[string]interface{}{
+			"id":             d.ID,
+			"notificationId": d.NotificationID,
+			"userId":         d.UserID,
+			"type":           d.Type,
+			"channel":        d.Channel,
+			"address":        d.Address,
+			"status":         d.Status,
+			"attempts":       d.Attempts,
+			"lastError":      d.LastError,
+			"createdAt":      d.CreatedAt.Format(time.RFC3339),
+		}
+		if d.Status == "pending" {
+			item["nextAttemptAt"] = d.NextAttemptAt.Format(time.RFC3339)
+		}
+		if d.SentAt != nil {
+			item["sentAt"] = d.SentAt.Format(time.RFC3339)
+		}
+		items = append(items, item)
+	}
+
+	jsonResponse(w, items)
+}
+
+// RetryNotificationDelivery - POST /api/admin/notifications/deliveries/{id}/retry
+func RetryNotificationDelivery(w http.ResponseWriter, r *http.Request) {
+	ok, err := notify.Retry(config.GetDB(), chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.Error(w, "Доставка не найдена или уже выполнена", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, map[string]string{"status": "pending"})
+}
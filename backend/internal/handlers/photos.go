@@ -0,0 +1,107 @@
+// internal/handlers/photos.go
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+	"refurnish/internal/uploads"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const maxProjectPhotos = 10
+
+// UploadProjectPhoto - POST /api/client/project/{id}/photos (multipart, поле "photo")
+func UploadProjectPhoto(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
+		return
+	}
+
+	var count int64
+	db.Model(&models.ProjectPhoto{}).Where("project_id = ?", project.ID).Count(&count)
+	if count >= maxProjectPhotos {
+		http.Error(w, "Можно загрузить не более 10 фотографий", http.StatusBadRequest)
+		return
+	}
+
+	url, err := uploads.SaveImage(w, r, "photo")
+	if err != nil {
+		if uploads.IsClientError(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("❌ Ошибка сохранения фото: %v", err)
+		http.Error(w, "Ошибка сохранения файла", http.StatusInternalServerError)
+		return
+	}
+
+	photo := models.ProjectPhoto{
+		ProjectID: project.ID,
+		URL:       url,
+		SortOrder: int(count),
+	}
+	if err := db.Create(&photo).Error; err != nil {
+		uploads.Remove(url)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("📷 Фото добавлено к проекту %s: %s", project.ID, url)
+
+	jsonResponse(w, map[string]interface{}{
+		"id":  photo.ID,
+		"url": photo.URL,
+	})
+}
+
+// DeleteProjectPhoto - DELETE /api/client/project/{id}/photos/{photoId}
+func DeleteProjectPhoto(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
+		return
+	}
+
+	var photo models.ProjectPhoto
+	if err := db.First(&photo, "id = ? AND project_id = ?", chi.URLParam(r, "photoId"), project.ID).Error; err != nil {
+		http.Error(w, "Фото не найдено", http.StatusNotFound)
+		return
+	}
+
+	if err := db.Delete(&photo).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// Фото могло попасть в портфолио мастера - убираем и оттуда
+	db.Where("url = ?", photo.URL).Delete(&models.PortfolioPhoto{})
+	if err := uploads.Remove(photo.URL); err != nil {
+		log.Printf("⚠️  Не удалось удалить файл %s: %v", photo.URL, err)
+	}
+
+	jsonResponse(w, map[string]string{
+		"status":  "deleted",
+		"photoId": photo.ID,
+	})
+}
+
+// projectPhotos возвращает фотографии проекта в порядке загрузки
+func projectPhotos(projectID string) []map[string]interface{} {
+	var photos []models.ProjectPhoto
+	config.GetDB().Where("project_id = ?", projectID).Order("sort_order, created_at").Find(&photos)
+
+	result := []map[string]interface{}{}
+	for _, p := range photos {
+		result = append(result, map[string]interface{}{
+			"id":  p.ID,
+			"url": p.URL,
+		})
+	}
+	return result
+}
@@ -0,0 +1,416 @@
+// internal/handlers/portfolio.go
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"refurnish/internal/catalog"
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+	"refurnish/internal/portfolio"
+	"refurnish/internal/uploads"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	maxPortfolioItems  = 100
+	maxPortfolioPhotos = 20
+)
+
+type portfolioItemRequest struct {
+	Title       string `json:"title"`
+	Category    string `json:"category"` // slug или id
+	Description string `json:"description"`
+	Price       *int   `json:"price"`
+	CompletedOn string `json:"completedOn"` // YYYY-MM-DD
+	SortOrder   int    `json:"sortOrder"`
+}
+
+// apply проверяет запрос и переносит поля в работу
+func (req portfolioItemRequest) apply(db *gorm.DB, item *models.PortfolioItem) error {
+	title := strings.TrimSpace(req.Title)
+	if title == "" || len([]rune(title)) > 200 {
+		return errors.New("Название должно быть от 1 до 200 символов")
+	}
+	if req.Price != nil && *req.Price < 0 {
+		return errors.New("Цена не может быть отрицательной")
+	}
+
+	item.CategoryID = nil
+	if req.Category != "" {
+		category, err := catalog.Resolve(db, req.Category)
+		if err != nil {
+			return errors.New("Неизвестная категория")
+		}
+		item.CategoryID = &category.ID
+	}
+
+	item.CompletedOn = nil
+	if req.CompletedOn != "" {
+		date, err := time.Parse("2006-01-02", req.CompletedOn)
+		if err != nil {
+			return errors.New("Неверная дата завершения")
+		}
+		if date.After(time.Now()) {
+			return errors.New("Дата завершения не может быть в будущем")
+		}
+		item.CompletedOn = &date
+	}
+
+	item.Title = title
+	item.Description = strings.TrimSpace(req.Description)
+	item.Price = req.Price
+	item.SortOrder = req.SortOrder
+	return nil
+}
+
+// MyPortfolio - GET /api/master/portfolio
+func MyPortfolio(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, portfolioItems(db, master.ID, requestLang(r)))
+}
+
+// MasterPortfolio - GET /api/masters/{id}/portfolio, {id} - id или адрес профиля
+func MasterPortfolio(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := findMaster(db, chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, portfolioItems(db, master.ID, requestLang(r)))
+}
+
+// CreatePortfolioItem - POST /api/master/portfolio
+func CreatePortfolioItem(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var req portfolioItemRequest
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	var count int64
+	db.Model(&models.PortfolioItem{}).Where("master_id = ?", master.ID).Count(&count)
+	if count >= maxPortfolioItems {
+		http.Error(w, "В портфолио не более 100 работ", http.StatusBadRequest)
+		return
+	}
+
+	item := models.PortfolioItem{MasterID: master.ID}
+	if err := req.apply(db, &item); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := db.Create(&item).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("🖼️ Мастер %s добавил работу в портфолио: %s", master.ID, item.Title)
+
+	jsonResponse(w, portfolioItemJSON(item, requestLang(r)))
+}
+
+// UpdatePortfolioItem - PUT /api/master/portfolio/{id}
+func UpdatePortfolioItem(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	item, ok := loadPortfolioItem(w, r, db)
+	if !ok {
+		return
+	}
+
+	var req portfolioItemRequest
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	if err := req.apply(db, item); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := db.Omit("Photos", "Category").Save(item).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	db.Preload("Category").Preload("Photos", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order, created_at")
+	}).First(item, "id = ?", item.ID)
+
+	jsonResponse(w, portfolioItemJSON(*item, requestLang(r)))
+}
+
+// DeletePortfolioItem - DELETE /api/master/portfolio/{id}
+func DeletePortfolioItem(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	item, ok := loadPortfolioItem(w, r, db)
+	if !ok {
+		return
+	}
+
+	if err := portfolio.Delete(db, item); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, map[string]string{
+		"status": "deleted",
+		"itemId": item.ID,
+	})
+}
+
+// UploadPortfolioPhoto - POST /api/master/portfolio/{id}/photos
+// multipart: поле "photo" и kind=before|after (по умолчанию after)
+func UploadPortfolioPhoto(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	item, ok := loadPortfolioItem(w, r, db)
+	if !ok {
+		return
+	}
+
+	if len(item.Photos) >= maxPortfolioPhotos {
+		http.Error(w, "Можно загрузить не более 20 фотографий", http.StatusBadRequest)
+		return
+	}
+
+	url, err := uploads.SaveImage(w, r, "photo")
+	if err != nil {
+		if uploads.IsClientError(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("❌ Ошибка сохранения фото: %v", err)
+		http.Error(w, "Ошибка сохранения файла", http.StatusInternalServerError)
+		return
+	}
+
+	kind := r.FormValue("kind")
+	if kind == "" {
+		kind = portfolio.After
+	}
+	if kind != portfolio.Before && kind != portfolio.After {
+		uploads.Remove(url)
+		http.Error(w, "kind должен быть before или after", http.StatusBadRequest)
+		return
+	}
+
+	sortOrder := 0
+	for _, p := range item.Photos {
+		if p.Kind == kind {
+			sortOrder++
+		}
+	}
+
+	photo := models.PortfolioPhoto{ItemID: item.ID, URL: url, Kind: kind, SortOrder: sortOrder}
+	if err := db.Create(&photo).Error; err != nil {
+		uploads.Remove(url)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"id":   photo.ID,
+		"url":  photo.URL,
+		"kind": photo.Kind,
+	})
+}
+
+// DeletePortfolioPhoto - DELETE /api/master/portfolio/{id}/photos/{photoId}
+func DeletePortfolioPhoto(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	item, ok := loadPortfolioItem(w, r, db)
+	if !ok {
+		return
+	}
+
+	var photo models.PortfolioPhoto
+	if err := db.First(&photo, "id = ? AND item_id = ?", chi.URLParam(r, "photoId"), item.ID).Error; err != nil {
+		http.Error(w, "Фото не найдено", http.StatusNotFound)
+		return
+	}
+
+	if err := db.Delete(&photo).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	portfolio.RemoveFile(db, photo.URL)
+
+	jsonResponse(w, map[string]string{
+		"status":  "deleted",
+		"photoId": photo.ID,
+	})
+}
+
+// PortfolioFromProject - POST /api/master/portfolio/from-project/{projectId}
+// Работа из завершённого проекта, если клиент дал согласие
+func PortfolioFromProject(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var project models.Project
+	if err := db.First(&project, "id = ?", chi.URLParam(r, "projectId")).Error; err != nil {
+		http.Error(w, "Проект не найден", http.StatusNotFound)
+		return
+	}
+	if project.MasterID == nil || *project.MasterID != master.ID {
+		http.Error(w, "Нет доступа", http.StatusForbidden)
+		return
+	}
+
+	item, err := portfolio.FromProject(db, project.ID)
+	if errors.Is(err, portfolio.ErrNotCompleted) || errors.Is(err, portfolio.ErrNoConsent) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	db.Preload("Category").Preload("Photos").First(item, "id = ?", item.ID)
+	jsonResponse(w, portfolioItemJSON(*item, requestLang(r)))
+}
+
+// SetPortfolioConsent - PUT /api/client/project/{id}/portfolio-consent
+// Согласие на завершённом проекте сразу добавляет работу мастеру,
+// отзыв согласия удаляет её.
+func SetPortfolioConsent(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Consent bool `json:"consent"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	if err := db.Model(&models.Project{}).Where("id = ?", project.ID).
+		Update("portfolio_consent", req.Consent).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if !req.Consent {
+		if err := portfolio.RemoveProject(db, project.ID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	} else if project.Status == "completed" {
+		if _, err := portfolio.FromProject(db, project.ID); err != nil {
+			log.Printf("⚠️  Не удалось добавить проект %s в портфолио: %v", project.ID, err)
+		}
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"projectId": project.ID,
+		"consent":   req.Consent,
+	})
+}
+
+// loadPortfolioItem загружает работу текущего мастера с фотографиями
+func loadPortfolioItem(w http.ResponseWriter, r *http.Request, db *gorm.DB) (*models.PortfolioItem, bool) {
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return nil, false
+	}
+
+	var item models.PortfolioItem
+	err = db.Preload("Category").
+		Preload("Photos", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order, created_at") }).
+		First(&item, "id = ? AND master_id = ?", chi.URLParam(r, "id"), master.ID).Error
+	if err != nil {
+		http.Error(w, "Работа не найдена", http.StatusNotFound)
+		return nil, false
+	}
+	return &item, true
+}
+
+func portfolioItems(db *gorm.DB, masterID, lang string) []map[string]interface{} {
+	var items []models.PortfolioItem
+	db.Preload("Category").
+		Preload("Photos", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order, created_at") }).
+		Where("master_id = ?", masterID).
+		Order("sort_order, completed_on DESC NULLS LAST, created_at DESC").
+		Find(&items)
+
+	result := []map[string]interface{}{}
+	for _, item := range items {
+		result = append(result, portfolioItemJSON(item, lang))
+	}
+	return result
+}
+
+func portfolioItemJSON(item models.PortfolioItem, lang string) map[string]interface{} {
+	before := []map[string]interface{}{}
+	after := []map[string]interface{}{}
+	for _, p := range item.Photos {
+		photo := map[string]interface{}{"id": p.ID, "url": p.URL}
+		if p.Kind == portfolio.Before {
+			before = append(before, photo)
+		} else {
+			after = append(after, photo)
+		}
+	}
+
+	result := map[string]interface{}{
+		"id":          item.ID,
+		"title":       item.Title,
+		"description": item.Description,
+		"price":       item.Price,
+		"projectId":   item.ProjectID,
+		"sortOrder":   item.SortOrder,
+		"before":      before,
+		"after":       after,
+		"createdAt":   item.CreatedAt.Format(time.RFC3339),
+	}
+	if item.CompletedOn != nil {
+		result["completedOn"] = item.CompletedOn.Format("2006-01-02")
+	}
+	if item.Category != nil {
+		result["category"] = map[string]interface{}{
+			"id":   item.Category.ID,
+			"slug": item.Category.Slug,
+			"name": item.Category.LocalizedName(lang),
+		}
+	}
+	return result
+}
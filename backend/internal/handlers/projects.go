@@ -2,30 +2,45 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"refurnish/internal/availability"
+	"refurnish/internal/catalog"
+	"refurnish/internal/cities"
 	"refurnish/internal/config"
+	"refurnish/internal/events"
 	"refurnish/internal/models"
+	"refurnish/internal/realtime"
+	"refurnish/internal/spec"
 
 	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// Создание проекта
+// Создание проекта. С "draft": true проект сохраняется черновиком,
+// с "publishAt" в будущем - публикуется планировщиком в указанное время.
 func CreateProject(w http.ResponseWriter, r *http.Request) {
 	userID := r.Context().Value("user_id").(string)
 
 	log.Printf("📝 Создание проекта для user_id: %s", userID)
 
 	var req struct {
-		Title         string `json:"title"`
-		Description   string `json:"description"`
-		FurnitureType string `json:"furnitureType"`
-		Budget        int    `json:"budget"`
-		Deadline      string `json:"deadline"`
-		City          string `json:"city"`
+		Title         string                 `json:"title"`
+		Description   string                 `json:"description"`
+		FurnitureType string                 `json:"furnitureType"`
+		Budget        int                    `json:"budget"`
+		Deadline      string                 `json:"deadline"`
+		City          string                 `json:"city"`
+		Spec          map[string]interface{} `json:"spec"`
+		Draft         bool                   `json:"draft"`
+		PublishAt     string                 `json:"publishAt"`
+		Visibility    string                 `json:"visibility"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -37,24 +52,30 @@ func CreateProject(w http.ResponseWriter, r *http.Request) {
 	log.Printf("📋 Данные проекта: %+v", req)
 
 	var deadline time.Time
-	var err error
-
-	// 1. Пробуем YYYY-MM-DD (стандартный формат HTML input type="date")
-	deadline, err = time.Parse("2006-01-02", req.Deadline)
-	if err != nil {
-		// 2. Пробуем MM-DD-YYYY (то что приходит сейчас)
-		deadline, err = time.Parse("01-02-2006", req.Deadline)
+	if req.Deadline != "" || !req.Draft {
+		var err error
+		deadline, err = parseDeadline(req.Deadline)
 		if err != nil {
-			// 3. Пробуем DD.MM.YYYY
-			deadline, err = time.Parse("02.01.2006", req.Deadline)
-			if err != nil {
-				log.Printf("❌ Ошибка парсинга даты '%s': %v", req.Deadline, err)
-				http.Error(w, "Неверный формат даты. Используйте YYYY-MM-DD", http.StatusBadRequest)
-				return
-			}
+			log.Printf("❌ Ошибка парсинга даты '%s': %v", req.Deadline, err)
+			http.Error(w, "Неверный формат даты. Используйте YYYY-MM-DD", http.StatusBadRequest)
+			return
 		}
 	}
 
+	publishAt, err := parsePublishAt(req.PublishAt)
+	if err != nil {
+		http.Error(w, "Неверный формат publishAt. Используйте RFC3339", http.StatusBadRequest)
+		return
+	}
+
+	if req.Visibility == "" {
+		req.Visibility = "public"
+	}
+	if !validVisibility(req.Visibility) {
+		http.Error(w, "Видимость должна быть public, invite_only или link_only", http.StatusBadRequest)
+		return
+	}
+
 	db := config.GetDB()
 
 	// Находим клиента
@@ -67,55 +88,140 @@ func CreateProject(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("✅ Найден клиент с ID: %s", client.ID)
 
+	project := models.Project{
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+		Budget:      req.Budget,
+		Deadline:    deadline,
+		// Приводим город к названию из справочника
+		City:       cities.Normalize(db, req.City),
+		ClientID:   client.ID,
+		Visibility: req.Visibility,
+	}
+
+	// Тип мебели должен быть из справочника категорий (в черновике можно не указывать)
+	if req.FurnitureType != "" || !req.Draft {
+		category, err := catalog.Resolve(db, req.FurnitureType)
+		if err != nil {
+			log.Printf("❌ Неизвестная категория '%s': %v", req.FurnitureType, err)
+			http.Error(w, "Неизвестный тип мебели", http.StatusBadRequest)
+			return
+		}
+		project.FurnitureType = category.Slug
+		project.CategoryID = &category.ID
+
+		// Старые клиенты (форма создания без блока характеристик) spec не присылают:
+		// тогда поля схемы необязательны, а заполнить их можно при редактировании
+		projectSpec, problem := validateSpec(db, category.ID, req.Spec, req.Draft || req.Spec == nil)
+		if problem != "" {
+			http.Error(w, problem, http.StatusBadRequest)
+			return
+		}
+		project.Spec = projectSpec
+	}
+
+	now := time.Now()
+	switch {
+	case req.Draft:
+		project.Status = "draft"
+	default:
+		if problems := publishProblems(db, &project, req.Spec != nil); len(problems) > 0 {
+			http.Error(w, "Проект нельзя опубликовать: "+strings.Join(problems, "; "), http.StatusBadRequest)
+			return
+		}
+		if publishAt != nil && publishAt.After(now) {
+			project.Status = "scheduled"
+			project.PublishAt = publishAt
+		} else {
+			project.Status = "published"
+			project.PublishedAt = &now
+		}
+	}
+
 	// ВАЖНО: Создаем простую структуру без сложных связей
+	var deadlineValue interface{}
+	if !project.Deadline.IsZero() {
+		deadlineValue = project.Deadline
+	}
 	projectData := map[string]interface{}{
-		"title":          req.Title,
-		"description":    req.Description,
-		"furniture_type": req.FurnitureType,
-		"budget":         req.Budget,
-		"deadline":       deadline,
-		"city":           req.City,
-		"status":         "published",
+		"title":          project.Title,
+		"description":    project.Description,
+		"furniture_type": project.FurnitureType,
+		"category_id":    project.CategoryID,
+		"spec":           project.Spec,
+		"budget":         project.Budget,
+		"deadline":       deadlineValue,
+		"city":           project.City,
+		"status":         project.Status,
+		"publish_at":     project.PublishAt,
+		"published_at":   project.PublishedAt,
+		"visibility":     project.Visibility,
 		"client_id":      client.ID,
-		"created_at":     time.Now(),
-		"updated_at":     time.Now(),
+		"created_at":     now,
+		"updated_at":     now,
+	}
+	if project.Spec == nil {
+		projectData["spec"] = "{}"
 	}
 
-	// Выполняем сырой SQL запрос
-	result := db.Exec(`
-		INSERT INTO projects (title, description, furniture_type, budget, 
-			deadline, city, status, client_id, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	// Выполняем сырой SQL запрос; событие пишется в той же транзакции
+	var projectID string
+	err = db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Raw(`
+		INSERT INTO projects (title, description, furniture_type, category_id, spec, budget, 
+			deadline, city, status, publish_at, published_at, visibility, client_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
 	`,
-		projectData["title"],
-		projectData["description"],
-		projectData["furniture_type"],
-		projectData["budget"],
-		projectData["deadline"],
-		projectData["city"],
-		projectData["status"],
-		projectData["client_id"],
-		projectData["created_at"],
-		projectData["updated_at"],
-	)
+			projectData["title"],
+			projectData["description"],
+			projectData["furniture_type"],
+			projectData["category_id"],
+			projectData["spec"],
+			projectData["budget"],
+			projectData["deadline"],
+			projectData["city"],
+			projectData["status"],
+			projectData["publish_at"],
+			projectData["published_at"],
+			projectData["visibility"],
+			projectData["client_id"],
+			projectData["created_at"],
+			projectData["updated_at"],
+		).Scan(&projectID)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		payload := map[string]interface{}{
+			"projectTitle": project.Title,
+			"clientUserId": userID,
+			"status":       project.Status,
+		}
+		if err := events.Emit(tx, events.ProjectCreated, projectID, payload); err != nil {
+			return err
+		}
+		if project.Status == "published" {
+			return events.Emit(tx, events.ProjectPublished, projectID, payload)
+		}
+		return nil
+	})
 
-	if result.Error != nil {
-		log.Printf("❌ Ошибка создания проекта: %v", result.Error)
-		http.Error(w, "Ошибка создания проекта: "+result.Error.Error(), http.StatusInternalServerError)
+	if err != nil {
+		log.Printf("❌ Ошибка создания проекта: %v", err)
+		http.Error(w, "Ошибка создания проекта: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	events.Wake()
 
-	// Получаем ID созданного проекта
-	var projectID string
-	db.Raw("SELECT id FROM projects WHERE client_id = ? ORDER BY created_at DESC LIMIT 1", client.ID).Scan(&projectID)
-
-	log.Printf("🎉 Проект успешно создан с ID: %s", projectID)
+	log.Printf("🎉 Проект успешно создан с ID: %s (статус: %s)", projectID, project.Status)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"status":    "ok",
-		"projectId": projectID,
-		"message":   "Проект успешно создан",
+		"status":        "ok",
+		"projectId":     projectID,
+		"projectStatus": project.Status,
+		"message":       "Проект успешно создан",
 	})
 }
 
@@ -123,16 +229,66 @@ func CreateProject(w http.ResponseWriter, r *http.Request) {
 func OpenProjects(w http.ResponseWriter, r *http.Request) {
 	city := r.URL.Query().Get("city")
 	furniture := r.URL.Query().Get("furniture")
+	if c := r.URL.Query().Get("category"); c != "" {
+		furniture = c
+	}
 
 	db := config.GetDB()
 
-	query := db.Model(&models.Project{}).Where("status = ?", "published")
+	// Проекты по приглашениям и по ссылке в общий список не попадают
+	query := db.Model(&models.Project{}).Where("status = ? AND visibility = ?", "published", "public")
 
 	if city != "" {
-		query = query.Where("city = ?", city)
+		query = query.Where("city = ?", cities.Normalize(db, city))
 	}
 	if furniture != "" {
-		query = query.Where("furniture_type = ?", furniture)
+		// Фильтр по категории включает все вложенные категории
+		category, err := catalog.Resolve(db, furniture)
+		if err != nil {
+			json.NewEncoder(w).Encode([]interface{}{})
+			return
+		}
+		ids, err := catalog.SubtreeIDs(db, category.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		query = query.Where("category_id IN ?", ids)
+	}
+
+	// Фильтры по спецификации: spec.material=mdf, spec.width.min=100, spec.width.max=200
+	for param, values := range r.URL.Query() {
+		if !strings.HasPrefix(param, "spec.") || len(values) == 0 {
+			continue
+		}
+		key := strings.TrimPrefix(param, "spec.")
+		op := ""
+		if i := strings.LastIndex(key, "."); i > 0 {
+			key, op = key[:i], key[i+1:]
+		}
+		if !spec.KeyPattern.MatchString(key) {
+			http.Error(w, "Неверный фильтр "+param, http.StatusBadRequest)
+			return
+		}
+
+		switch op {
+		case "":
+			query = query.Where("spec->>(?::text) = ?", key, values[0])
+		case "min", "max":
+			n, err := strconv.ParseFloat(values[0], 64)
+			if err != nil {
+				http.Error(w, "Неверное значение фильтра "+param, http.StatusBadRequest)
+				return
+			}
+			cmp := ">="
+			if op == "max" {
+				cmp = "<="
+			}
+			query = query.Where("jsonb_typeof(spec->(?::text)) = 'number' AND (spec->>(?::text))::numeric "+cmp+" ?", key, key, n)
+		default:
+			http.Error(w, "Неверный фильтр "+param, http.StatusBadRequest)
+			return
+		}
 	}
 
 	var projects []models.Project
@@ -144,16 +300,19 @@ func OpenProjects(w http.ResponseWriter, r *http.Request) {
 	var response []map[string]interface{}
 	for _, project := range projects {
 		response = append(response, map[string]interface{}{
-			"id":            project.ID,
-			"title":         project.Title,
-			"description":   project.Description,
-			"furnitureType": project.FurnitureType,
-			"budget":        project.Budget,
-			"deadline":      project.Deadline.Format("2006-01-02"),
-			"city":          project.City,
-			"status":        project.Status,
-			"clientName":    project.Client.User.Email,
-			"createdAt":     project.CreatedAt.Format(time.RFC3339),
+			"id":                 project.ID,
+			"title":              project.Title,
+			"description":        project.Description,
+			"furnitureType":      project.FurnitureType,
+			"budget":             project.Budget,
+			"deadline":           project.Deadline.Format("2006-01-02"),
+			"city":               project.City,
+			"status":             project.Status,
+			"clientName":         project.Client.User.Email,
+			"clientId":           project.ClientID,
+			"clientReputation":   project.Client.Reputation,
+			"clientReviewsCount": project.Client.ReviewsCount,
+			"createdAt":          project.CreatedAt.Format(time.RFC3339),
 		})
 	}
 
@@ -199,9 +358,14 @@ func MyProjects(w http.ResponseWriter, r *http.Request) {
 			"deadline":      project.Deadline.Format("2006-01-02"),
 			"city":          project.City,
 			"status":        project.Status,
+			"visibility":    project.Visibility,
 			"createdAt":     project.CreatedAt.Format(time.RFC3339),
 		}
 
+		if project.PublishAt != nil {
+			projectData["publishAt"] = project.PublishAt.Format(time.RFC3339)
+		}
+
 		if project.Master != nil {
 			projectData["assignedMaster"] = map[string]interface{}{
 				"id":   project.Master.ID,
@@ -215,12 +379,15 @@ func MyProjects(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-// Присвоение мастера (закрытие проекта)
-func AssignMaster(w http.ResponseWriter, r *http.Request) {
-	projectID := chi.URLParam(r, "id")
+var errAlreadyAssigned = errors.New("project already assigned")
 
+// Присвоение мастера (закрытие проекта). Назначить можно только
+// на свой опубликованный проект.
+func AssignMaster(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		MasterID string `json:"masterId"`
+		// Назначить несмотря на предупреждения о занятости мастера
+		Confirm bool `json:"confirm"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -230,6 +397,16 @@ func AssignMaster(w http.ResponseWriter, r *http.Request) {
 
 	db := config.GetDB()
 
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
+		return
+	}
+	projectID := project.ID
+	if project.Status != "published" {
+		http.Error(w, "Мастера можно назначить только на опубликованный проект", http.StatusConflict)
+		return
+	}
+
 	// Проверяем существование мастера
 	var master models.Master
 	if err := db.Where("id = ?", req.MasterID).First(&master).Error; err != nil {
@@ -237,29 +414,69 @@ func AssignMaster(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Обновляем проект
-	result := db.Model(&models.Project{}).
-		Where("id = ?", projectID).
-		Updates(map[string]interface{}{
-			"status":          "assigned",
-			"assigned_master": req.MasterID,
-			"updated_at":      time.Now(),
+	// Занятость мастера на период работ: от начала из его отклика до дедлайна
+	start := time.Now()
+	var response models.Response
+	if err := db.Where("project_id = ? AND master_id = ?", projectID, master.ID).First(&response).Error; err == nil &&
+		!response.StartDate.IsZero() {
+		start = response.StartDate
+	}
+	warnings, err := availability.Check(db, master, start, project.Deadline)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(warnings) > 0 && !req.Confirm {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":                "Мастер может быть занят в эти даты",
+			"warnings":             warnings,
+			"requiresConfirmation": true,
 		})
-
-	if result.Error != nil {
-		http.Error(w, result.Error.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	if result.RowsAffected == 0 {
-		http.Error(w, "Project not found", http.StatusNotFound)
+	// Обновляем проект и пишем событие в одной транзакции. Условие на статус
+	// не даёт двум одновременным запросам назначить двух мастеров.
+	err = db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Project{}).
+			Where("id = ? AND status = 'published'", projectID).
+			Updates(map[string]interface{}{
+				"status":          "assigned",
+				"assigned_master": req.MasterID,
+				"assigned_at":     time.Now(),
+				"updated_at":      time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errAlreadyAssigned
+		}
+		return events.Emit(tx, events.MasterAssigned, projectID, map[string]interface{}{
+			"projectTitle": project.Title,
+			"clientUserId": project.Client.UserID,
+			"masterId":     master.ID,
+			"masterUserId": master.UserID,
+		})
+	})
+
+	if errors.Is(err, errAlreadyAssigned) {
+		http.Error(w, "Мастер на проект уже назначен", http.StatusConflict)
 		return
 	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	events.Wake()
 
-	json.NewEncoder(w).Encode(map[string]string{
+	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":    "assigned",
 		"projectId": projectID,
 		"masterId":  req.MasterID,
+		"warnings":  warnings,
 	})
 }
 
@@ -287,29 +504,23 @@ func GetClientProfile(w http.ResponseWriter, r *http.Request) {
 
 // EditProject - PUT /api/client/project/{id}
 func EditProject(w http.ResponseWriter, r *http.Request) {
-	userID := r.Context().Value("user_id").(string)
-	projectID, _ := strconv.Atoi(chi.URLParam(r, "id"))
 	db := config.GetDB()
 
-	var project models.Project
-	if err := db.Preload("Client").First(&project, projectID).Error; err != nil {
-		http.Error(w, "Проект не найден", http.StatusNotFound)
-		return
-	}
-
-	if project.Client.UserID != userID {
-		http.Error(w, "Нет доступа", http.StatusForbidden)
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
 		return
 	}
 
 	var input struct {
-		Title         string `json:"title"`
-		Description   string `json:"description"`
-		FurnitureType string `json:"furnitureType"`
-		Budget        int    `json:"budget"`
-		Deadline      string `json:"deadline"`
-		City          string `json:"city"`
-		Status        string `json:"status"`
+		Title         string                 `json:"title"`
+		Description   string                 `json:"description"`
+		FurnitureType string                 `json:"furnitureType"`
+		Budget        int                    `json:"budget"`
+		Deadline      string                 `json:"deadline"`
+		City          string                 `json:"city"`
+		Status        string                 `json:"status"`
+		Spec          map[string]interface{} `json:"spec"`
+		Visibility    string                 `json:"visibility"`
 	}
 
 	if err := parseJSON(r, &input); err != nil {
@@ -317,21 +528,73 @@ func EditProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	timee, err := time.Parse("2006-01-02", input.Deadline)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	// Статус здесь не меняется: публикация, назначение мастера и завершение
+	// идут через /publish, /assign и /complete со своими проверками и событиями.
+	// Старая форма присылает текущий статус - его просто пропускаем.
+	if input.Status != "" && input.Status != project.Status {
+		http.Error(w, "Статус меняется через /publish, /assign и /complete", http.StatusConflict)
 		return
 	}
 
+	if input.Visibility != "" && !validVisibility(input.Visibility) {
+		http.Error(w, "Видимость должна быть public, invite_only или link_only", http.StatusBadRequest)
+		return
+	}
+
+	// Черновик можно сохранять не до конца заполненным
+	draft := project.Status == "draft"
+
+	var deadline time.Time
+	if input.Deadline != "" || !draft {
+		var err error
+		deadline, err = parseDeadline(input.Deadline)
+		if err != nil {
+			http.Error(w, "Неверный формат даты. Используйте YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if input.FurnitureType != "" || !draft {
+		category, err := catalog.Resolve(db, input.FurnitureType)
+		if err != nil {
+			http.Error(w, "Неизвестный тип мебели", http.StatusBadRequest)
+			return
+		}
+
+		// Форма редактирования spec не присылает: сохранённые характеристики
+		// остаются как есть. Сбрасываются они только при смене категории -
+		// у другой категории другая схема.
+		categoryChanged := project.CategoryID == nil || *project.CategoryID != category.ID
+		switch {
+		case input.Spec != nil:
+			projectSpec, problem := validateSpec(db, category.ID, input.Spec, draft)
+			if problem != "" {
+				http.Error(w, problem, http.StatusBadRequest)
+				return
+			}
+			project.Spec = projectSpec
+		case categoryChanged:
+			project.Spec = models.JSONB("{}")
+		}
+
+		project.FurnitureType = category.Slug
+		project.CategoryID = &category.ID
+	} else {
+		project.CategoryID = nil
+		project.FurnitureType = ""
+		project.Spec = models.JSONB("{}")
+	}
+
 	project.Title = input.Title
 	project.Description = input.Description
-	project.FurnitureType = input.FurnitureType
 	project.Budget = input.Budget
-	project.Deadline = timee
-	project.City = input.City
-	project.Status = input.Status
+	project.Deadline = deadline
+	project.City = cities.Normalize(db, input.City)
+	if input.Visibility != "" {
+		project.Visibility = input.Visibility
+	}
 
-	if err := db.Save(&project).Error; err != nil {
+	if err := db.Omit(clause.Associations).Save(project).Error; err != nil {
 		http.Error(w, "Ошибка сохранения", http.StatusInternalServerError)
 		return
 	}
@@ -339,6 +602,165 @@ func EditProject(w http.ResponseWriter, r *http.Request) {
 	jsonResponse(w, project)
 }
 
+// PublishProject - POST /api/client/project/{id}/publish
+// Проверяет обязательные поля и публикует черновик сразу или в publishAt.
+func PublishProject(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		PublishAt string `json:"publishAt"`
+	}
+	if r.ContentLength != 0 {
+		if err := parseJSON(r, &input); err != nil {
+			http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+			return
+		}
+	}
+
+	publishAt, err := parsePublishAt(input.PublishAt)
+	if err != nil {
+		http.Error(w, "Неверный формат publishAt. Используйте RFC3339", http.StatusBadRequest)
+		return
+	}
+
+	if project.Status != "draft" && project.Status != "scheduled" {
+		http.Error(w, "Опубликовать можно только черновик", http.StatusConflict)
+		return
+	}
+
+	if problems := publishProblems(db, project, true); len(problems) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":    "Проект заполнен не полностью",
+			"problems": problems,
+		})
+		return
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{"updated_at": now}
+	if publishAt != nil && publishAt.After(now) {
+		updates["status"] = "scheduled"
+		updates["publish_at"] = *publishAt
+	} else {
+		updates["status"] = "published"
+		updates["publish_at"] = nil
+		updates["published_at"] = now
+	}
+
+	userID := r.Context().Value("user_id").(string)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Project{}).Where("id = ?", project.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if updates["status"] != "published" {
+			return nil
+		}
+		return events.Emit(tx, events.ProjectPublished, project.ID, map[string]interface{}{
+			"projectTitle": project.Title,
+			"clientUserId": userID,
+		})
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("📣 Проект %s: статус %s", project.ID, updates["status"])
+
+	if updates["status"] == "published" {
+		events.Wake()
+	} else {
+		realtime.Publish(userID, realtime.ProjectStatusChanged, map[string]interface{}{
+			"projectId": project.ID,
+			"status":    updates["status"],
+		})
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"status":    updates["status"],
+		"projectId": project.ID,
+		"publishAt": updates["publish_at"],
+	})
+}
+
+// ExtendProject - POST /api/client/project/{id}/extend
+// Возвращает истёкший проект в публикацию, при необходимости с новым сроком.
+func ExtendProject(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
+		return
+	}
+
+	if project.Status != "expired" && project.Status != "published" {
+		http.Error(w, "Продлить можно только опубликованный или истёкший проект", http.StatusConflict)
+		return
+	}
+
+	var input struct {
+		Deadline string `json:"deadline"`
+	}
+	if r.ContentLength != 0 {
+		if err := parseJSON(r, &input); err != nil {
+			http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+			return
+		}
+	}
+
+	deadline := project.Deadline
+	if input.Deadline != "" {
+		d, err := parseDeadline(input.Deadline)
+		if err != nil {
+			http.Error(w, "Неверный формат даты. Используйте YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		deadline = d
+	}
+	if deadline.Before(time.Now().Truncate(24 * time.Hour)) {
+		http.Error(w, "Укажите новый срок в будущем", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Project{}).Where("id = ?", project.ID).Updates(map[string]interface{}{
+			"status":       "published",
+			"deadline":     deadline,
+			"published_at": now,
+			"expired_at":   nil,
+			"updated_at":   now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return events.Emit(tx, events.ProjectPublished, project.ID, map[string]interface{}{
+			"projectTitle": project.Title,
+			"clientUserId": r.Context().Value("user_id").(string),
+		})
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	events.Wake()
+
+	log.Printf("🔁 Проект %s продлён до %s", project.ID, deadline.Format("2006-01-02"))
+
+	jsonResponse(w, map[string]interface{}{
+		"status":    "published",
+		"projectId": project.ID,
+		"deadline":  deadline.Format("2006-01-02"),
+	})
+}
+
 // ProjectResponses - GET /api/client/project/{id}/responses
 func ProjectResponses(w http.ResponseWriter, r *http.Request) {
 	userID := r.Context().Value("user_id").(string)
@@ -372,3 +794,131 @@ func ProjectResponses(w http.ResponseWriter, r *http.Request) {
 
 	jsonResponse(w, result)
 }
+
+// validateSpec проверяет спецификацию по схеме категории.
+// Возвращает JSON для сохранения или текст ошибки для клиента.
+func validateSpec(db *gorm.DB, categoryID string, values map[string]interface{}, partial bool) (models.JSONB, string) {
+	schema, err := spec.ForCategory(db, categoryID)
+	if err != nil {
+		log.Printf("❌ Не удалось загрузить схему категории %s: %v", categoryID, err)
+		return nil, "Не удалось проверить спецификацию"
+	}
+
+	clean, problems := spec.Validate(schema, values, partial)
+	if len(problems) > 0 {
+		return nil, "Ошибка в спецификации: " + strings.Join(problems, "; ")
+	}
+
+	raw, err := json.Marshal(clean)
+	if err != nil {
+		return nil, "Не удалось сохранить спецификацию"
+	}
+	return models.JSONB(raw), ""
+}
+
+// publishProblems проверяет, что проект заполнен достаточно для публикации.
+// withSpec=false пропускает проверку обязательных полей спецификации.
+func publishProblems(db *gorm.DB, project *models.Project, withSpec bool) []string {
+	var problems []string
+
+	if strings.TrimSpace(project.Title) == "" {
+		problems = append(problems, "не указано название")
+	}
+	if strings.TrimSpace(project.Description) == "" {
+		problems = append(problems, "не заполнено описание")
+	}
+	if strings.TrimSpace(project.City) == "" {
+		problems = append(problems, "не указан город")
+	}
+	if project.Budget <= 0 {
+		problems = append(problems, "не указан бюджет")
+	}
+	if project.Deadline.IsZero() {
+		problems = append(problems, "не указан срок")
+	} else if project.Deadline.Before(time.Now().Truncate(24 * time.Hour)) {
+		problems = append(problems, "срок уже прошёл")
+	}
+
+	if project.CategoryID == nil {
+		problems = append(problems, "не выбран тип мебели")
+		return problems
+	}
+	if !withSpec {
+		return problems
+	}
+
+	schema, err := spec.ForCategory(db, *project.CategoryID)
+	if err != nil {
+		return append(problems, "не удалось проверить спецификацию")
+	}
+	var values map[string]interface{}
+	if len(project.Spec) > 0 {
+		json.Unmarshal(project.Spec, &values)
+	}
+	_, specProblems := spec.Validate(schema, values, false)
+	for _, p := range specProblems {
+		problems = append(problems, "спецификация: "+p)
+	}
+
+	return problems
+}
+
+// loadClientProject загружает проект из URL и проверяет, что он принадлежит
+// текущему клиенту. При ошибке сам пишет ответ и возвращает false.
+func loadClientProject(w http.ResponseWriter, r *http.Request, db *gorm.DB) (*models.Project, bool) {
+	userID := r.Context().Value("user_id").(string)
+	projectID := chi.URLParam(r, "id")
+
+	var project models.Project
+	if err := db.Preload("Client").First(&project, "id = ?", projectID).Error; err != nil {
+		http.Error(w, "Проект не найден", http.StatusNotFound)
+		return nil, false
+	}
+
+	if project.Client.UserID != userID {
+		http.Error(w, "Нет доступа", http.StatusForbidden)
+		return nil, false
+	}
+
+	return &project, true
+}
+
+// parseDeadline понимает YYYY-MM-DD, MM-DD-YYYY, DD.MM.YYYY и RFC3339.
+// Срок - дата: из RFC3339 берётся день в часовом поясе отправителя.
+func parseDeadline(value string) (time.Time, error) {
+	// 1. Пробуем YYYY-MM-DD (стандартный формат HTML input type="date")
+	deadline, err := time.Parse("2006-01-02", value)
+	if err == nil {
+		return deadline, nil
+	}
+	// 2. Пробуем MM-DD-YYYY (то что приходит сейчас)
+	if deadline, err = time.Parse("01-02-2006", value); err == nil {
+		return deadline, nil
+	}
+	// 3. Пробуем DD.MM.YYYY
+	if deadline, err = time.Parse("02.01.2006", value); err == nil {
+		return deadline, nil
+	}
+	// 4. Пробуем RFC3339 (toISOString и API-клиенты)
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC), nil
+}
+
+func validVisibility(v string) bool {
+	return v == "public" || v == "invite_only" || v == "link_only"
+}
+
+func parsePublishAt(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
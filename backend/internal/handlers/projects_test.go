@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDeadline(t *testing.T) {
+	want := time.Date(2026, 11, 5, 0, 0, 0, 0, time.UTC)
+	for _, value := range []string{
+		"2026-11-05",
+		"11-05-2026",
+		"05.11.2026",
+		"2026-11-05T00:00:00Z",
+		"2026-11-05T23:30:00+03:00",
+		"2026-11-05T01:00:00-05:00",
+	} {
+		got, err := parseDeadline(value)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseDeadline(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+
+	for _, value := range []string{"", "завтра", "2026-13-01", "05/11/2026", "2026-11-05T10:00"} {
+		if got, err := parseDeadline(value); err == nil {
+			t.Errorf("parseDeadline(%q) = %v, want ошибку", value, got)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// internal/handlers/questions.go
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+	"refurnish/internal/moderation"
+	"refurnish/internal/notify"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+const maxQuestionLength = 1000
+
+// AskQuestion - POST /api/project/{id}/questions (мастер задаёт вопрос)
+func AskQuestion(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+	projectID := chi.URLParam(r, "id")
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Вопросы могут задавать только мастера", http.StatusForbidden)
+		return
+	}
+
+	var req struct {
+		Question string `json:"question"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	question := strings.TrimSpace(req.Question)
+	if question == "" || len([]rune(question)) > maxQuestionLength {
+		http.Error(w, "Вопрос должен быть от 1 до 1000 символов", http.StatusBadRequest)
+		return
+	}
+
+	var project models.Project
+	if err := db.Preload("Client").First(&project, "id = ?", projectID).Error; err != nil {
+		http.Error(w, "Проект не найден", http.StatusNotFound)
+		return
+	}
+	if project.Status != "published" {
+		http.Error(w, "Вопросы можно задавать только по опубликованным проектам", http.StatusConflict)
+		return
+	}
+	if project.Visibility == "invite_only" && !isInvited(db, project.ID, userID) {
+		http.Error(w, "Проект не найден", http.StatusNotFound)
+		return
+	}
+
+	// Q&A не должен становиться каналом обмена контактами
+	question, masked := moderation.MaskContacts(question)
+
+	q := models.ProjectQuestion{
+		ProjectID:      project.ID,
+		MasterID:       master.ID,
+		Question:       question,
+		ContactsMasked: masked,
+	}
+	if err := db.Create(&q).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("❓ Вопрос по проекту %s от мастера %s", project.ID, master.ID)
+
+	notify.Send(db, project.Client.UserID, notify.QuestionAsked, map[string]interface{}{
+		"projectId":    project.ID,
+		"projectTitle": project.Title,
+		"questionId":   q.ID,
+		"question":     question,
+	})
+
+	jsonResponse(w, map[string]interface{}{
+		"id":             q.ID,
+		"question":       q.Question,
+		"contactsMasked": masked,
+	})
+}
+
+// AnswerQuestion - POST /api/client/project/{id}/questions/{questionId}/answer
+func AnswerQuestion(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Answer string `json:"answer"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	answer := strings.TrimSpace(req.Answer)
+	if answer == "" || len([]rune(answer)) > maxQuestionLength {
+		http.Error(w, "Ответ должен быть от 1 до 1000 символов", http.StatusBadRequest)
+		return
+	}
+
+	var q models.ProjectQuestion
+	if err := db.Preload("Master").
+		First(&q, "id = ? AND project_id = ?", chi.URLParam(r, "questionId"), project.ID).Error; err != nil {
+		http.Error(w, "Вопрос не найден", http.StatusNotFound)
+		return
+	}
+
+	answer, masked := moderation.MaskContacts(answer)
+	now := time.Now()
+	err := db.Model(&q).Updates(map[string]interface{}{
+		"answer":          answer,
+		"answered_at":     now,
+		"contacts_masked": q.ContactsMasked || masked,
+	}).Error
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if q.Master != nil {
+		notify.Send(db, q.Master.UserID, notify.QuestionAnswered, map[string]interface{}{
+			"projectId":    project.ID,
+			"projectTitle": project.Title,
+			"questionId":   q.ID,
+			"answer":       answer,
+		})
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"id":             q.ID,
+		"answer":         answer,
+		"contactsMasked": masked,
+	})
+}
+
+// projectQuestions возвращает Q&A проекта для GetProjectDetails.
+// Отвеченные вопросы видны всем, неотвеченные - владельцу и автору вопроса.
+func projectQuestions(db *gorm.DB, projectID, userID string, isOwner bool) []map[string]interface{} {
+	var questions []models.ProjectQuestion
+	db.Preload("Master").Where("project_id = ?", projectID).Order("created_at").Find(&questions)
+
+	result := []map[string]interface{}{}
+	for _, q := range questions {
+		isAuthor := q.Master != nil && q.Master.UserID == userID
+		if q.AnsweredAt == nil && !isOwner && !isAuthor {
+			continue
+		}
+
+		item := map[string]interface{}{
+			"id":        q.ID,
+			"question":  q.Question,
+			"answer":    q.Answer,
+			"answered":  q.AnsweredAt != nil,
+			"createdAt": q.CreatedAt.Format(time.RFC3339),
+			"mine":      isAuthor,
+		}
+		if q.Master != nil {
+			item["masterName"] = q.Master.Name
+		}
+		if q.AnsweredAt != nil {
+			item["answeredAt"] = q.AnsweredAt.Format(time.RFC3339)
+		}
+		result = append(result, item)
+	}
+	return result
+}
@@ -0,0 +1,173 @@
+// internal/handlers/realtime.go
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"refurnish/internal/config"
+	"refurnish/internal/realtime"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	realtimePingInterval = 30 * time.Second
+	realtimeWriteTimeout = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Аутентификация по токену, а не по cookie, поэтому Origin не проверяем
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// realtimeTypes - фильтр из ?types=response,message.created
+func realtimeTypes(r *http.Request) []string {
+	raw := r.URL.Query().Get("types")
+	if raw == "" {
+		return nil
+	}
+	return strings.Split(raw, ",")
+}
+
+// RealtimeTicket - POST /api/realtime/ticket
+// Одноразовый билет на TicketTTL для ?ticket= при подключении к ws/events.
+// На каждое подключение (и переподключение EventSource) нужен новый билет.
+func RealtimeTicket(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+	role, _ := r.Context().Value("role").(string)
+
+	ticket, err := realtime.IssueTicket(config.GetDB(), userID, role)
+	if err != nil {
+		log.Printf("❌ Realtime: не удалось выдать билет: %v", err)
+		http.Error(w, "Не удалось выдать билет", http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"ticket":    ticket,
+		"expiresIn": int(realtime.TicketTTL.Seconds()),
+	})
+}
+
+// RealtimeWebSocket - GET /api/realtime/ws?ticket=...&types=...
+// Клиент может менять фильтр сообщением {"action":"subscribe","types":[...]}
+func RealtimeWebSocket(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("❌ Realtime: ошибка upgrade: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	hub := realtime.Default()
+	sub := hub.Subscribe(userID, realtimeTypes(r))
+	defer hub.Unsubscribe(sub)
+
+	log.Printf("📡 Realtime: WebSocket подключён user_id=%s", userID)
+
+	// Чтение: команды клиента и обнаружение закрытия соединения
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(4096)
+		conn.SetReadDeadline(time.Now().Add(2 * realtimePingInterval))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(2 * realtimePingInterval))
+		})
+		for {
+			var cmd struct {
+				Action string   `json:"action"`
+				Types  []string `json:"types"`
+			}
+			if err := conn.ReadJSON(&cmd); err != nil {
+				return
+			}
+			switch cmd.Action {
+			case "subscribe":
+				sub.SetTypes(cmd.Types)
+			case "unsubscribe":
+				sub.SetTypes(nil)
+			}
+		}
+	}()
+
+	ping := time.NewTicker(realtimePingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case e, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(realtimeWriteTimeout))
+			if err := conn.WriteJSON(e); err != nil {
+				return
+			}
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(realtimeWriteTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// RealtimeEvents - GET /api/realtime/events?ticket=...&types=... (Server-Sent Events)
+func RealtimeEvents(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Потоковая передача не поддерживается", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	hub := realtime.Default()
+	sub := hub.Subscribe(userID, realtimeTypes(r))
+	defer hub.Unsubscribe(sub)
+
+	log.Printf("📡 Realtime: SSE подключён user_id=%s", userID)
+
+	fmt.Fprint(w, "retry: 5000\n\n")
+	flusher.Flush()
+
+	ping := time.NewTicker(realtimePingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+			flusher.Flush()
+		case <-ping.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
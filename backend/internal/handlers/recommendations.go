@@ -0,0 +1,80 @@
+// internal/handlers/recommendations.go
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+	"refurnish/internal/recommend"
+)
+
+// RecommendedMasters - GET /api/client/project/{id}/recommended-masters
+func RecommendedMasters(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
+		return
+	}
+
+	limit := 10
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 50 {
+		limit = l
+	}
+
+	recommendations, err := recommend.Rank(db, *project, limit)
+	if err != nil {
+		log.Printf("❌ Ошибка подбора мастеров для проекта %s: %v", project.ID, err)
+		http.Error(w, "Ошибка подбора мастеров", http.StatusInternalServerError)
+		return
+	}
+
+	// Кого уже пригласили и кто уже откликнулся
+	var invited []string
+	db.Model(&models.Invitation{}).Where("project_id = ?", project.ID).Pluck("master_id", &invited)
+	var responded []string
+	db.Model(&models.Response{}).Where("project_id = ?", project.ID).Pluck("master_id", &responded)
+	invitedSet := toSet(invited)
+	respondedSet := toSet(responded)
+
+	inviteURL := "/api/client/project/" + project.ID + "/invitations"
+
+	result := []map[string]interface{}{}
+	for _, rec := range recommendations {
+		m := rec.Master
+		item := map[string]interface{}{
+			"masterId":        m.ID,
+			"name":            m.Name,
+			"city":            m.City,
+			"specializations": m.Specializations,
+			"priceFrom":       m.PriceFrom,
+			"rating":          m.Rating,
+			"score":           rec.Score,
+			"breakdown":       rec.Factors,
+			"invited":         invitedSet[m.ID],
+			"responded":       respondedSet[m.ID],
+		}
+		// Действие для кнопки "Пригласить"
+		if !invitedSet[m.ID] && !respondedSet[m.ID] {
+			item["inviteAction"] = map[string]interface{}{
+				"method": "POST",
+				"url":    inviteURL,
+				"body":   map[string]interface{}{"masterIds": []string{m.ID}},
+			}
+		}
+		result = append(result, item)
+	}
+
+	jsonResponse(w, result)
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
@@ -2,17 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
 	"refurnish/internal/config"
 	"refurnish/internal/models"
+	"refurnish/internal/respond"
 )
 
 // Отклик на проект
 func RespondToProject(w http.ResponseWriter, r *http.Request) {
-	masterID := r.Context().Value("user_id").(string)
-
 	var req struct {
 		ProjectID string `json:"projectId"`
 		Comment   string `json:"comment"`
@@ -34,32 +34,33 @@ func RespondToProject(w http.ResponseWriter, r *http.Request) {
 
 	db := config.GetDB()
 
-	// Проверяем существование проекта
-	var project models.Project
-	if err := db.Where("id = ? AND status = ?", req.ProjectID, "published").
-		First(&project).Error; err != nil {
-		http.Error(w, "Project not found or not available", http.StatusNotFound)
-		return
-	}
-
-	// Проверяем, не откликался ли уже мастер
-	var existingResponse models.Response
-	if err := db.Where("project_id = ? AND master_id = ?", req.ProjectID, masterID).
-		First(&existingResponse).Error; err == nil {
-		http.Error(w, "You have already responded to this project", http.StatusBadRequest)
+	// responses.master_id ссылается на masters.id, а не на users.id
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Master not found", http.StatusNotFound)
 		return
 	}
 
-	// Создаем отклик
-	response := models.Response{
+	response, err := respond.Submit(db, master, respond.Input{
 		ProjectID: req.ProjectID,
-		MasterID:  masterID,
 		Comment:   req.Comment,
 		Price:     req.Price,
 		StartDate: startDate,
-	}
-
-	if err := db.Create(&response).Error; err != nil {
+	})
+	switch {
+	case errors.Is(err, respond.ErrProjectNotFound):
+		http.Error(w, "Project not found or not available", http.StatusNotFound)
+		return
+	case errors.Is(err, respond.ErrProjectExpired):
+		http.Error(w, "Project has expired", http.StatusGone)
+		return
+	case errors.Is(err, respond.ErrInviteOnly):
+		http.Error(w, "This project is available by invitation only", http.StatusForbidden)
+		return
+	case errors.Is(err, respond.ErrAlreadyResponded):
+		http.Error(w, "You have already responded to this project", http.StatusBadRequest)
+		return
+	case err != nil:
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -73,12 +74,16 @@ func RespondToProject(w http.ResponseWriter, r *http.Request) {
 
 // Мои отклики
 func MyResponses(w http.ResponseWriter, r *http.Request) {
-	masterID := r.Context().Value("user_id").(string)
-
 	db := config.GetDB()
 
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Master not found", http.StatusNotFound)
+		return
+	}
+
 	var responses []models.Response
-	if err := db.Where("master_id = ?", masterID).
+	if err := db.Where("master_id = ?", master.ID).
 		Preload("Project").
 		Find(&responses).Error; err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -0,0 +1,400 @@
+// internal/handlers/reviews.go
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"refurnish/internal/config"
+	"refurnish/internal/events"
+	"refurnish/internal/models"
+	"refurnish/internal/moderation"
+	"refurnish/internal/ratings"
+	"refurnish/internal/uploads"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	maxReviewLength = 2000
+	maxReviewPhotos = 5
+)
+
+// CompleteProject - POST /api/client/project/{id}/complete
+// Клиент подтверждает, что назначенный мастер выполнил работу.
+func CompleteProject(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
+		return
+	}
+	if project.Status != "assigned" || project.MasterID == nil {
+		http.Error(w, "Завершить можно только проект с назначенным мастером", http.StatusConflict)
+		return
+	}
+
+	var master models.Master
+	if err := db.First(&master, "id = ?", *project.MasterID).Error; err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	// Согласие на портфолио можно дать сразу при завершении, тело необязательно
+	var req struct {
+		PortfolioConsent *bool `json:"portfolioConsent"`
+	}
+	parseJSON(r, &req)
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":       "completed",
+		"completed_at": now,
+		"updated_at":   now,
+	}
+	if req.PortfolioConsent != nil {
+		updates["portfolio_consent"] = *req.PortfolioConsent
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Project{}).Where("id = ?", project.ID).Updates(updates).Error
+		if err != nil {
+			return err
+		}
+		return events.Emit(tx, events.ProjectCompleted, project.ID, map[string]interface{}{
+			"projectTitle": project.Title,
+			"clientUserId": r.Context().Value("user_id").(string),
+			"masterId":     master.ID,
+			"masterUserId": master.UserID,
+		})
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	events.Wake()
+
+	log.Printf("🏁 Проект %s завершён", project.ID)
+
+	jsonResponse(w, map[string]interface{}{
+		"status":      "completed",
+		"projectId":   project.ID,
+		"completedAt": now.Format(time.RFC3339),
+	})
+}
+
+// validScore - оценка 1-5; для подоценок nil допустим
+func validScore(v *int) bool {
+	return v == nil || (*v >= 1 && *v <= 5)
+}
+
+// CreateReview - POST /api/client/project/{id}/review
+func CreateReview(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
+		return
+	}
+	if project.Status != "completed" || project.MasterID == nil {
+		http.Error(w, "Отзыв можно оставить только по завершённому проекту", http.StatusConflict)
+		return
+	}
+	if ratings.WindowClosed(project.CompletedAt, config.ReviewRevealWindow()) {
+		http.Error(w, "Срок для отзыва по этому проекту истёк", http.StatusConflict)
+		return
+	}
+
+	var req struct {
+		Rating        int    `json:"rating"`
+		Quality       *int   `json:"quality"`
+		Punctuality   *int   `json:"punctuality"`
+		Communication *int   `json:"communication"`
+		Text          string `json:"text"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	if !validScore(&req.Rating) || !validScore(req.Quality) || !validScore(req.Punctuality) || !validScore(req.Communication) {
+		http.Error(w, "Оценки должны быть от 1 до 5", http.StatusBadRequest)
+		return
+	}
+	text := strings.TrimSpace(req.Text)
+	if len([]rune(text)) > maxReviewLength {
+		http.Error(w, "Отзыв не длиннее 2000 символов", http.StatusBadRequest)
+		return
+	}
+
+	var master models.Master
+	if err := db.First(&master, "id = ?", *project.MasterID).Error; err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var existing int64
+	db.Model(&models.Review{}).Where("project_id = ?", project.ID).Count(&existing)
+	if existing > 0 {
+		http.Error(w, "Отзыв по этому проекту уже оставлен", http.StatusConflict)
+		return
+	}
+
+	review := models.Review{
+		ProjectID:     project.ID,
+		ClientID:      project.ClientID,
+		MasterID:      master.ID,
+		Rating:        req.Rating,
+		Quality:       req.Quality,
+		Punctuality:   req.Punctuality,
+		Communication: req.Communication,
+		Text:          text,
+	}
+	if ip := clientIP(r); ip != "" {
+		review.IP = &ip
+	}
+
+	// Отзыв с автоматическими флагами ждёт решения модератора
+	review.Flags = moderation.Check(db, moderation.MasterReview, moderation.Submission{
+		Text:      review.Text,
+		IP:        review.IP,
+		AuthorID:  review.ClientID,
+		SubjectID: review.MasterID,
+	})
+	review.ModerationStatus = moderation.StatusApproved
+	if len(review.Flags) > 0 {
+		review.ModerationStatus = moderation.StatusPending
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&review).Error; err != nil {
+			return err
+		}
+		if len(review.Flags) > 0 {
+			if err := moderation.Record(tx, moderation.MasterReview, review.ID, nil, moderation.ActionFlagged, "", map[string]interface{}{
+				"flags": review.Flags,
+			}); err != nil {
+				return err
+			}
+		}
+		published, err := ratings.Reveal(tx, project.ID, config.ReviewRevealWindow())
+		if err != nil {
+			return err
+		}
+		if published {
+			now := time.Now()
+			review.PublishedAt = &now
+		}
+		return events.Emit(tx, events.ReviewCreated, review.ID, map[string]interface{}{
+			"projectId":    project.ID,
+			"projectTitle": project.Title,
+			"masterId":     master.ID,
+			"masterUserId": master.UserID,
+		})
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	events.Wake()
+
+	log.Printf("⭐ Отзыв на мастера %s: %d (модерация: %s %v)", master.ID, review.Rating, review.ModerationStatus, review.Flags)
+
+	jsonResponse(w, reviewJSON(review, ""))
+}
+
+// UploadReviewPhoto - POST /api/client/project/{id}/review/photos (multipart, поле "photo")
+func UploadReviewPhoto(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
+		return
+	}
+
+	var review models.Review
+	if err := db.First(&review, "project_id = ?", project.ID).Error; err != nil {
+		http.Error(w, "Сначала оставьте отзыв", http.StatusNotFound)
+		return
+	}
+
+	var count int64
+	db.Model(&models.ReviewPhoto{}).Where("review_id = ?", review.ID).Count(&count)
+	if count >= maxReviewPhotos {
+		http.Error(w, "Можно загрузить не более 5 фотографий", http.StatusBadRequest)
+		return
+	}
+
+	url, err := uploads.SaveImage(w, r, "photo")
+	if err != nil {
+		if uploads.IsClientError(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("❌ Ошибка сохранения фото: %v", err)
+		http.Error(w, "Ошибка сохранения файла", http.StatusInternalServerError)
+		return
+	}
+
+	photo := models.ReviewPhoto{ReviewID: review.ID, URL: url, SortOrder: int(count)}
+	if err := db.Create(&photo).Error; err != nil {
+		uploads.Remove(url)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"id":  photo.ID,
+		"url": photo.URL,
+	})
+}
+
+// ReplyToReview - POST /api/master/reviews/{id}/reply
+func ReplyToReview(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var req struct {
+		Reply string `json:"reply"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	reply := strings.TrimSpace(req.Reply)
+	if reply == "" || len([]rune(reply)) > maxReviewLength {
+		http.Error(w, "Ответ должен быть от 1 до 2000 символов", http.StatusBadRequest)
+		return
+	}
+
+	var review models.Review
+	if err := db.First(&review, "id = ? AND master_id = ?", chi.URLParam(r, "id"), master.ID).Error; err != nil {
+		http.Error(w, "Отзыв не найден", http.StatusNotFound)
+		return
+	}
+
+	now := time.Now()
+	if err := db.Model(&review).Updates(map[string]interface{}{"reply": reply, "replied_at": now}).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	review.Reply = &reply
+	review.RepliedAt = &now
+
+	jsonResponse(w, reviewJSON(review, ""))
+}
+
+// MasterReviews - GET /api/masters/{id}/reviews?limit=&offset=, {id} - id или адрес профиля
+func MasterReviews(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := findMaster(db, chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	limit := 20
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	var reviews []models.Review
+	err = db.Preload("Client").Preload("Project").
+		Preload("Photos", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order") }).
+		Where("master_id = ? AND "+ratings.VisibleReview, master.ID).
+		Order("created_at DESC").Limit(limit).Offset(offset).
+		Find(&reviews).Error
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var averages struct {
+		Quality       *float64
+		Punctuality   *float64
+		Communication *float64
+	}
+	db.Raw(`
+		SELECT AVG(quality)::float8 AS quality, AVG(punctuality)::float8 AS punctuality,
+		       AVG(communication)::float8 AS communication
+		FROM reviews WHERE master_id = ? AND `+ratings.VisibleReview, master.ID).Scan(&averages)
+
+	var distribution []struct {
+		Rating int
+		Count  int
+	}
+	db.Raw(`SELECT rating, COUNT(*) AS count FROM reviews
+		WHERE master_id = ? AND `+ratings.VisibleReview+` GROUP BY rating`, master.ID).Scan(&distribution)
+	stars := map[string]int{"1": 0, "2": 0, "3": 0, "4": 0, "5": 0}
+	for _, d := range distribution {
+		stars[strconv.Itoa(d.Rating)] = d.Count
+	}
+
+	items := []map[string]interface{}{}
+	for _, review := range reviews {
+		clientName := ""
+		if review.Client != nil {
+			clientName = review.Client.Name
+		}
+		item := reviewJSON(review, clientName)
+		if review.Project != nil {
+			item["projectTitle"] = review.Project.Title
+		}
+		items = append(items, item)
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"masterId":     master.ID,
+		"rating":       master.Rating,
+		"reviewsCount": master.ReviewsCount,
+		"averages": map[string]interface{}{
+			"quality":       averages.Quality,
+			"punctuality":   averages.Punctuality,
+			"communication": averages.Communication,
+		},
+		"distribution": stars,
+		"items":        items,
+	})
+}
+
+func reviewJSON(review models.Review, clientName string) map[string]interface{} {
+	photos := []map[string]interface{}{}
+	for _, p := range review.Photos {
+		photos = append(photos, map[string]interface{}{"id": p.ID, "url": p.URL})
+	}
+
+	result := map[string]interface{}{
+		"id":               review.ID,
+		"projectId":        review.ProjectID,
+		"rating":           review.Rating,
+		"quality":          review.Quality,
+		"punctuality":      review.Punctuality,
+		"communication":    review.Communication,
+		"text":             review.Text,
+		"photos":           photos,
+		"published":        review.PublishedAt != nil,
+		"moderationStatus": review.ModerationStatus,
+		"createdAt":        review.CreatedAt.Format(time.RFC3339),
+	}
+	if clientName != "" {
+		result["clientName"] = clientName
+	}
+	if review.Reply != nil {
+		result["reply"] = *review.Reply
+		result["repliedAt"] = review.RepliedAt.Format(time.RFC3339)
+	}
+	return result
+}
@@ -0,0 +1,273 @@
+// internal/handlers/saved_searches.go
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"refurnish/internal/alerts"
+	"refurnish/internal/catalog"
+	"refurnish/internal/cities"
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+const maxSavedSearches = 20
+
+type savedSearchInput struct {
+	Name      string `json:"name"`
+	City      string `json:"city"`
+	Category  string `json:"category"`
+	BudgetMin *int   `json:"budgetMin"`
+	BudgetMax *int   `json:"budgetMax"`
+	Keywords  string `json:"keywords"`
+	RadiusKm  int    `json:"radiusKm"`
+	Delivery  string `json:"delivery"`
+}
+
+// apply проверяет ввод и переносит его в модель
+func (in savedSearchInput) apply(db *gorm.DB, search *models.SavedSearch) string {
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return "Укажите название поиска"
+	}
+	if in.Delivery == "" {
+		in.Delivery = "instant"
+	}
+	if in.Delivery != "instant" && in.Delivery != "daily" {
+		return "Доставка должна быть instant или daily"
+	}
+	if in.RadiusKm < 0 || in.RadiusKm > 500 {
+		return "Радиус должен быть от 0 до 500 км"
+	}
+	if in.BudgetMin != nil && in.BudgetMax != nil && *in.BudgetMin > *in.BudgetMax {
+		return "Минимальный бюджет больше максимального"
+	}
+
+	search.CategoryID = nil
+	if in.Category != "" {
+		category, err := catalog.Resolve(db, in.Category)
+		if err != nil {
+			return "Неизвестная категория"
+		}
+		search.CategoryID = &category.ID
+	}
+
+	search.Name = name
+	search.City = cities.Normalize(db, in.City)
+	search.BudgetMin = in.BudgetMin
+	search.BudgetMax = in.BudgetMax
+	search.Keywords = strings.TrimSpace(in.Keywords)
+	search.RadiusKm = in.RadiusKm
+	search.Delivery = in.Delivery
+	return ""
+}
+
+func savedSearchJSON(s models.SavedSearch) map[string]interface{} {
+	result := map[string]interface{}{
+		"id":             s.ID,
+		"name":           s.Name,
+		"city":           s.City,
+		"budgetMin":      s.BudgetMin,
+		"budgetMax":      s.BudgetMax,
+		"keywords":       s.Keywords,
+		"radiusKm":       s.RadiusKm,
+		"delivery":       s.Delivery,
+		"isActive":       s.IsActive,
+		"unsubscribeUrl": alerts.UnsubscribeURL(s.UnsubscribeToken),
+		"createdAt":      s.CreatedAt.Format(time.RFC3339),
+	}
+	if s.Category != nil {
+		result["category"] = s.Category.Slug
+	}
+	return result
+}
+
+// MySavedSearches - GET /api/master/searches
+func MySavedSearches(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var searches []models.SavedSearch
+	db.Preload("Category").Where("master_id = ?", master.ID).Order("created_at").Find(&searches)
+
+	result := []map[string]interface{}{}
+	for _, s := range searches {
+		result = append(result, savedSearchJSON(s))
+	}
+	jsonResponse(w, result)
+}
+
+// CreateSavedSearch - POST /api/master/searches
+func CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var input savedSearchInput
+	if err := parseJSON(r, &input); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	var count int64
+	db.Model(&models.SavedSearch{}).Where("master_id = ?", master.ID).Count(&count)
+	if count >= maxSavedSearches {
+		http.Error(w, "Можно сохранить не более 20 поисков", http.StatusBadRequest)
+		return
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	search := models.SavedSearch{
+		MasterID:         master.ID,
+		UnsubscribeToken: token,
+		IsActive:         true,
+	}
+	if msg := input.apply(db, &search); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if err := db.Create(&search).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	db.Preload("Category").First(&search, "id = ?", search.ID)
+	jsonResponse(w, savedSearchJSON(search))
+}
+
+// UpdateSavedSearch - PUT /api/master/searches/{id}
+func UpdateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var search models.SavedSearch
+	if err := db.First(&search, "id = ? AND master_id = ?", chi.URLParam(r, "id"), master.ID).Error; err != nil {
+		http.Error(w, "Поиск не найден", http.StatusNotFound)
+		return
+	}
+
+	var input struct {
+		savedSearchInput
+		IsActive *bool `json:"isActive"`
+	}
+	if err := parseJSON(r, &input); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	if msg := input.apply(db, &search); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	if input.IsActive != nil {
+		search.IsActive = *input.IsActive
+	}
+	search.Category = nil
+
+	if err := db.Save(&search).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	db.Preload("Category").First(&search, "id = ?", search.ID)
+	jsonResponse(w, savedSearchJSON(search))
+}
+
+// DeleteSavedSearch - DELETE /api/master/searches/{id}
+func DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	result := db.Where("id = ? AND master_id = ?", chi.URLParam(r, "id"), master.ID).Delete(&models.SavedSearch{})
+	if result.Error != nil {
+		http.Error(w, result.Error.Error(), http.StatusInternalServerError)
+		return
+	}
+	if result.RowsAffected == 0 {
+		http.Error(w, "Поиск не найден", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, map[string]string{"status": "deleted"})
+}
+
+// UnsubscribePreview - GET /api/searches/unsubscribe?token=
+// Страница подтверждения отписки показывает, от какого поиска отписываются.
+// Ничего не меняет: по ссылкам из писем ходят сканеры.
+func UnsubscribePreview(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(w, "Не указан токен", http.StatusBadRequest)
+		return
+	}
+
+	var search models.SavedSearch
+	if err := config.GetDB().Where("unsubscribe_token = ?", token).First(&search).Error; err != nil {
+		http.Error(w, "Подписка не найдена", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"name":     search.Name,
+		"isActive": search.IsActive,
+	})
+}
+
+// UnsubscribeSavedSearch - POST /api/searches/unsubscribe {"token": ...}
+// Отписка по токену из уведомления, работает без авторизации.
+func UnsubscribeSavedSearch(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := parseJSON(r, &req); err != nil || req.Token == "" {
+		http.Error(w, "Не указан токен", http.StatusBadRequest)
+		return
+	}
+	token := req.Token
+
+	result := config.GetDB().Model(&models.SavedSearch{}).
+		Where("unsubscribe_token = ?", token).
+		Updates(map[string]interface{}{"is_active": false, "updated_at": time.Now()})
+	if result.Error != nil {
+		http.Error(w, result.Error.Error(), http.StatusInternalServerError)
+		return
+	}
+	if result.RowsAffected == 0 {
+		http.Error(w, "Подписка не найдена", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, map[string]string{
+		"status":  "unsubscribed",
+		"message": "Вы отписались от оповещений по этому поиску",
+	})
+}
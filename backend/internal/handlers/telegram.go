@@ -0,0 +1,61 @@
+// internal/handlers/telegram.go
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+	"refurnish/internal/telegram"
+)
+
+// TelegramStatus - GET /api/telegram
+func TelegramStatus(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+
+	var link models.TelegramLink
+	if err := config.GetDB().First(&link, "user_id = ?", userID).Error; err != nil {
+		jsonResponse(w, map[string]interface{}{"linked": false})
+		return
+	}
+
+	jsonResponse(w, map[string]interface{}{
+		"linked":   true,
+		"username": link.Username,
+		"linkedAt": link.LinkedAt.Format(time.RFC3339),
+	})
+}
+
+// CreateTelegramLinkCode - POST /api/telegram/link-code
+// Код отправляется боту командой /start <код> или по ссылке deepLink.
+func CreateTelegramLinkCode(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+
+	code, err := telegram.NewLinkCode(config.GetDB(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := map[string]interface{}{
+		"code":      code.Code,
+		"expiresAt": code.ExpiresAt.Format(time.RFC3339),
+	}
+	if bot := os.Getenv("TELEGRAM_BOT_USERNAME"); bot != "" {
+		result["deepLink"] = "https://t.me/" + bot + "?start=" + code.Code
+	}
+	jsonResponse(w, result)
+}
+
+// UnlinkTelegram - DELETE /api/telegram
+func UnlinkTelegram(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+
+	if err := telegram.Unlink(config.GetDB(), userID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	jsonResponse(w, map[string]string{"status": "unlinked"})
+}
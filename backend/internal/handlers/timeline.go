@@ -0,0 +1,150 @@
+// internal/handlers/timeline.go
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// timelineItem - событие в ленте проекта
+type timelineItem struct {
+	At   time.Time
+	Type string
+	Data map[string]interface{}
+}
+
+// ClientProjectTimeline - GET /api/client/project/{id}/timeline
+// Лента проекта: публикация, отклики, назначение мастера, замеры, завершение
+func ClientProjectTimeline(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	project, ok := loadClientProject(w, r, db)
+	if !ok {
+		return
+	}
+
+	jsonResponse(w, timelineJSON(projectTimeline(db, project, "")))
+}
+
+// MasterProjectTimeline - GET /api/master/project/{id}/timeline
+// Та же лента для мастера, откликнувшегося на проект или назначенного:
+// чужие отклики и замеры в неё не попадают
+func MasterProjectTimeline(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var project models.Project
+	if err := db.First(&project, "id = ?", chi.URLParam(r, "id")).Error; err != nil {
+		http.Error(w, "Проект не найден", http.StatusNotFound)
+		return
+	}
+	if !masterOnProject(db, &project, master.ID) {
+		http.Error(w, "Нет доступа", http.StatusForbidden)
+		return
+	}
+
+	jsonResponse(w, timelineJSON(projectTimeline(db, &project, master.ID)))
+}
+
+// projectTimeline собирает ленту; masterID ограничивает отклики и замеры
+// одним мастером, пустой - все (для клиента)
+func projectTimeline(db *gorm.DB, project *models.Project, masterID string) []timelineItem {
+	items := []timelineItem{{At: project.CreatedAt, Type: "project_created"}}
+	if project.PublishedAt != nil {
+		items = append(items, timelineItem{At: *project.PublishedAt, Type: "project_published"})
+	}
+	if project.ExpiredAt != nil {
+		items = append(items, timelineItem{At: *project.ExpiredAt, Type: "project_expired"})
+	}
+
+	var responses []models.Response
+	query := db.Preload("Master").Where("project_id = ?", project.ID)
+	if masterID != "" {
+		query = query.Where("master_id = ?", masterID)
+	}
+	query.Find(&responses)
+	for _, response := range responses {
+		data := map[string]interface{}{
+			"responseId": response.ID,
+			"masterId":   response.MasterID,
+			"price":      response.Price,
+		}
+		if response.Master != nil {
+			data["masterName"] = response.Master.Name
+		}
+		items = append(items, timelineItem{At: response.CreatedAt, Type: "response_created", Data: data})
+	}
+
+	if project.AssignedAt != nil && project.MasterID != nil {
+		data := map[string]interface{}{"masterId": *project.MasterID}
+		var master models.Master
+		if err := db.Select("id", "name").First(&master, "id = ?", *project.MasterID).Error; err == nil {
+			data["masterName"] = master.Name
+		}
+		items = append(items, timelineItem{At: *project.AssignedAt, Type: "master_assigned", Data: data})
+	}
+
+	var changes []struct {
+		models.AppointmentChange
+		MasterID   string
+		MasterName string
+	}
+	changesQuery := db.Table("appointment_changes c").
+		Select("c.*, a.master_id, m.name AS master_name").
+		Joins("JOIN appointments a ON a.id = c.appointment_id").
+		Joins("JOIN masters m ON m.id = a.master_id").
+		Where("a.project_id = ?", project.ID)
+	if masterID != "" {
+		changesQuery = changesQuery.Where("a.master_id = ?", masterID)
+	}
+	changesQuery.Scan(&changes)
+	for _, change := range changes {
+		data := map[string]interface{}{
+			"appointmentId": change.AppointmentID,
+			"masterId":      change.MasterID,
+			"masterName":    change.MasterName,
+			"by":            change.ActorRole,
+		}
+		if change.Reason != "" {
+			data["reason"] = change.Reason
+		}
+		if change.StartsAt != nil {
+			data["startsAt"] = change.StartsAt.Format(time.RFC3339)
+		}
+		items = append(items, timelineItem{At: change.CreatedAt, Type: "appointment_" + change.Action, Data: data})
+	}
+
+	if project.CompletedAt != nil {
+		items = append(items, timelineItem{At: *project.CompletedAt, Type: "project_completed"})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].At.Before(items[j].At) })
+	return items
+}
+
+func timelineJSON(items []timelineItem) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		entry := map[string]interface{}{
+			"at":   item.At.Format(time.RFC3339),
+			"type": item.Type,
+		}
+		for key, value := range item.Data {
+			entry[key] = value
+		}
+		result = append(result, entry)
+	}
+	return result
+}
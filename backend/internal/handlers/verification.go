@@ -0,0 +1,404 @@
+// internal/handlers/verification.go
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+	"refurnish/internal/notify"
+	"refurnish/internal/uploads"
+	"refurnish/internal/verification"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// MyVerification - GET /api/master/verification
+// Статус проверки и последняя заявка мастера
+func MyVerification(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"verified": master.VerifiedAt != nil,
+	}
+	if master.VerifiedAt != nil {
+		response["verifiedAt"] = master.VerifiedAt.Format(time.RFC3339)
+	}
+
+	var request models.VerificationRequest
+	if err := db.Preload("Documents").Where("master_id = ?", master.ID).
+		Order("created_at DESC").First(&request).Error; err == nil {
+		response["request"] = verificationRequestJSON(request)
+	}
+
+	jsonResponse(w, response)
+}
+
+// UploadVerificationDocument - POST /api/master/verification/documents
+// multipart: поле "file" (изображение или PDF) и kind - вид документа
+func UploadVerificationDocument(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	request, err := draftVerificationRequest(db, master.ID)
+	if errors.Is(err, errVerificationPending) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(request.Documents) >= verification.MaxDocuments {
+		http.Error(w, "Можно загрузить не более 10 документов", http.StatusBadRequest)
+		return
+	}
+
+	file, err := uploads.SavePrivate(w, r, "file")
+	if err != nil {
+		if uploads.IsClientError(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("❌ Ошибка сохранения документа: %v", err)
+		http.Error(w, "Ошибка сохранения файла", http.StatusInternalServerError)
+		return
+	}
+
+	kind := r.FormValue("kind")
+	if !verification.DocumentKinds[kind] {
+		uploads.RemovePrivate(file.URL)
+		http.Error(w, "Неизвестный вид документа", http.StatusBadRequest)
+		return
+	}
+
+	document := models.VerificationDocument{
+		RequestID:    request.ID,
+		Kind:         kind,
+		FileName:     file.URL,
+		OriginalName: file.Name,
+		ContentType:  file.ContentType,
+		Size:         file.Size,
+	}
+	if err := db.Create(&document).Error; err != nil {
+		uploads.RemovePrivate(file.URL)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, verificationDocumentJSON(document))
+}
+
+// DeleteVerificationDocument - DELETE /api/master/verification/documents/{id}
+// Удалять можно только из черновика заявки
+func DeleteVerificationDocument(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var document models.VerificationDocument
+	err = db.Joins("JOIN verification_requests vr ON vr.id = verification_documents.request_id").
+		Where("verification_documents.id = ? AND vr.master_id = ? AND vr.status = ?",
+			chi.URLParam(r, "id"), master.ID, verification.StatusDraft).
+		First(&document).Error
+	if err != nil {
+		http.Error(w, "Документ не найден", http.StatusNotFound)
+		return
+	}
+
+	if err := db.Delete(&document).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := uploads.RemovePrivate(document.FileName); err != nil {
+		log.Printf("⚠️  Не удалось удалить документ %s: %v", document.FileName, err)
+	}
+
+	jsonResponse(w, map[string]string{
+		"status":     "deleted",
+		"documentId": document.ID,
+	})
+}
+
+// SubmitVerification - POST /api/master/verification/submit
+// ИНН обязателен, ОГРНИП - для ИП. Контрольные числа проверяются сразу.
+func SubmitVerification(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	master, err := currentMaster(db, r)
+	if err != nil {
+		http.Error(w, "Мастер не найден", http.StatusNotFound)
+		return
+	}
+
+	var req struct {
+		INN    string `json:"inn"`
+		OGRNIP string `json:"ogrnip"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	inn := strings.TrimSpace(req.INN)
+	ogrnip := strings.TrimSpace(req.OGRNIP)
+
+	var request models.VerificationRequest
+	if err := db.Preload("Documents").
+		First(&request, "master_id = ? AND status = ?", master.ID, verification.StatusDraft).Error; err != nil {
+		http.Error(w, "Сначала загрузите документы", http.StatusConflict)
+		return
+	}
+
+	if err := verification.CheckSubmission(inn, ogrnip, request.Documents); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":       verification.StatusPending,
+		"inn":          inn,
+		"ogrnip":       nil,
+		"submitted_at": now,
+	}
+	if ogrnip != "" {
+		updates["ogrnip"] = ogrnip
+	}
+	if err := db.Model(&request).Updates(updates).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	request.Status = verification.StatusPending
+	request.INN = &inn
+	if ogrnip != "" {
+		request.OGRNIP = &ogrnip
+	}
+	request.SubmittedAt = &now
+
+	log.Printf("🪪 Мастер %s отправил заявку на проверку %s", master.ID, request.ID)
+
+	jsonResponse(w, verificationRequestJSON(request))
+}
+
+// VerificationQueue - GET /api/admin/verifications?status=pending
+func VerificationQueue(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = verification.StatusPending
+	}
+
+	var requests []models.VerificationRequest
+	err := db.Preload("Master").Preload("Documents").
+		Where("status = ?", status).Order("submitted_at, created_at").Limit(200).
+		Find(&requests).Error
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	items := []map[string]interface{}{}
+	for _, request := range requests {
+		item := verificationRequestJSON(request)
+		if request.Master != nil {
+			item["master"] = map[string]interface{}{
+				"id":   request.Master.ID,
+				"name": request.Master.Name,
+				"city": request.Master.City,
+			}
+		}
+		items = append(items, item)
+	}
+
+	jsonResponse(w, items)
+}
+
+// VerificationDocumentFile - GET /api/admin/verifications/{id}/documents/{documentId}
+// Документы отдаются только модератору, в обход публичного /uploads/
+func VerificationDocumentFile(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	var document models.VerificationDocument
+	if err := db.First(&document, "id = ? AND request_id = ?",
+		chi.URLParam(r, "documentId"), chi.URLParam(r, "id")).Error; err != nil {
+		http.Error(w, "Документ не найден", http.StatusNotFound)
+		return
+	}
+
+	f, err := uploads.OpenPrivate(document.FileName)
+	if err != nil {
+		http.Error(w, "Файл не найден", http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", document.ContentType)
+	w.Header().Set("Cache-Control", "private, no-store")
+	http.ServeContent(w, r, document.FileName, document.CreatedAt, f)
+}
+
+// ApproveVerification - POST /api/admin/verifications/{id}/approve
+func ApproveVerification(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+	adminID := r.Context().Value("user_id").(string)
+
+	request, ok := loadPendingVerification(w, r, db)
+	if !ok {
+		return
+	}
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return verification.Approve(tx, request, adminID)
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	request.Status = verification.StatusApproved
+
+	notify.Send(db, request.Master.UserID, notify.VerificationStatus, map[string]interface{}{
+		"status": verification.StatusApproved,
+	})
+	log.Printf("🪪 Мастер %s проверен (модератор %s)", request.MasterID, adminID)
+
+	jsonResponse(w, verificationRequestJSON(*request))
+}
+
+// RejectVerification - POST /api/admin/verifications/{id}/reject
+func RejectVerification(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+	adminID := r.Context().Value("user_id").(string)
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := parseJSON(r, &req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		http.Error(w, "Укажите причину отказа", http.StatusBadRequest)
+		return
+	}
+
+	request, ok := loadPendingVerification(w, r, db)
+	if !ok {
+		return
+	}
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return verification.Reject(tx, request, adminID, reason)
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	request.Status = verification.StatusRejected
+	request.Reason = &reason
+
+	notify.Send(db, request.Master.UserID, notify.VerificationStatus, map[string]interface{}{
+		"status": verification.StatusRejected,
+		"reason": reason,
+	})
+	log.Printf("🪪 Заявка %s отклонена: %s", request.ID, reason)
+
+	jsonResponse(w, verificationRequestJSON(*request))
+}
+
+var errVerificationPending = errors.New("Заявка уже на проверке, дождитесь решения")
+
+// draftVerificationRequest возвращает черновик заявки мастера, создавая его
+// при необходимости. Пока заявка на проверке, новые документы не принимаются.
+func draftVerificationRequest(db *gorm.DB, masterID string) (*models.VerificationRequest, error) {
+	var request models.VerificationRequest
+	err := db.Preload("Documents").
+		Where("master_id = ? AND status IN ?", masterID,
+			[]string{verification.StatusDraft, verification.StatusPending}).
+		First(&request).Error
+	if err == nil {
+		if request.Status == verification.StatusPending {
+			return nil, errVerificationPending
+		}
+		return &request, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	request = models.VerificationRequest{MasterID: masterID, Status: verification.StatusDraft}
+	if err := db.Create(&request).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func loadPendingVerification(w http.ResponseWriter, r *http.Request, db *gorm.DB) (*models.VerificationRequest, bool) {
+	var request models.VerificationRequest
+	if err := db.Preload("Master").Preload("Documents").
+		First(&request, "id = ?", chi.URLParam(r, "id")).Error; err != nil || request.Master == nil {
+		http.Error(w, "Заявка не найдена", http.StatusNotFound)
+		return nil, false
+	}
+	if request.Status != verification.StatusPending {
+		http.Error(w, "Заявка не ожидает проверки", http.StatusConflict)
+		return nil, false
+	}
+	return &request, true
+}
+
+func verificationRequestJSON(request models.VerificationRequest) map[string]interface{} {
+	documents := []map[string]interface{}{}
+	for _, d := range request.Documents {
+		documents = append(documents, verificationDocumentJSON(d))
+	}
+
+	result := map[string]interface{}{
+		"id":        request.ID,
+		"status":    request.Status,
+		"inn":       request.INN,
+		"ogrnip":    request.OGRNIP,
+		"documents": documents,
+		"createdAt": request.CreatedAt.Format(time.RFC3339),
+	}
+	if request.Reason != nil {
+		result["reason"] = *request.Reason
+	}
+	if request.SubmittedAt != nil {
+		result["submittedAt"] = request.SubmittedAt.Format(time.RFC3339)
+	}
+	if request.ReviewedAt != nil {
+		result["reviewedAt"] = request.ReviewedAt.Format(time.RFC3339)
+	}
+	return result
+}
+
+func verificationDocumentJSON(d models.VerificationDocument) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          d.ID,
+		"kind":        d.Kind,
+		"name":        d.OriginalName,
+		"contentType": d.ContentType,
+		"size":        d.Size,
+		"createdAt":   d.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,283 @@
+// internal/handlers/webhooks.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+	"refurnish/internal/webhooks"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+const maxWebhooksPerUser = 10
+
+type webhookInput struct {
+	URL        *string  `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	IsActive   *bool    `json:"isActive"`
+}
+
+func validateEventTypes(types []string) bool {
+	if len(types) == 0 {
+		return false
+	}
+	for _, t := range types {
+		if !webhooks.IsEventType(t) {
+			return false
+		}
+	}
+	return true
+}
+
+func webhookJSON(h models.Webhook) map[string]interface{} {
+	result := map[string]interface{}{
+		"id":                  h.ID,
+		"url":                 h.URL,
+		"eventTypes":          h.EventTypes,
+		"isActive":            h.IsActive,
+		"consecutiveFailures": h.ConsecutiveFailures,
+		"createdAt":           h.CreatedAt.Format(time.RFC3339),
+	}
+	if h.DisabledAt != nil {
+		result["disabledAt"] = h.DisabledAt.Format(time.RFC3339)
+		result["disabledReason"] = h.DisabledReason
+	}
+	return result
+}
+
+// loadWebhook - вебхук из URL, принадлежащий текущему пользователю
+func loadWebhook(w http.ResponseWriter, r *http.Request, db *gorm.DB) (*models.Webhook, bool) {
+	userID := r.Context().Value("user_id").(string)
+
+	var hook models.Webhook
+	if err := db.First(&hook, "id = ? AND user_id = ?", chi.URLParam(r, "id"), userID).Error; err != nil {
+		http.Error(w, "Вебхук не найден", http.StatusNotFound)
+		return nil, false
+	}
+	return &hook, true
+}
+
+// MyWebhooks - GET /api/webhooks
+func MyWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+
+	var hooks []models.Webhook
+	if err := config.GetDB().Where("user_id = ?", userID).Order("created_at").Find(&hooks).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := []map[string]interface{}{}
+	for _, h := range hooks {
+		result = append(result, webhookJSON(h))
+	}
+	jsonResponse(w, map[string]interface{}{
+		"items":      result,
+		"eventTypes": webhooks.EventTypes,
+	})
+}
+
+// CreateWebhook - POST /api/webhooks {url, eventTypes}
+// Секрет для проверки подписи возвращается только здесь.
+func CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(string)
+	db := config.GetDB()
+
+	var input webhookInput
+	if err := parseJSON(r, &input); err != nil || input.URL == nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	hookURL, err := webhooks.CheckURL(r.Context(), *input.URL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !validateEventTypes(input.EventTypes) {
+		http.Error(w, "Укажите типы событий из списка: "+strings.Join(webhooks.EventTypes, ", "), http.StatusBadRequest)
+		return
+	}
+
+	var count int64
+	db.Model(&models.Webhook{}).Where("user_id = ?", userID).Count(&count)
+	if count >= maxWebhooksPerUser {
+		http.Error(w, "Можно создать не больше 10 вебхуков", http.StatusConflict)
+		return
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	hook := models.Webhook{
+		UserID:     userID,
+		URL:        hookURL,
+		EventTypes: input.EventTypes,
+		Secret:     "whsec_" + secret,
+		IsActive:   true,
+	}
+	if err := db.Create(&hook).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := webhookJSON(hook)
+	result["secret"] = hook.Secret
+	jsonResponse(w, result)
+}
+
+// UpdateWebhook - PUT /api/webhooks/{id}
+// Повторное включение сбрасывает счётчик неудач.
+func UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	hook, ok := loadWebhook(w, r, db)
+	if !ok {
+		return
+	}
+
+	var input webhookInput
+	if err := parseJSON(r, &input); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	if input.URL != nil {
+		hookURL, err := webhooks.CheckURL(r.Context(), *input.URL)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		hook.URL = hookURL
+	}
+	if input.EventTypes != nil {
+		if !validateEventTypes(input.EventTypes) {
+			http.Error(w, "Укажите типы событий из списка: "+strings.Join(webhooks.EventTypes, ", "), http.StatusBadRequest)
+			return
+		}
+		hook.EventTypes = input.EventTypes
+	}
+	if input.IsActive != nil {
+		if *input.IsActive && !hook.IsActive {
+			hook.ConsecutiveFailures = 0
+			hook.DisabledAt = nil
+			hook.DisabledReason = nil
+		}
+		hook.IsActive = *input.IsActive
+	}
+
+	if err := db.Save(hook).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	jsonResponse(w, webhookJSON(*hook))
+}
+
+// DeleteWebhook - DELETE /api/webhooks/{id}
+func DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	hook, ok := loadWebhook(w, r, db)
+	if !ok {
+		return
+	}
+	if err := db.Delete(hook).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	jsonResponse(w, map[string]string{"status": "deleted"})
+}
+
+// WebhookDeliveries - GET /api/webhooks/{id}/deliveries?status=&limit=&offset=
+func WebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	hook, ok := loadWebhook(w, r, db)
+	if !ok {
+		return
+	}
+
+	limit := 50
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := db.Where("webhook_id = ?", hook.ID)
+	if status := r.URL.Query().Get("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&deliveries).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	items := []map[string]interface{}{}
+	for _, d := range deliveries {
+		items = append(items, webhookDeliveryJSON(d))
+	}
+	jsonResponse(w, items)
+}
+
+// RedeliverWebhook - POST /api/webhooks/{id}/deliveries/{deliveryId}/redeliver
+func RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	hook, ok := loadWebhook(w, r, db)
+	if !ok {
+		return
+	}
+	if !hook.IsActive {
+		http.Error(w, "Вебхук отключён, сначала включите его", http.StatusConflict)
+		return
+	}
+
+	var original models.WebhookDelivery
+	if err := db.First(&original, "id = ? AND webhook_id = ?", chi.URLParam(r, "deliveryId"), hook.ID).Error; err != nil {
+		http.Error(w, "Доставка не найдена", http.StatusNotFound)
+		return
+	}
+
+	delivery, err := webhooks.Redeliver(db, original)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	jsonResponse(w, webhookDeliveryJSON(*delivery))
+}
+
+func webhookDeliveryJSON(d models.WebhookDelivery) map[string]interface{} {
+	result := map[string]interface{}{
+		"id":             d.ID,
+		"eventType":      d.EventType,
+		"eventId":        d.EventID,
+		"redeliveryOf":   d.RedeliveryOf,
+		"payload":        d.Payload,
+		"status":         d.Status,
+		"attempts":       d.Attempts,
+		"responseStatus": d.ResponseStatus,
+		"responseBody":   d.ResponseBody,
+		"lastError":      d.LastError,
+		"createdAt":      d.CreatedAt.Format(time.RFC3339),
+	}
+	if d.Status == "pending" {
+		result["nextAttemptAt"] = d.NextAttemptAt.Format(time.RFC3339)
+	}
+	if d.DeliveredAt != nil {
+		result["deliveredAt"] = d.DeliveredAt.Format(time.RFC3339)
+	}
+	return result
+}
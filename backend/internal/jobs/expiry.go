@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"refurnish/internal/events"
+
+	"gorm.io/gorm"
+)
+
+// ExpireProjects снимает с публикации проекты с прошедшим сроком
+// или висящие дольше lifetime. Клиентов уведомляют подписчики project.expired.
+func ExpireProjects(db *gorm.DB, lifetime time.Duration) func(context.Context) error {
+	return func(ctx context.Context) error {
+		now := time.Now()
+		today := now.Truncate(24 * time.Hour)
+
+		var expired []struct {
+			ID     string
+			Title  string
+			UserID string
+		}
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			err := tx.Raw(`
+				UPDATE projects p
+				SET status = 'expired', expired_at = ?, updated_at = ?
+				FROM clients c
+				WHERE c.id = p.client_id
+				  AND p.status = 'published'
+				  AND (p.deadline < ? OR COALESCE(p.published_at, p.created_at) < ?)
+				RETURNING p.id, p.title, c.user_id
+			`, now, now, today, now.Add(-lifetime)).Scan(&expired).Error
+			if err != nil {
+				return err
+			}
+			for _, p := range expired {
+				err := events.Emit(tx, events.ProjectExpired, p.ID, map[string]interface{}{
+					"projectTitle": p.Title,
+					"clientUserId": p.UserID,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(expired) > 0 {
+			events.Wake()
+			log.Printf("⌛ Снято с публикации: %d проект(ов)", len(expired))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,38 @@
+// internal/jobs/jobs.go
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every запускает fn сразу и затем с периодом interval, пока не отменён ctx.
+// Ошибки логируются, следующий запуск происходит по расписанию.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	log.Printf("⏱️  Фоновая задача %s: каждые %s", name, interval)
+
+	run := func() {
+		defer func() {
+			if p := recover(); p != nil {
+				log.Printf("❌ Фоновая задача %s упала: %v", name, p)
+			}
+		}()
+		if err := fn(ctx); err != nil {
+			log.Printf("❌ Фоновая задача %s: %v", name, err)
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	run()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
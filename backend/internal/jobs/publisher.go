@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"refurnish/internal/events"
+
+	"gorm.io/gorm"
+)
+
+// PublishScheduled публикует проекты, у которых наступило время publish_at
+func PublishScheduled(db *gorm.DB) func(context.Context) error {
+	return func(ctx context.Context) error {
+		now := time.Now()
+
+		var published []struct {
+			ID     string
+			Title  string
+			UserID string
+		}
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			err := tx.Raw(`
+				UPDATE projects p
+				SET status = 'published', published_at = ?, publish_at = NULL, updated_at = ?
+				FROM clients c
+				WHERE c.id = p.client_id AND p.status = 'scheduled' AND p.publish_at <= ?
+				RETURNING p.id, p.title, c.user_id
+			`, now, now, now).Scan(&published).Error
+			if err != nil {
+				return err
+			}
+			for _, p := range published {
+				err := events.Emit(tx, events.ProjectPublished, p.ID, map[string]interface{}{
+					"projectTitle": p.Title,
+					"clientUserId": p.UserID,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(published) > 0 {
+			events.Wake()
+			log.Printf("📣 Опубликовано по расписанию: %d проект(ов)", len(published))
+		}
+		return nil
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -88,7 +89,7 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			if ok1 {
 				finalUserID = userID
 			} else if ok2 {
-				finalUserID = string(int(userIdFloat)) // Конвертируем float64 в string
+				finalUserID = strconv.Itoa(int(userIdFloat)) // Конвертируем float64 в string
 			} else {
 				log.Printf("❌ [AUTH] Неверный токен: отсутствует user_id. Claims: %v", claims)
 				http.Error(w, "Неверный токен: отсутствует user_id", http.StatusUnauthorized)
@@ -116,3 +117,36 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// TicketRedeemer гасит одноразовый билет и возвращает user_id и роль владельца
+type TicketRedeemer func(ticket string) (userID, role string, err error)
+
+// QueryTicket - аутентификация для realtime: браузер не умеет слать заголовки
+// в WebSocket и EventSource, поэтому принимается одноразовый ?ticket=.
+// JWT в URL не принимается - адрес запроса попадает в логи.
+// Без билета работает обычная проверка заголовка Authorization.
+func QueryTicket(redeem TicketRedeemer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		withHeader := AuthMiddleware(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ticket := r.URL.Query().Get("ticket")
+			if ticket == "" {
+				withHeader.ServeHTTP(w, r)
+				return
+			}
+
+			userID, role, err := redeem(ticket)
+			if err != nil {
+				log.Printf("❌ [AUTH] Билет realtime не принят: %v", err)
+				http.Error(w, "Недействительный билет", http.StatusUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), "user_id", userID)
+			if role != "" {
+				ctx = context.WithValue(ctx, "role", role)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
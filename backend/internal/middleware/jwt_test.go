@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestQueryTicket(t *testing.T) {
+	used := map[string]bool{}
+	redeem := func(ticket string) (string, string, error) {
+		if ticket != "good" || used[ticket] {
+			return "", "", errors.New("недействительный билет")
+		}
+		used[ticket] = true
+		return "user-1", "client", nil
+	}
+
+	var gotUser, gotRole string
+	handler := QueryTicket(redeem)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, _ = r.Context().Value("user_id").(string)
+		gotRole, _ = r.Context().Value("role").(string)
+	}))
+
+	tests := []struct {
+		name string
+		url  string
+		want int
+	}{
+		{"билет", "/api/realtime/ws?ticket=good", http.StatusOK},
+		{"повторный билет", "/api/realtime/ws?ticket=good", http.StatusUnauthorized},
+		{"чужой билет", "/api/realtime/ws?ticket=bad", http.StatusUnauthorized},
+		{"JWT в URL не принимается", "/api/realtime/ws?token=eyJhbGciOiJIUzI1NiJ9.e30.x", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		gotUser, gotRole = "", ""
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.url, nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s: код %d, want %d", tt.name, rec.Code, tt.want)
+		}
+		if tt.want == http.StatusOK && (gotUser != "user-1" || gotRole != "client") {
+			t.Errorf("%s: user_id=%q role=%q", tt.name, gotUser, gotRole)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// RealIP подставляет в r.RemoteAddr адрес клиента из X-Forwarded-For или
+// X-Real-IP, но только для запросов от доверенных прокси (trusted - IP или
+// CIDR). Остальным заголовки не помогут подделать IP, по которому модерация
+// связывает отзывы. Из X-Forwarded-For берётся самый правый адрес, не
+// принадлежащий доверенным прокси: левую часть цепочки пишет сам клиент.
+func RealIP(trusted []string) func(http.Handler) http.Handler {
+	var nets []*net.IPNet
+	for _, v := range trusted {
+		if !strings.Contains(v, "/") {
+			if ip := net.ParseIP(v); ip != nil && ip.To4() != nil {
+				v += "/32"
+			} else {
+				v += "/128"
+			}
+		}
+		_, n, err := net.ParseCIDR(v)
+		if err != nil {
+			log.Printf("⚠️  TRUSTED_PROXIES: пропущен неверный адрес %q", v)
+			continue
+		}
+		nets = append(nets, n)
+	}
+
+	isTrusted := func(ip net.IP) bool {
+		for _, n := range nets {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(nets) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			host, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				host = r.RemoteAddr
+			}
+			if peer := net.ParseIP(host); peer == nil || !isTrusted(peer) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			client := ""
+			if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+				hops := strings.Split(xff, ",")
+				for i := len(hops) - 1; i >= 0; i-- {
+					ip := net.ParseIP(strings.TrimSpace(hops[i]))
+					if ip == nil {
+						break
+					}
+					client = ip.String()
+					if !isTrusted(ip) {
+						break
+					}
+				}
+			} else if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+				client = ip.String()
+			}
+			if client != "" {
+				r.RemoteAddr = client
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIP(t *testing.T) {
+	var got string
+	handler := RealIP([]string{"10.0.0.0/8", "192.168.1.10"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.RemoteAddr
+	}))
+
+	tests := []struct {
+		name   string
+		remote string
+		xff    string
+		realIP string
+		want   string
+	}{
+		{"прямое соединение: заголовки игнорируются", "203.0.113.7:5000", "198.51.100.1", "198.51.100.2", "203.0.113.7:5000"},
+		{"доверенный прокси", "10.0.0.2:5000", "198.51.100.1", "", "198.51.100.1"},
+		{"подделанная левая часть цепочки", "10.0.0.2:5000", "1.2.3.4, 198.51.100.1", "", "198.51.100.1"},
+		{"цепочка доверенных прокси", "10.0.0.2:5000", "198.51.100.1, 192.168.1.10, 10.0.0.3", "", "198.51.100.1"},
+		{"X-Real-IP от прокси", "192.168.1.10:5000", "", "198.51.100.9", "198.51.100.9"},
+		{"мусор в заголовке", "10.0.0.2:5000", "not-an-ip", "", "10.0.0.2:5000"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tt.remote
+		if tt.xff != "" {
+			req.Header.Set("X-Forwarded-For", tt.xff)
+		}
+		if tt.realIP != "" {
+			req.Header.Set("X-Real-IP", tt.realIP)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if got != tt.want {
+			t.Errorf("%s: RemoteAddr = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// Без настроенных прокси заголовки не учитываются вовсе
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.2:5000"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	RealIP(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { got = r.RemoteAddr })).
+		ServeHTTP(httptest.NewRecorder(), req)
+	if got != "10.0.0.2:5000" {
+		t.Errorf("без TRUSTED_PROXIES: RemoteAddr = %q", got)
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+)
+
+// RequireRole пропускает только пользователей с указанной ролью.
+// Должен подключаться после AuthMiddleware.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userRole, _ := r.Context().Value("role").(string)
+			if userRole != role {
+				log.Printf("❌ [AUTH] Роль %q не допускается к %s %s", userRole, r.Method, r.URL.Path)
+				http.Error(w, "Недостаточно прав", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
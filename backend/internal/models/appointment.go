@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Appointment - выезд мастера на замер по проекту
+type Appointment struct {
+	ID             string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProjectID      string `gorm:"type:uuid;not null"`
+	MasterID       string `gorm:"type:uuid;not null"`
+	Status         string `gorm:"default:proposed"` // proposed, confirmed, cancelled
+	StartsAt       *time.Time
+	EndsAt         *time.Time
+	Address        string
+	Note           string
+	CancelReason   *string
+	CancelledBy    *string // client, master
+	RemindedDayAt  *time.Time
+	RemindedHourAt *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	// Связи
+	Project *Project          `gorm:"foreignKey:ProjectID"`
+	Master  *Master           `gorm:"foreignKey:MasterID"`
+	Slots   []AppointmentSlot `gorm:"foreignKey:AppointmentID"`
+}
+
+// AppointmentSlot - вариант времени, предложенный мастером
+type AppointmentSlot struct {
+	ID            string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	AppointmentID string `gorm:"type:uuid;not null"`
+	StartsAt      time.Time
+	EndsAt        time.Time
+}
+
+// AppointmentChange - запись истории выезда
+type AppointmentChange struct {
+	ID            string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	AppointmentID string `gorm:"type:uuid;not null"`
+	ActorRole     string `gorm:"not null"` // client, master, system
+	Action        string `gorm:"not null"`
+	Reason        string
+	StartsAt      *time.Time
+	CreatedAt     time.Time
+}
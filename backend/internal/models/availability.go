@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// WorkingHours - рабочие часы мастера в один день недели
+type WorkingHours struct {
+	MasterID    string `gorm:"type:uuid;primaryKey"`
+	Weekday     int    `gorm:"primaryKey"` // 1 - понедельник ... 7 - воскресенье
+	StartMinute int    // минуты от полуночи: 540 - 09:00
+	EndMinute   int
+}
+
+func (WorkingHours) TableName() string {
+	return "master_working_hours"
+}
+
+// TimeOff - выходной или отпуск мастера, даты включительно
+type TimeOff struct {
+	ID        string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	MasterID  string    `gorm:"type:uuid;not null"`
+	Kind      string    `gorm:"default:day_off"` // day_off, vacation
+	StartsOn  time.Time `gorm:"type:date"`
+	EndsOn    time.Time `gorm:"type:date"`
+	Note      string
+	CreatedAt time.Time
+}
+
+func (TimeOff) TableName() string {
+	return "master_time_off"
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Category - узел иерархического справочника мебели (он же специализация мастера)
+type Category struct {
+	ID        string  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Slug      string  `gorm:"uniqueIndex;not null"`
+	ParentID  *string `gorm:"type:uuid"`
+	NameRu    string  `gorm:"not null"`
+	NameEn    string  `gorm:"not null"`
+	SortOrder int     `gorm:"default:0"`
+	IsActive  bool    // без default-тега: с ним gorm не пишет false в INSERT
+	// Описание полей спецификации проекта (см. internal/spec)
+	SpecSchema JSONB `gorm:"type:jsonb"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	// Связи
+	Parent *Category `gorm:"foreignKey:ParentID"`
+}
+
+// LocalizedName возвращает название категории на нужном языке (по умолчанию русский)
+func (c Category) LocalizedName(lang string) string {
+	if lang == "en" && c.NameEn != "" {
+		return c.NameEn
+	}
+	return c.NameRu
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Неактивная категория должна сохраняться неактивной: false не должен
+// выпадать из INSERT в пользу значения по умолчанию в базе
+func TestCategoryInsertKeepsInactive(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := db.Create(&Category{Slug: "archive", NameRu: "Архив", NameEn: "Archive", IsActive: false})
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	stmt := result.Statement
+	sql := stmt.SQL.String()
+	if !strings.Contains(sql, `"is_active"`) {
+		t.Fatalf("is_active нет в INSERT: %s", sql)
+	}
+	for _, v := range stmt.Vars {
+		if b, ok := v.(bool); ok && b {
+			t.Fatalf("в INSERT попало true: %s %v", sql, stmt.Vars)
+		}
+	}
+}
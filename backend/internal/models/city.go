@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// City - запись справочника городов
+type City struct {
+	ID         string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Name       string `gorm:"uniqueIndex;not null"`
+	Region     string
+	Timezone   string
+	Latitude   float64
+	Longitude  float64
+	Population int
+	Aliases    StringArray `gorm:"type:text[]"`
+	// Нормализованные варианты названия (имя + алиасы) для поиска
+	SearchKeys StringArray `gorm:"type:text[]"`
+	CreatedAt  time.Time
+}
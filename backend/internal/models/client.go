@@ -3,11 +3,14 @@ package models
 import "time"
 
 type Client struct {
-	ID        string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	UserID    string `gorm:"type:uuid;not null"`
-	Name      string
-	Phone     string
-	CreatedAt time.Time
+	ID     string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID string `gorm:"type:uuid;not null"`
+	Name   string
+	Phone  string
+	// Репутация по отзывам мастеров, см. internal/ratings
+	Reputation   float64
+	ReviewsCount int
+	CreatedAt    time.Time
 
 	// Связи
 	User     *User      `gorm:"foreignKey:UserID"`
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// ClientReview - отзыв мастера о клиенте по завершённому проекту
+type ClientReview struct {
+	ID            string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProjectID     string `gorm:"type:uuid;uniqueIndex;not null"`
+	MasterID      string `gorm:"type:uuid;not null"`
+	ClientID      string `gorm:"type:uuid;not null"`
+	Rating        int    `gorm:"not null"` // 1-5
+	Payment       *int   // подоценки 1-5: оплата, общение, соответствие описанию
+	Communication *int
+	Accuracy      *int
+	Text          string
+	PublishedAt   *time.Time // nil - скрыт до открытия обоих отзывов
+	// Модерация: "pending", "approved" или "rejected"
+	ModerationStatus string   `gorm:"default:approved"`
+	Flags            []string `gorm:"type:jsonb;serializer:json"` // автоматические флаги, см. internal/moderation
+	IP               *string
+	CreatedAt        time.Time
+
+	// Связи
+	Master  *Master  `gorm:"foreignKey:MasterID"`
+	Client  *Client  `gorm:"foreignKey:ClientID"`
+	Project *Project `gorm:"foreignKey:ProjectID"`
+}
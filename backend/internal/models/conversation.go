@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Conversation - переписка клиента и мастера в рамках проекта
+type Conversation struct {
+	ID            string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProjectID     string `gorm:"type:uuid;not null"`
+	ClientID      string `gorm:"type:uuid;not null"`
+	MasterID      string `gorm:"type:uuid;not null"`
+	LastMessageAt *time.Time
+	CreatedAt     time.Time
+
+	// Связи
+	Project *Project `gorm:"foreignKey:ProjectID"`
+	Client  *Client  `gorm:"foreignKey:ClientID"`
+	Master  *Master  `gorm:"foreignKey:MasterID"`
+}
+
+// Message - сообщение в переписке
+type Message struct {
+	ID             string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ConversationID string `gorm:"type:uuid;not null"`
+	SenderID       string `gorm:"type:uuid;not null"` // users.id
+	Body           string
+	ReadAt         *time.Time
+	CreatedAt      time.Time
+
+	// Связи
+	Attachments []MessageAttachment `gorm:"foreignKey:MessageID"`
+}
+
+// MessageAttachment - файл, приложенный к сообщению
+type MessageAttachment struct {
+	ID             string  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ConversationID string  `gorm:"type:uuid;not null"`
+	MessageID      *string `gorm:"type:uuid"`
+	UploaderID     string  `gorm:"type:uuid;not null"`
+	URL            string  `gorm:"column:url;not null"` // имя файла в uploads.PrivateDir (старые - /uploads/...)
+	FileName       string
+	ContentType    string
+	Size           int64
+	CreatedAt      time.Time
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Invitation - приглашение мастера оценить проект
+type Invitation struct {
+	ID            string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProjectID     string `gorm:"type:uuid;not null"`
+	MasterID      string `gorm:"type:uuid;not null"`
+	Status        string `gorm:"default:'pending'"` // pending, accepted, declined
+	Message       string
+	DeclineReason string
+	RespondedAt   *time.Time
+	CreatedAt     time.Time
+
+	// Связи
+	Project *Project `gorm:"foreignKey:ProjectID"`
+	Master  *Master  `gorm:"foreignKey:MasterID"`
+}
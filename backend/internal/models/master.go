@@ -3,15 +3,24 @@ package models
 import "time"
 
 type Master struct {
-	ID              string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	UserID          string `gorm:"type:uuid;not null"`
-	Name            string `gorm:"not null"`
+	ID              string  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID          string  `gorm:"type:uuid;not null"`
+	Name            string  `gorm:"not null"`
+	Slug            *string // адрес публичного профиля, см. internal/slug
 	Description     string
 	City            string
-	Specializations []string `gorm:"type:text[]"`
+	Specializations StringArray `gorm:"type:text[]"`
 	PriceFrom       int
-	Rating          float64 `gorm:"default:0"`
-	CreatedAt       time.Time
+	ServiceRadiusKm int // выезд за пределы своего города, 0 - только свой город
+	// Сколько проектов мастер ведёт одновременно, см. internal/availability
+	MaxConcurrentProjects int     `gorm:"default:3"`
+	Rating                float64 `gorm:"default:0"` // байесовское среднее отзывов, см. internal/ratings
+	ReviewsCount          int
+	// Проверка модератором, см. internal/verification. nil - не проверен
+	VerifiedAt *time.Time
+	INN        *string `gorm:"column:inn"`
+	OGRNIP     *string `gorm:"column:ogrnip"`
+	CreatedAt  time.Time
 
 	// Связи
 	User      *User       `gorm:"foreignKey:UserID"`
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"refurnish/internal/testdb"
+)
+
+// Специализации читаются обратно из text[], в том числе через Find и Preload
+func TestMasterSpecializationsRoundTrip(t *testing.T) {
+	db := testdb.Open(t)
+
+	user := User{Email: "master@example.com", Password: "x", Role: "master"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	want := StringArray{"kitchen", "мягкая мебель", "a,b"}
+	master := Master{UserID: user.ID, Name: "Иван", Specializations: want}
+	if err := db.Create(&master).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var got Master
+	if err := db.First(&got, "id = ?", master.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Specializations, want) {
+		t.Errorf("Specializations = %q, ожидалось %q", got.Specializations, want)
+	}
+
+	var found []Master
+	if err := db.Where("? = ANY(specializations)", "мягкая мебель").Find(&found).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || !reflect.DeepEqual(found[0].Specializations, want) {
+		t.Errorf("поиск по специализации вернул %+v", found)
+	}
+}
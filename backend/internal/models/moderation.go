@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// ReviewDispute - мастер или клиент оспаривает отзыв о себе. Kind "master" -
+// отзыв клиента о мастере (ReviewID), "client" - отзыв мастера о клиенте
+// (ClientReviewID)
+type ReviewDispute struct {
+	ID             string  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Kind           string  `gorm:"default:master"`
+	ReviewID       *string `gorm:"type:uuid"`
+	ClientReviewID *string `gorm:"type:uuid"`
+	OpenedBy       string  `gorm:"type:uuid;not null"` // пользователь, о ком отзыв
+	Reason         string  `gorm:"not null"`
+	Status         string  `gorm:"default:open"` // "open", "accepted" или "rejected"
+	Resolution     *string
+	ResolvedBy     *string `gorm:"type:uuid"`
+	ResolvedAt     *time.Time
+	CreatedAt      time.Time
+
+	// Связи
+	Review       *Review       `gorm:"foreignKey:ReviewID"`
+	ClientReview *ClientReview `gorm:"foreignKey:ClientReviewID"`
+}
+
+// ModerationAction - запись журнала модерации по отзыву вида Kind,
+// как в ReviewDispute
+type ModerationAction struct {
+	ID             string  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Kind           string  `gorm:"default:master"`
+	ReviewID       *string `gorm:"type:uuid"`
+	ClientReviewID *string `gorm:"type:uuid"`
+	ActorID        *string `gorm:"type:uuid"` // nil - автоматическая проверка
+	Action         string  `gorm:"not null"`
+	Reason         string
+	Details        map[string]interface{} `gorm:"type:jsonb;serializer:json"`
+	CreatedAt      time.Time
+}
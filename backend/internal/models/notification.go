@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Notification - уведомление во входящих пользователя
+type Notification struct {
+	ID        string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID    string `gorm:"type:uuid;not null"`
+	Type      string `gorm:"not null"`
+	Title     string `gorm:"not null"`
+	Body      string
+	Data      JSONB `gorm:"type:jsonb"`
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// NotificationPreference - переопределение канала для типа уведомлений
+type NotificationPreference struct {
+	UserID    string `gorm:"type:uuid;primaryKey"`
+	Type      string `gorm:"primaryKey"`
+	Channel   string `gorm:"primaryKey"`
+	Enabled   bool
+	UpdatedAt time.Time
+}
+
+// NotificationDelivery - попытки доставки уведомления во внешний канал
+type NotificationDelivery struct {
+	ID             string  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	NotificationID *string `gorm:"type:uuid"`
+	UserID         string  `gorm:"type:uuid;not null"`
+	Type           string  `gorm:"not null"`
+	Channel        string  `gorm:"not null"`
+	Address        string  `gorm:"not null"`
+	Title          string  `gorm:"not null"`
+	Body           string
+	Data           JSONB  `gorm:"type:jsonb"`
+	Status         string `gorm:"default:pending"` // pending, sent, failed
+	Attempts       int
+	LastError      *string
+	NextAttemptAt  time.Time
+	SentAt         *time.Time
+	CreatedAt      time.Time
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// OutboxEvent - доменное событие, ожидающее доставки подписчикам
+type OutboxEvent struct {
+	ID            int64  `gorm:"primaryKey"`
+	EventType     string `gorm:"not null"`
+	AggregateID   string `gorm:"not null"`
+	Payload       JSONB  `gorm:"type:jsonb"`
+	Attempts      int
+	LastError     *string
+	NextAttemptAt time.Time `gorm:"default:now()"`
+	LockedUntil   *time.Time
+	DispatchedAt  *time.Time
+	FailedAt      *time.Time
+	CreatedAt     time.Time
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox"
+}
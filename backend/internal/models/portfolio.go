@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// PortfolioItem - работа в портфолио мастера
+type PortfolioItem struct {
+	ID          string  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	MasterID    string  `gorm:"type:uuid;not null"`
+	Title       string  `gorm:"not null"`
+	CategoryID  *string `gorm:"type:uuid"`
+	Description string
+	Price       *int
+	CompletedOn *time.Time `gorm:"type:date"`
+	ProjectID   *string    `gorm:"type:uuid"` // работа создана из завершённого проекта
+	SortOrder   int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	// Связи
+	Category *Category        `gorm:"foreignKey:CategoryID"`
+	Photos   []PortfolioPhoto `gorm:"foreignKey:ItemID"`
+}
+
+// PortfolioPhoto - фото работы: "before" или "after"
+type PortfolioPhoto struct {
+	ID        string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ItemID    string `gorm:"type:uuid;not null"`
+	URL       string `gorm:"column:url;not null"`
+	Kind      string `gorm:"not null"`
+	SortOrder int
+	CreatedAt time.Time
+}
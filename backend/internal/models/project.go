@@ -8,18 +8,29 @@ type Project struct {
 	ID            string `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
 	Title         string `gorm:"not null"`
 	Description   string
-	FurnitureType string
+	FurnitureType string    // slug категории из справочника
+	CategoryID    *string   `gorm:"type:uuid"`
+	Category      *Category `gorm:"foreignKey:CategoryID"`
+	Spec          JSONB     `gorm:"type:jsonb"` // значения полей спецификации категории
 	Budget        int
 	Deadline      time.Time
 	City          string
-	Status        string `gorm:"default:'published'"`
+	Status        string     `gorm:"default:'published'"` // draft, scheduled, published, expired, assigned, completed
+	PublishAt     *time.Time // время отложенной публикации
+	PublishedAt   *time.Time
+	ExpiredAt     *time.Time
+	AssignedAt    *time.Time
+	CompletedAt   *time.Time
+	// Клиент разрешил мастеру показать проект в портфолио
+	PortfolioConsent bool
+	Visibility       string `gorm:"default:'public'"` // public, invite_only, link_only
 
 	// ИСПРАВЛЕНО: используем *string для nullable UUID
 	ClientID string `gorm:"type:uuid;not null"`
 	Client   Client `gorm:"foreignKey:ClientID;references:ID"`
 
 	// ИСПРАВЛЕНО: используем *string вместо string для nullable
-	MasterID  *string `gorm:"type:uuid"`
+	MasterID  *string `gorm:"type:uuid;column:assigned_master"`
 	Master    *Master `gorm:"foreignKey:MasterID;references:ID"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
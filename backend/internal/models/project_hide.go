@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// ProjectHide - проект, который мастер скрыл из своей ленты
+type ProjectHide struct {
+	MasterID  string `gorm:"type:uuid;primaryKey"`
+	ProjectID string `gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time
+}
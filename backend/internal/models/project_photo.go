@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type ProjectPhoto struct {
+	ID        string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProjectID string `gorm:"type:uuid;not null"`
+	URL       string `gorm:"column:url;not null"`
+	SortOrder int
+	CreatedAt time.Time
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ProjectQuestion - вопрос мастера по проекту и ответ клиента
+type ProjectQuestion struct {
+	ID        string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProjectID string `gorm:"type:uuid;not null"`
+	MasterID  string `gorm:"type:uuid;not null"`
+	Question  string `gorm:"not null"`
+	Answer    string
+	// В вопросе или ответе были скрыты контактные данные
+	ContactsMasked bool
+	AnsweredAt     *time.Time
+	CreatedAt      time.Time
+
+	// Связи
+	Master *Master `gorm:"foreignKey:MasterID"`
+}
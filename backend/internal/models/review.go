@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Review - отзыв клиента о мастере по завершённому проекту
+type Review struct {
+	ID            string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProjectID     string `gorm:"type:uuid;uniqueIndex;not null"`
+	ClientID      string `gorm:"type:uuid;not null"`
+	MasterID      string `gorm:"type:uuid;not null"`
+	Rating        int    `gorm:"not null"` // 1-5
+	Quality       *int   // подоценки 1-5, необязательные
+	Punctuality   *int
+	Communication *int
+	Text          string
+	Reply         *string // ответ мастера
+	RepliedAt     *time.Time
+	PublishedAt   *time.Time // nil - скрыт до открытия обоих отзывов
+	// Модерация: "pending", "approved" или "rejected"
+	ModerationStatus string   `gorm:"default:approved"`
+	Flags            []string `gorm:"type:jsonb;serializer:json"` // автоматические флаги, см. internal/moderation
+	IP               *string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+
+	// Связи
+	Project *Project      `gorm:"foreignKey:ProjectID"`
+	Client  *Client       `gorm:"foreignKey:ClientID"`
+	Photos  []ReviewPhoto `gorm:"foreignKey:ReviewID"`
+}
+
+// ReviewPhoto - фото к отзыву
+type ReviewPhoto struct {
+	ID        string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ReviewID  string `gorm:"type:uuid;not null"`
+	URL       string `gorm:"not null"`
+	SortOrder int
+	CreatedAt time.Time
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// SavedSearch - сохранённый поиск мастера, по которому приходят оповещения
+type SavedSearch struct {
+	ID               string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	MasterID         string `gorm:"type:uuid;not null"`
+	Name             string `gorm:"not null"`
+	City             string
+	CategoryID       *string `gorm:"type:uuid"`
+	BudgetMin        *int
+	BudgetMax        *int
+	Keywords         string
+	RadiusKm         int
+	Delivery         string `gorm:"default:'instant'"` // instant, daily
+	UnsubscribeToken string `gorm:"uniqueIndex;not null"`
+	IsActive         bool   `gorm:"default:true"`
+	LastDigestAt     *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+
+	// Связи
+	Master   *Master   `gorm:"foreignKey:MasterID"`
+	Category *Category `gorm:"foreignKey:CategoryID"`
+}
+
+// SavedSearchMatch - проект, подошедший под сохранённый поиск
+type SavedSearchMatch struct {
+	SearchID  string `gorm:"type:uuid;primaryKey"`
+	ProjectID string `gorm:"type:uuid;primaryKey"`
+	SentAt    *time.Time
+	CreatedAt time.Time
+}
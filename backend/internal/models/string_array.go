@@ -0,0 +1,58 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// StringArray - колонка text[]. Через database/sql драйвер pgx отдаёт
+// массив строкой вида {a,"b c"}, поэтому разбор и запись - через pgtype.
+type StringArray []string
+
+// pgtype.Map кэширует планы разбора и не рассчитан на конкурентный доступ
+var (
+	arrayTypes   = pgtype.NewMap()
+	arrayTypesMu sync.Mutex
+)
+
+// Value реализует driver.Valuer. Пустой массив пишется как '{}', не NULL:
+// колонки справочника городов NOT NULL
+func (a StringArray) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	arrayTypesMu.Lock()
+	defer arrayTypesMu.Unlock()
+	buf, err := arrayTypes.Encode(pgtype.TextArrayOID, pgtype.TextFormatCode, []string(a), nil)
+	if err != nil {
+		return nil, err
+	}
+	return string(buf), nil
+}
+
+// Scan реализует sql.Scanner
+func (a *StringArray) Scan(value interface{}) error {
+	var src []byte
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case string:
+		src = []byte(v)
+	case []byte:
+		src = v
+	default:
+		return fmt.Errorf("StringArray: неподдерживаемый тип %T", value)
+	}
+	arrayTypesMu.Lock()
+	defer arrayTypesMu.Unlock()
+	var items []string
+	if err := arrayTypes.Scan(pgtype.TextArrayOID, pgtype.TextFormatCode, src, &items); err != nil {
+		return err
+	}
+	*a = items
+	return nil
+}
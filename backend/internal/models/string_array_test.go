@@ -0,0 +1,59 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStringArrayRoundTrip(t *testing.T) {
+	cases := []StringArray{
+		{},
+		{"москва"},
+		{"санкт петербург", "спб", "питер"},
+		{`с "кавычками"`, `back\slash`, "запятая, внутри", "{скобки}", "NULL", ""},
+	}
+	for _, want := range cases {
+		value, err := want.Value()
+		if err != nil {
+			t.Fatalf("Value(%q): %v", want, err)
+		}
+		var got StringArray
+		if err := got.Scan(value); err != nil {
+			t.Fatalf("Scan(%v): %v", value, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("после записи и чтения %q получилось %q", want, got)
+		}
+	}
+}
+
+func TestStringArrayScan(t *testing.T) {
+	// Так массив text[] приходит из драйвера pgx через database/sql
+	cases := map[string]StringArray{
+		`{}`:                     {},
+		`{a,b}`:                  {"a", "b"},
+		`{"нижний новгород",нн}`: {"нижний новгород", "нн"},
+		`{"a \"b\"","c\\d"}`:     {`a "b"`, `c\d`},
+	}
+	for src, want := range cases {
+		var got StringArray
+		if err := got.Scan(src); err != nil {
+			t.Fatalf("Scan(%s): %v", src, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Scan(%s) = %q, ожидалось %q", src, got, want)
+		}
+		got = nil
+		if err := got.Scan([]byte(src)); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("Scan([]byte(%s)) = %q, %v", src, got, err)
+		}
+	}
+
+	var null StringArray = StringArray{"x"}
+	if err := null.Scan(nil); err != nil || null != nil {
+		t.Errorf("NULL должен давать nil, получено %q, %v", null, err)
+	}
+	if value, _ := StringArray(nil).Value(); value != "{}" {
+		t.Errorf("nil пишется как %v, ожидалось {}", value)
+	}
+}
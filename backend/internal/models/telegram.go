@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// TelegramLink - привязка пользователя к чату с ботом
+type TelegramLink struct {
+	UserID   string `gorm:"type:uuid;primaryKey"`
+	ChatID   int64  `gorm:"uniqueIndex;not null"`
+	Username string
+	LinkedAt time.Time `gorm:"default:now()"`
+}
+
+// TelegramLinkCode - одноразовый код привязки
+type TelegramLinkCode struct {
+	Code      string `gorm:"primaryKey"`
+	UserID    string `gorm:"type:uuid;not null"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -10,7 +10,8 @@ type User struct {
 	ID        string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
 	Email     string `gorm:"uniqueIndex;not null"`
 	Password  string `gorm:"not null"`
-	Role      string `gorm:"not null"` // "client" или "master"
+	Role      string `gorm:"not null"`   // "client", "master" или "admin"
+	Language  string `gorm:"default:ru"` // язык уведомлений: "ru" или "en"
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// VerificationRequest - заявка мастера на проверку личности и статуса
+type VerificationRequest struct {
+	ID          string  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	MasterID    string  `gorm:"type:uuid;not null"`
+	Status      string  `gorm:"default:draft"` // draft, pending, approved, rejected
+	INN         *string `gorm:"column:inn"`
+	OGRNIP      *string `gorm:"column:ogrnip"`
+	Reason      *string // причина отказа
+	ReviewedBy  *string `gorm:"type:uuid"`
+	SubmittedAt *time.Time
+	ReviewedAt  *time.Time
+	CreatedAt   time.Time
+
+	// Связи
+	Master    *Master                `gorm:"foreignKey:MasterID"`
+	Documents []VerificationDocument `gorm:"foreignKey:RequestID"`
+}
+
+// VerificationDocument - скан или фото документа, файл в uploads.PrivateDir
+type VerificationDocument struct {
+	ID           string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	RequestID    string `gorm:"type:uuid;not null"`
+	Kind         string `gorm:"not null"`
+	FileName     string `gorm:"not null"`
+	OriginalName string
+	ContentType  string
+	Size         int64
+	CreatedAt    time.Time
+}
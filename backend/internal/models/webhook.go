@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Webhook - подписка партнёра на события его проектов
+type Webhook struct {
+	ID                  string   `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID              string   `gorm:"type:uuid;not null"`
+	URL                 string   `gorm:"not null"`
+	EventTypes          []string `gorm:"type:jsonb;serializer:json"`
+	Secret              string   `gorm:"not null"`
+	IsActive            bool     `gorm:"default:true"`
+	ConsecutiveFailures int
+	DisabledAt          *time.Time
+	DisabledReason      *string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// WebhookDelivery - попытки доставки события на URL вебхука
+type WebhookDelivery struct {
+	ID             string  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	WebhookID      string  `gorm:"type:uuid;not null"`
+	EventID        *int64  // id события в outbox
+	RedeliveryOf   *string `gorm:"type:uuid"`
+	EventType      string  `gorm:"not null"`
+	Payload        JSONB   `gorm:"type:jsonb"`
+	Status         string  `gorm:"default:pending"` // pending, success, failed
+	Attempts       int
+	ResponseStatus *int
+	ResponseBody   *string
+	LastError      *string
+	NextAttemptAt  time.Time `gorm:"default:now()"`
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+}
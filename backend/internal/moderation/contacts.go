@@ -0,0 +1,58 @@
+// internal/moderation/contacts.go
+package moderation
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ContactPlaceholder подставляется вместо найденных контактов
+const ContactPlaceholder = "[контакты скрыты]"
+
+// Границы слов - как в profanity.go: \b в RE2 понимает только ASCII и
+// между кириллическими буквами не срабатывает. Группы pre/post возвращаются
+// в текст при замене.
+var contactPatterns = []*regexp.Regexp{
+	// email
+	regexp.MustCompile(`(?i)[a-z0-9._%+\-]+\s*(@|\(at\)|\[at\])\s*[a-z0-9.\-]+\s*(\.|\(dot\))\s*[a-z]{2,}`),
+	// ссылки и мессенджеры
+	regexp.MustCompile(`(?i)(https?://|www\.)\S+`),
+	// Короткие ссылки маскируются, что бы ни стояло перед ними: "пишитеt.me/ivan"
+	regexp.MustCompile(`(?i)(t\.me|wa\.me|vk\.com|instagram\.com|ok\.ru)/\S*`),
+	regexp.MustCompile(`(?i)(^|\s)@[a-z0-9_]{4,}`),
+	regexp.MustCompile(`(?i)(?P<pre>^|[^\p{L}])(whats\s?app|(ватсап|вотсап|вацап|вайбер|скайп)(а|е|у|ом)?|` +
+		`telegram|телеграм\p{L}*|телег[аеуи]|viber|skype)(?P<post>$|[^\p{L}])`),
+	// телефоны: +7 (921) 394-65-09, 89213946509, 8 921 394 65 09, 921 394 65 09.
+	// Только российские номера - +7/8 и 10 цифр или мобильный на 9 без префикса,
+	// между группами не больше одного пробела или дефиса. Цены и диапазоны
+	// ("150 000 - 200 000") под эту форму не подходят.
+	regexp.MustCompile(`(?P<pre>^|\D)((\+7|8)[ \-]?\(?\d{3}\)?|\(?9\d{2}\)?)[ \-]?\d{3}[ \-]?\d{2}[ \-]?\d{2}(?P<post>$|\D)`),
+}
+
+// MaskContacts заменяет телефоны, email, ссылки и упоминания мессенджеров.
+// Возвращает очищенный текст и признак того, что что-то было скрыто.
+func MaskContacts(text string) (string, bool) {
+	masked := false
+	for _, re := range contactPatterns {
+		replacement := " " + ContactPlaceholder + " "
+		if re.SubexpIndex("pre") >= 0 {
+			replacement = "${pre}" + ContactPlaceholder + "${post}"
+		}
+		// Граница после совпадения съедается, поэтому соседние слова
+		// ("ватсап телеграм") находятся только на следующем проходе
+		for re.MatchString(text) {
+			masked = true
+			text = re.ReplaceAllString(text, replacement)
+		}
+	}
+	if masked {
+		text = strings.Join(strings.Fields(text), " ")
+	}
+	return text, masked
+}
+
+// HasContacts сообщает, есть ли в тексте контактные данные
+func HasContacts(text string) bool {
+	_, found := MaskContacts(text)
+	return found
+}
@@ -0,0 +1,39 @@
+package moderation
+
+import "testing"
+
+func TestMaskContacts(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		want   string
+		masked bool
+	}{
+		{"кириллица в конце строки", "пишите в ватсап", "пишите в [контакты скрыты]", true},
+		{"кириллица перед запятой", "телеграм, вайбер - как удобно", "[контакты скрыты], [контакты скрыты] - как удобно", true},
+		{"падежные окончания", "спишемся в вайбере или по скайпу", "спишемся в [контакты скрыты] или по [контакты скрыты]", true},
+		{"соседние слова", "ватсап телега", "[контакты скрыты] [контакты скрыты]", true},
+		{"латиница", "Найдите меня в WhatsApp", "Найдите меня в [контакты скрыты]", true},
+		{"часть другого слова", "bigtelegram и ватсапер", "bigtelegram и ватсапер", false},
+		{"обычный текст", "телевизор поставим на тумбу", "телевизор поставим на тумбу", false},
+		{"ссылка вплотную к слову", "пишитеt.me/ivan", "пишите [контакты скрыты]", true},
+		{"ссылка", "профиль vk.com/master", "профиль [контакты скрыты]", true},
+		{"телефон", "звоните +7 (921) 394-65-09", "звоните [контакты скрыты]", true},
+		{"телефон с восьмёрки", "мой номер 89213946509.", "мой номер [контакты скрыты].", true},
+		{"телефон группами", "8 921 394 65 09 после шести", "[контакты скрыты] после шести", true},
+		{"мобильный без префикса", "921-394-65-09", "[контакты скрыты]", true},
+		{"диапазон цен", "Бюджет 150 000 - 200 000 руб", "Бюджет 150 000 - 200 000 руб", false},
+		{"цена на девятку", "Готов за 900 000 - 950 000 руб", "Готов за 900 000 - 950 000 руб", false},
+		{"длинное число", "артикул 921394650912345", "артикул 921394650912345", false},
+		{"email", "почта master@mail.ru", "почта [контакты скрыты]", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, masked := MaskContacts(tt.text)
+			if got != tt.want || masked != tt.masked {
+				t.Errorf("MaskContacts(%q) = %q, %v; want %q, %v", tt.text, got, masked, tt.want, tt.masked)
+			}
+		})
+	}
+}
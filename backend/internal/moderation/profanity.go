@@ -0,0 +1,16 @@
+// internal/moderation/profanity.go
+package moderation
+
+import "regexp"
+
+// Корни нецензурных слов. Совпадение ищется только с начала слова,
+// иначе под фильтр попадают "употреблять", "хлеба" и т.п.
+var profanityPattern = regexp.MustCompile(`(?i)(^|[^\p{L}])(` +
+	`х[уy][йеёяю]|п[иі]зд|[её]б[аылун]|[её]бн|заеб|заёб|наеб|наёб|выеб|выёб|отъеб|разъеб|` +
+	`бля|бляд|сук[аи]|мудак|мудил|гандон|пидор|пидар|шлюх|долбо[её]б|` +
+	`fuck|shit|bitch|asshole|cunt)`)
+
+// HasProfanity сообщает, есть ли в тексте нецензурная лексика
+func HasProfanity(text string) bool {
+	return profanityPattern.MatchString(text)
+}
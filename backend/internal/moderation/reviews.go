@@ -0,0 +1,192 @@
+// internal/moderation/reviews.go
+package moderation
+
+import (
+	"time"
+
+	"refurnish/internal/models"
+	"refurnish/internal/ratings"
+
+	"gorm.io/gorm"
+)
+
+// Статусы модерации отзыва
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// Автоматические флаги. Отзыв хотя бы с одним флагом не публикуется,
+// пока его не проверит модератор. Автор - тот, кто пишет отзыв, адресат -
+// тот, о ком он.
+const (
+	FlagProfanity  = "profanity"   // нецензурная лексика
+	FlagContacts   = "contacts"    // телефоны, email, ссылки
+	FlagSameIP     = "same_ip"     // с этого IP адресату уже оставляли отзыв другие авторы
+	FlagNewAccount = "new_account" // аккаунт автора моложе NewAccountAge
+	FlagBurst      = "burst"       // адресат за сутки получил BurstLimit отзывов и больше
+)
+
+// Действия в журнале модерации
+const (
+	ActionFlagged        = "flagged"
+	ActionApproved       = "approved"
+	ActionRejected       = "rejected"
+	ActionEdited         = "edited"
+	ActionDisputeOpened  = "dispute_opened"
+	ActionDisputeAccept  = "dispute_accepted"
+	ActionDisputeDecline = "dispute_rejected"
+)
+
+const (
+	NewAccountAge = 7 * 24 * time.Hour
+	BurstLimit    = 5
+)
+
+// Kind - вид отзыва. Отзывы клиентов о мастерах и мастеров о клиентах
+// модерируются одинаково, споры и журнал у них общие и различаются по Name.
+type Kind struct {
+	Name        string // значение kind в review_disputes и moderation_actions
+	Table       string // таблица отзывов
+	Column      string // ссылка на отзыв в review_disputes и moderation_actions
+	Author      string // колонка автора отзыва
+	AuthorTable string // профили авторов
+	Subject     string // колонка адресата, его рейтинг пересчитывается
+}
+
+var (
+	// MasterReview - отзыв клиента о мастере
+	MasterReview = Kind{Name: "master", Table: "reviews", Column: "review_id",
+		Author: "client_id", AuthorTable: "clients", Subject: "master_id"}
+	// ClientReview - отзыв мастера о клиенте
+	ClientReview = Kind{Name: "client", Table: "client_reviews", Column: "client_review_id",
+		Author: "master_id", AuthorTable: "masters", Subject: "client_id"}
+)
+
+// KindByName - вид отзыва по значению kind
+func KindByName(name string) (Kind, bool) {
+	switch name {
+	case MasterReview.Name:
+		return MasterReview, true
+	case ClientReview.Name:
+		return ClientReview, true
+	}
+	return Kind{}, false
+}
+
+// Submission - новый отзыв, который проверяет Check
+type Submission struct {
+	Text      string
+	IP        *string
+	AuthorID  string
+	SubjectID string
+}
+
+// Check возвращает автоматические флаги для нового отзыва.
+// Отзыв ещё не сохранён: сравнение идёт с уже существующими отзывами.
+func Check(db *gorm.DB, kind Kind, review Submission) []string {
+	flags := []string{}
+
+	if HasProfanity(review.Text) {
+		flags = append(flags, FlagProfanity)
+	}
+	if HasContacts(review.Text) {
+		flags = append(flags, FlagContacts)
+	}
+
+	if review.IP != nil && *review.IP != "" {
+		var sameIP int64
+		db.Table(kind.Table).
+			Where(kind.Subject+" = ? AND ip = ? AND "+kind.Author+" <> ?", review.SubjectID, *review.IP, review.AuthorID).
+			Count(&sameIP)
+		if sameIP > 0 {
+			flags = append(flags, FlagSameIP)
+		}
+	}
+
+	var registered *time.Time
+	db.Raw(`SELECT u.created_at FROM users u JOIN `+kind.AuthorTable+` a ON a.user_id = u.id WHERE a.id = ?`,
+		review.AuthorID).Scan(&registered)
+	if registered != nil && time.Since(*registered) < NewAccountAge {
+		flags = append(flags, FlagNewAccount)
+	}
+
+	var recent int64
+	db.Table(kind.Table).
+		Where(kind.Subject+" = ? AND created_at > ?", review.SubjectID, time.Now().Add(-24*time.Hour)).
+		Count(&recent)
+	if recent+1 >= BurstLimit {
+		flags = append(flags, FlagBurst)
+	}
+
+	return flags
+}
+
+// Target - то, что модерации нужно знать об отзыве любого вида
+type Target struct {
+	ID               string
+	ProjectID        string
+	MasterID         string
+	ClientID         string
+	ModerationStatus string
+	Text             string
+}
+
+// Load загружает отзыв вида kind. where дополняет условие по id,
+// например проверкой адресата.
+func Load(db *gorm.DB, kind Kind, id string, where ...interface{}) (*Target, error) {
+	var target Target
+	query := db.Table(kind.Table).
+		Select("id, project_id, master_id, client_id, moderation_status, text").
+		Where("id = ?", id)
+	if len(where) > 0 {
+		query = query.Where(where[0], where[1:]...)
+	}
+	if err := query.Take(&target).Error; err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// Record добавляет запись в журнал модерации. actorID nil - решение
+// автоматической проверки.
+func Record(tx *gorm.DB, kind Kind, reviewID string, actorID *string, action, reason string, details map[string]interface{}) error {
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	entry := models.ModerationAction{
+		Kind:    kind.Name,
+		ActorID: actorID,
+		Action:  action,
+		Reason:  reason,
+		Details: details,
+	}
+	if kind == ClientReview {
+		entry.ClientReviewID = &reviewID
+	} else {
+		entry.ReviewID = &reviewID
+	}
+	return tx.Create(&entry).Error
+}
+
+// SetStatus меняет статус модерации отзыва, пишет журнал и пересчитывает
+// рейтинг адресата - учитываются только одобренные отзывы
+func SetStatus(tx *gorm.DB, kind Kind, review *Target, status string, actorID *string, action, reason string) error {
+	previous := review.ModerationStatus
+	if err := tx.Table(kind.Table).Where("id = ?", review.ID).Update("moderation_status", status).Error; err != nil {
+		return err
+	}
+	review.ModerationStatus = status
+
+	if err := Record(tx, kind, review.ID, actorID, action, reason, map[string]interface{}{
+		"from": previous,
+		"to":   status,
+	}); err != nil {
+		return err
+	}
+	if kind == ClientReview {
+		return ratings.RecalculateClient(tx, review.ClientID)
+	}
+	return ratings.Recalculate(tx, review.MasterID)
+}
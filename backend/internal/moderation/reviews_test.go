@@ -0,0 +1,87 @@
+package moderation
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"refurnish/internal/models"
+	"refurnish/internal/testdb"
+
+	"gorm.io/gorm"
+)
+
+// Оба вида отзывов проверяются и модерируются одним кодом: флаги считаются
+// по адресату и автору своего вида, решение пересчитывает рейтинг адресата
+func TestCheckAndRejectBothKinds(t *testing.T) {
+	db := testdb.Open(t)
+
+	clientID, _ := testdb.Client(t, db)
+	otherClientID, _ := testdb.Client(t, db)
+	masterID, _ := testdb.Master(t, db)
+	otherMasterID, _ := testdb.Master(t, db)
+	projectID := testdb.Project(t, db, clientID, map[string]interface{}{
+		"status":          "completed",
+		"assigned_master": masterID,
+		"completed_at":    time.Now(),
+	})
+	reviewID := testdb.Insert(t, db, `
+		INSERT INTO reviews (project_id, client_id, master_id, rating, ip, published_at)
+		VALUES (?, ?, ?, 5, '203.0.113.7', now()) RETURNING id
+	`, projectID, clientID, masterID)
+	clientReviewID := testdb.Insert(t, db, `
+		INSERT INTO client_reviews (project_id, master_id, client_id, rating, ip, published_at)
+		VALUES (?, ?, ?, 5, '203.0.113.7', now()) RETURNING id
+	`, projectID, masterID, clientID)
+
+	ip := "203.0.113.7"
+	cases := []struct {
+		kind       Kind
+		submission Submission
+		reviewID   string
+		count      string
+	}{
+		{MasterReview, Submission{Text: "Всё хорошо", IP: &ip, AuthorID: otherClientID, SubjectID: masterID},
+			reviewID, "SELECT reviews_count FROM masters WHERE id = '" + masterID + "'"},
+		{ClientReview, Submission{Text: "Всё хорошо", IP: &ip, AuthorID: otherMasterID, SubjectID: clientID},
+			clientReviewID, "SELECT reviews_count FROM clients WHERE id = '" + clientID + "'"},
+	}
+	admin := testdb.User(t, db, "admin")
+
+	for _, c := range cases {
+		t.Run(c.kind.Name, func(t *testing.T) {
+			flags := Check(db, c.kind, c.submission)
+			for _, want := range []string{FlagSameIP, FlagNewAccount} {
+				if !slices.Contains(flags, want) {
+					t.Errorf("flags = %v, want %s", flags, want)
+				}
+			}
+			if slices.Contains(flags, FlagContacts) || slices.Contains(flags, FlagProfanity) {
+				t.Errorf("flags = %v, text is clean", flags)
+			}
+
+			review, err := Load(db, c.kind, c.reviewID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := db.Transaction(func(tx *gorm.DB) error {
+				return SetStatus(tx, c.kind, review, StatusRejected, &admin, ActionRejected, "оскорбление")
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			var count int
+			db.Raw(c.count).Scan(&count)
+			if count != 0 {
+				t.Errorf("reviews_count = %d after rejection, want 0", count)
+			}
+			var logged int64
+			db.Model(&models.ModerationAction{}).
+				Where(c.kind.Column+" = ? AND kind = ? AND action = ?", c.reviewID, c.kind.Name, ActionRejected).
+				Count(&logged)
+			if logged != 1 {
+				t.Errorf("moderation log has %d rejections, want 1", logged)
+			}
+		})
+	}
+}
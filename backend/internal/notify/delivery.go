@@ -0,0 +1,170 @@
+// internal/notify/delivery.go
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Message - уведомление для внешнего канала
+type Message struct {
+	Kind  string
+	Title string
+	Body  string
+	Data  map[string]interface{} // те же данные, что в in-app уведомлении
+}
+
+// Sender доставляет уведомление во внешний канал
+type Sender interface {
+	// Address - адрес пользователя в канале (email, телефон, chat id).
+	// false - адреса нет, канал для пользователя пропускается.
+	Address(db *gorm.DB, userID string) (string, bool)
+	Deliver(ctx context.Context, address string, msg Message) error
+}
+
+// ErrPermanent - ошибка, после которой повторять доставку бессмысленно
+var ErrPermanent = errors.New("постоянная ошибка доставки")
+
+// MaxAttempts - после стольких неудачных попыток доставка помечается failed
+const MaxAttempts = 6
+
+var (
+	sendersMu sync.RWMutex
+	senders   = map[string]Sender{}
+)
+
+// Register подключает отправителя для канала
+func Register(channel string, s Sender) {
+	sendersMu.Lock()
+	senders[channel] = s
+	sendersMu.Unlock()
+	log.Printf("🔔 Канал уведомлений %s подключён", channel)
+}
+
+func senderFor(channel string) Sender {
+	sendersMu.RLock()
+	defer sendersMu.RUnlock()
+	return senders[channel]
+}
+
+// retryDelay - экспоненциальная задержка: 1, 2, 4, 8, 16 минут
+func retryDelay(attempts int) time.Duration {
+	return time.Minute << (attempts - 1)
+}
+
+func enqueue(db *gorm.DB, notificationID *string, userID, channel string, msg Message, data models.JSONB) error {
+	sender := senderFor(channel)
+	if sender == nil {
+		return nil
+	}
+	address, ok := sender.Address(db, userID)
+	if !ok {
+		return nil
+	}
+
+	delivery := models.NotificationDelivery{
+		NotificationID: notificationID,
+		UserID:         userID,
+		Type:           msg.Kind,
+		Channel:        channel,
+		Address:        address,
+		Title:          msg.Title,
+		Body:           msg.Body,
+		Data:           data,
+		Status:         "pending",
+		// Первая попытка сразу; если процесс упадёт, её подберёт фоновая задача
+		NextAttemptAt: time.Now().Add(time.Minute),
+	}
+	if err := db.Create(&delivery).Error; err != nil {
+		return err
+	}
+
+	go attempt(context.Background(), db, delivery)
+	return nil
+}
+
+// DeliverPending - фоновая задача: повторяет доставки, у которых
+// наступило время следующей попытки.
+func DeliverPending(db *gorm.DB) func(context.Context) error {
+	return func(ctx context.Context) error {
+		// Забираем пачку и сдвигаем next_attempt_at, чтобы другие
+		// экземпляры не взяли те же доставки
+		var due []models.NotificationDelivery
+		err := db.WithContext(ctx).Raw(`
+			UPDATE notification_deliveries
+			SET next_attempt_at = now() + interval '5 minutes'
+			WHERE id IN (
+				SELECT id FROM notification_deliveries
+				WHERE status = 'pending' AND next_attempt_at <= now()
+				ORDER BY next_attempt_at
+				LIMIT 100
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		`).Scan(&due).Error
+		if err != nil {
+			return err
+		}
+
+		for _, d := range due {
+			attempt(ctx, db, d)
+		}
+		return nil
+	}
+}
+
+func attempt(ctx context.Context, db *gorm.DB, d models.NotificationDelivery) {
+	sender := senderFor(d.Channel)
+	var err error
+	if sender == nil {
+		err = errors.New("канал не настроен")
+	} else {
+		msg := Message{Kind: d.Type, Title: d.Title, Body: d.Body}
+		json.Unmarshal(d.Data, &msg.Data)
+
+		sendCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		err = sender.Deliver(sendCtx, d.Address, msg)
+		cancel()
+	}
+
+	attempts := d.Attempts + 1
+	updates := map[string]interface{}{"attempts": attempts}
+	if err == nil {
+		updates["status"] = "sent"
+		updates["sent_at"] = time.Now()
+		updates["last_error"] = nil
+	} else {
+		updates["last_error"] = err.Error()
+		if attempts >= MaxAttempts || errors.Is(err, ErrPermanent) || sender == nil {
+			updates["status"] = "failed"
+			log.Printf("❌ Доставка %s (%s) не удалась окончательно: %v", d.ID, d.Channel, err)
+		} else {
+			updates["next_attempt_at"] = time.Now().Add(retryDelay(attempts))
+			log.Printf("⚠️  Доставка %s (%s), попытка %d: %v", d.ID, d.Channel, attempts, err)
+		}
+	}
+
+	if err := db.Model(&models.NotificationDelivery{}).Where("id = ? AND status = 'pending'", d.ID).Updates(updates).Error; err != nil {
+		log.Printf("❌ Не удалось обновить доставку %s: %v", d.ID, err)
+	}
+}
+
+// Retry возвращает доставку в очередь (например, из админки)
+func Retry(db *gorm.DB, deliveryID string) (bool, error) {
+	result := db.Model(&models.NotificationDelivery{}).
+		Where("id = ? AND status <> 'sent'", deliveryID).
+		Updates(map[string]interface{}{
+			"status":          "pending",
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,88 @@
+// internal/notify/email.go
+package notify
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"os"
+	"strings"
+
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// EmailSender отправляет письма через SMTP
+type EmailSender struct {
+	Addr     string // host:port
+	Host     string
+	User     string
+	Password string
+	From     string
+}
+
+// EmailFromEnv - SMTP_HOST, SMTP_PORT (587), SMTP_USER, SMTP_PASSWORD, SMTP_FROM.
+// Без SMTP_HOST канал не подключается.
+func EmailFromEnv() (*EmailSender, bool) {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return nil, false
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = "noreply@refurnish.ru"
+	}
+	return &EmailSender{
+		Addr:     host + ":" + port,
+		Host:     host,
+		User:     os.Getenv("SMTP_USER"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+	}, true
+}
+
+func (s *EmailSender) Address(db *gorm.DB, userID string) (string, bool) {
+	var user models.User
+	if err := db.Select("email").First(&user, "id = ?", userID).Error; err != nil || user.Email == "" {
+		return "", false
+	}
+	return user.Email, true
+}
+
+func (s *EmailSender) Deliver(ctx context.Context, address string, m Message) error {
+	if strings.ContainsAny(address, "\r\n") {
+		return fmt.Errorf("%w: неверный адрес", ErrPermanent)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + s.From,
+		"To: " + address,
+		"Subject: " + mime.QEncoding.Encode("utf-8", m.Title),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		m.Body,
+	}, "\r\n")
+
+	var auth smtp.Auth
+	if s.User != "" {
+		auth = smtp.PlainAuth("", s.User, s.Password, s.Host)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(s.Addr, auth, s.From, []string{address}, []byte(msg))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
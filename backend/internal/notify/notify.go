@@ -0,0 +1,98 @@
+// internal/notify/notify.go
+package notify
+
+import (
+	"encoding/json"
+	"log"
+
+	"refurnish/internal/models"
+	"refurnish/internal/realtime"
+
+	"gorm.io/gorm"
+)
+
+// Типы уведомлений
+const (
+	ResponseReceived   = "response_received"
+	MasterAssigned     = "master_assigned"
+	ProjectExpired     = "project_expired"
+	ProjectInvitation  = "project_invitation"
+	InvitationAnswered = "invitation_answered"
+	QuestionAsked      = "question_asked"
+	QuestionAnswered   = "question_answered"
+	NewProjectAlert    = "new_project_alert"
+	NewProjectsDigest  = "new_projects_digest"
+	MessageReceived    = "message_received"
+	ReviewReceived     = "review_received"
+	// ClientReviewReceived - мастер оставил отзыв о клиенте
+	ClientReviewReceived = "client_review_received"
+	// DisputeResolved - модератор рассмотрел спор мастера или клиента по отзыву
+	DisputeResolved = "dispute_resolved"
+	// VerificationStatus - заявка на проверку одобрена, отклонена или отметка снята
+	VerificationStatus = "verification_status"
+	// Выезд на замер: предложено время, подтверждён, перенесён, отменён, скоро
+	AppointmentProposed    = "appointment_proposed"
+	AppointmentConfirmed   = "appointment_confirmed"
+	AppointmentRescheduled = "appointment_rescheduled"
+	AppointmentCancelled   = "appointment_cancelled"
+	AppointmentReminder    = "appointment_reminder"
+)
+
+// Send рассылает уведомление по включённым у пользователя каналам.
+// Заголовок и текст берутся из шаблона типа kind на языке пользователя,
+// data подставляется в шаблон и сохраняется вместе с уведомлением.
+func Send(db *gorm.DB, userID, kind string, data map[string]interface{}) error {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	var user models.User
+	if err := db.Select("id", "language").First(&user, "id = ?", userID).Error; err != nil {
+		return err
+	}
+	title, body, err := Render(kind, user.Language, data)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	channels := EnabledChannels(db, userID, kind)
+
+	var notificationID *string
+	if channels[InApp] {
+		notification := models.Notification{
+			UserID: userID,
+			Type:   kind,
+			Title:  title,
+			Body:   body,
+			Data:   models.JSONB(raw),
+		}
+		if err := db.Create(&notification).Error; err != nil {
+			return err
+		}
+		notificationID = &notification.ID
+
+		realtime.Publish(userID, realtime.NotificationCreated, map[string]interface{}{
+			"id":    notification.ID,
+			"kind":  kind,
+			"title": title,
+		})
+	}
+
+	for channel := range channels {
+		if channel == InApp {
+			continue
+		}
+		msg := Message{Kind: kind, Title: title, Body: body, Data: data}
+		if err := enqueue(db, notificationID, userID, channel, msg, models.JSONB(raw)); err != nil {
+			log.Printf("⚠️  Не удалось поставить уведомление %s в канал %s: %v", kind, channel, err)
+		}
+	}
+
+	log.Printf("🔔 Уведомление %s для user_id=%s", kind, userID)
+	return nil
+}
@@ -0,0 +1,129 @@
+// internal/notify/preferences.go
+package notify
+
+import (
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Каналы доставки
+const (
+	InApp    = "in_app"
+	Email    = "email"
+	SMS      = "sms"
+	Telegram = "telegram"
+)
+
+// Channels - все каналы в порядке отображения в настройках
+var Channels = []string{InApp, Email, SMS, Telegram}
+
+// Kinds - типы уведомлений в порядке отображения в настройках
+var Kinds = []string{
+	ResponseReceived, MasterAssigned, ProjectExpired,
+	ProjectInvitation, InvitationAnswered,
+	QuestionAsked, QuestionAnswered,
+	NewProjectAlert, NewProjectsDigest,
+	MessageReceived,
+	ReviewReceived, ClientReviewReceived, DisputeResolved,
+	VerificationStatus,
+	AppointmentProposed, AppointmentConfirmed, AppointmentRescheduled, AppointmentCancelled,
+	AppointmentReminder,
+}
+
+// defaults - каналы, включённые по умолчанию помимо in-app.
+// SMS платные, поэтому только для назначения исполнителем.
+var defaults = map[string][]string{
+	ResponseReceived:       {Email, Telegram},
+	MasterAssigned:         {Email, SMS, Telegram},
+	ProjectExpired:         {Email},
+	ProjectInvitation:      {Email, Telegram},
+	InvitationAnswered:     {Telegram},
+	QuestionAsked:          {Telegram},
+	QuestionAnswered:       {Telegram},
+	NewProjectAlert:        {Telegram},
+	NewProjectsDigest:      {Email},
+	MessageReceived:        {Telegram},
+	ReviewReceived:         {Email, Telegram},
+	ClientReviewReceived:   {Email},
+	DisputeResolved:        {Email},
+	VerificationStatus:     {Email, Telegram},
+	AppointmentProposed:    {Email, Telegram},
+	AppointmentConfirmed:   {Email, Telegram},
+	AppointmentRescheduled: {Email, Telegram},
+	AppointmentCancelled:   {Email, Telegram},
+	AppointmentReminder:    {Telegram},
+}
+
+// IsKind / IsChannel - проверка значений из запросов
+func IsKind(kind string) bool {
+	_, ok := templates[kind]
+	return ok
+}
+
+func IsChannel(channel string) bool {
+	for _, c := range Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultEnabled - значение канала без пользовательской настройки
+func DefaultEnabled(kind, channel string) bool {
+	if channel == InApp {
+		return true
+	}
+	for _, c := range defaults[kind] {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// Preferences - итоговая матрица тип -> канал -> включён
+func Preferences(db *gorm.DB, userID string) map[string]map[string]bool {
+	result := make(map[string]map[string]bool, len(Kinds))
+	for _, kind := range Kinds {
+		result[kind] = make(map[string]bool, len(Channels))
+		for _, channel := range Channels {
+			result[kind][channel] = DefaultEnabled(kind, channel)
+		}
+	}
+
+	var overrides []models.NotificationPreference
+	db.Where("user_id = ?", userID).Find(&overrides)
+	for _, p := range overrides {
+		if result[p.Type] != nil {
+			result[p.Type][p.Channel] = p.Enabled
+		}
+	}
+	return result
+}
+
+// EnabledChannels - включённые каналы для уведомления. Внешние каналы
+// учитываются, только если для них настроен отправитель.
+func EnabledChannels(db *gorm.DB, userID, kind string) map[string]bool {
+	enabled := make(map[string]bool)
+	for _, channel := range Channels {
+		if channel != InApp && senderFor(channel) == nil {
+			continue
+		}
+		if DefaultEnabled(kind, channel) {
+			enabled[channel] = true
+		}
+	}
+
+	var overrides []models.NotificationPreference
+	db.Where("user_id = ? AND type = ?", userID, kind).Find(&overrides)
+	for _, p := range overrides {
+		if !p.Enabled {
+			delete(enabled, p.Channel)
+		} else if p.Channel == InApp || senderFor(p.Channel) != nil {
+			enabled[p.Channel] = true
+		}
+	}
+	return enabled
+}
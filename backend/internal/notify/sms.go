@@ -0,0 +1,73 @@
+// internal/notify/sms.go
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SMSSender отправляет SMS через HTTP-шлюз провайдера:
+// POST {"phone": "...", "text": "..."} с заголовком Authorization: Bearer <key>
+type SMSSender struct {
+	URL    string
+	APIKey string
+	Client *http.Client
+}
+
+// SMSFromEnv - SMS_GATEWAY_URL и SMS_API_KEY. Без URL канал не подключается.
+func SMSFromEnv() (*SMSSender, bool) {
+	url := os.Getenv("SMS_GATEWAY_URL")
+	if url == "" {
+		return nil, false
+	}
+	return &SMSSender{URL: url, APIKey: os.Getenv("SMS_API_KEY"), Client: http.DefaultClient}, true
+}
+
+// Address - телефон из профиля клиента (у мастеров телефона в профиле нет)
+func (s *SMSSender) Address(db *gorm.DB, userID string) (string, bool) {
+	var phone string
+	db.Raw("SELECT COALESCE(phone, '') FROM clients WHERE user_id = ? LIMIT 1", userID).Scan(&phone)
+	phone = strings.TrimSpace(phone)
+	return phone, phone != ""
+}
+
+func (s *SMSSender) Deliver(ctx context.Context, address string, m Message) error {
+	payload, err := json.Marshal(map[string]string{
+		"phone": address,
+		"text":  m.Title + ". " + m.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.APIKey)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests:
+		return fmt.Errorf("%w: шлюз ответил %d", ErrPermanent, resp.StatusCode)
+	default:
+		return fmt.Errorf("шлюз ответил %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,127 @@
+// internal/notify/templates.go
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+type messageTemplate struct {
+	Title string
+	Body  string
+}
+
+// templates - шаблоны по типу и языку. Поля data подставляются как {{.projectTitle}}
+var templates = map[string]map[string]messageTemplate{
+	ResponseReceived: {
+		"ru": {"Новый отклик на проект", "{{.masterName}} откликнулся на проект «{{.projectTitle}}»{{if .price}} — {{.price}} ₽{{end}}."},
+		"en": {"New response to your project", "{{.masterName}} responded to «{{.projectTitle}}»{{if .price}} — {{.price}} RUB{{end}}."},
+	},
+	MasterAssigned: {
+		"ru": {"Вас выбрали исполнителем", "Клиент выбрал вас для проекта «{{.projectTitle}}»."},
+		"en": {"You have been assigned", "The client chose you for «{{.projectTitle}}»."},
+	},
+	ProjectExpired: {
+		"ru": {"Срок публикации проекта истёк", "Проект «{{.projectTitle}}» снят с публикации. Продлите срок, чтобы снова получать отклики."},
+		"en": {"Your project listing expired", "«{{.projectTitle}}» is no longer listed. Extend it to keep receiving responses."},
+	},
+	ProjectInvitation: {
+		"ru": {"Вас пригласили оценить проект", "Клиент приглашает вас оценить проект «{{.projectTitle}}»."},
+		"en": {"You are invited to quote", "A client invites you to quote «{{.projectTitle}}»."},
+	},
+	InvitationAnswered: {
+		"ru": {"{{if eq .status \"accepted\"}}Мастер принял приглашение{{else}}Мастер отклонил приглашение{{end}}", "{{.masterName}} — проект «{{.projectTitle}}»."},
+		"en": {"{{if eq .status \"accepted\"}}Invitation accepted{{else}}Invitation declined{{end}}", "{{.masterName}} — «{{.projectTitle}}»."},
+	},
+	QuestionAsked: {
+		"ru": {"Новый вопрос по проекту", "По проекту «{{.projectTitle}}» задан вопрос: {{.question}}"},
+		"en": {"New question about your project", "Question about «{{.projectTitle}}»: {{.question}}"},
+	},
+	QuestionAnswered: {
+		"ru": {"Клиент ответил на ваш вопрос", "Проект «{{.projectTitle}}»: {{.answer}}"},
+		"en": {"The client answered your question", "«{{.projectTitle}}»: {{.answer}}"},
+	},
+	NewProjectAlert: {
+		"ru": {"Новый проект по вашему поиску", "«{{.projectTitle}}» — {{.city}}, бюджет {{.budget}} ₽ (поиск «{{.searchName}}»){{if .unsubscribeUrl}}\nОтписаться: {{.unsubscribeUrl}}{{end}}"},
+		"en": {"New project for your search", "«{{.projectTitle}}» — {{.city}}, budget {{.budget}} RUB (search «{{.searchName}}»){{if .unsubscribeUrl}}\nUnsubscribe: {{.unsubscribeUrl}}{{end}}"},
+	},
+	NewProjectsDigest: {
+		"ru": {"Новые проекты за сутки", "По поиску «{{.searchName}}» найдено новых проектов: {{.count}}{{if .unsubscribeUrl}}\nОтписаться: {{.unsubscribeUrl}}{{end}}"},
+		"en": {"New projects today", "New projects for «{{.searchName}}»: {{.count}}{{if .unsubscribeUrl}}\nUnsubscribe: {{.unsubscribeUrl}}{{end}}"},
+	},
+	ReviewReceived: {
+		"ru": {"Новый отзыв", "Клиент оставил отзыв по проекту «{{.projectTitle}}». Оставьте отзыв о клиенте, чтобы увидеть оба."},
+		"en": {"New review", "The client reviewed your work on «{{.projectTitle}}». Review the client to see both reviews."},
+	},
+	ClientReviewReceived: {
+		"ru": {"Мастер оставил отзыв", "Мастер оставил отзыв по проекту «{{.projectTitle}}». Оцените его работу, чтобы увидеть оба отзыва."},
+		"en": {"The master left a review", "The master reviewed «{{.projectTitle}}». Rate their work to see both reviews."},
+	},
+	DisputeResolved: {
+		"ru": {"Спор по отзыву рассмотрен", "{{if .accepted}}Отзыв по проекту «{{.projectTitle}}» снят с публикации.{{else}}Отзыв по проекту «{{.projectTitle}}» оставлен без изменений.{{end}} {{.resolution}}"},
+		"en": {"Review dispute resolved", "{{if .accepted}}The review on «{{.projectTitle}}» has been removed.{{else}}The review on «{{.projectTitle}}» stays published.{{end}} {{.resolution}}"},
+	},
+	VerificationStatus: {
+		"ru": {"Проверка профиля", "{{if eq .status \"approved\"}}Профиль проверен, в каталоге появилась отметка «Проверен».{{else if eq .status \"rejected\"}}Заявка на проверку отклонена: {{.reason}}{{else}}Отметка «Проверен» снята после изменения профиля. Подайте заявку заново.{{end}}"},
+		"en": {"Profile verification", "{{if eq .status \"approved\"}}Your profile is verified and now shows the «Verified» badge.{{else if eq .status \"rejected\"}}Verification request rejected: {{.reason}}{{else}}The «Verified» badge was removed after a profile change. Please submit a new request.{{end}}"},
+	},
+	AppointmentProposed: {
+		"ru": {"Мастер предлагает время замера", "{{.masterName}} предлагает приехать на замер по проекту «{{.projectTitle}}». Выберите удобное время: {{.slots}}"},
+		"en": {"The master suggests a visit time", "{{.masterName}} offers to visit for measurements on «{{.projectTitle}}». Pick a convenient time: {{.slots}}"},
+	},
+	AppointmentConfirmed: {
+		"ru": {"Замер назначен", "Замер по проекту «{{.projectTitle}}» назначен на {{.when}}.{{if .address}} Адрес: {{.address}}.{{end}}"},
+		"en": {"Visit confirmed", "The measurement visit for «{{.projectTitle}}» is scheduled for {{.when}}.{{if .address}} Address: {{.address}}.{{end}}"},
+	},
+	AppointmentRescheduled: {
+		"ru": {"Замер перенесён", "{{if .when}}Замер по проекту «{{.projectTitle}}» перенесён на {{.when}}.{{else}}Замер по проекту «{{.projectTitle}}» нужно назначить заново.{{if .slots}} Варианты времени: {{.slots}}.{{end}}{{end}} Причина: {{.reason}}"},
+		"en": {"Visit rescheduled", "{{if .when}}The visit for «{{.projectTitle}}» has been moved to {{.when}}.{{else}}The visit for «{{.projectTitle}}» needs a new time.{{if .slots}} Available times: {{.slots}}.{{end}}{{end}} Reason: {{.reason}}"},
+	},
+	AppointmentCancelled: {
+		"ru": {"Замер отменён", "Замер по проекту «{{.projectTitle}}» отменён. Причина: {{.reason}}"},
+		"en": {"Visit cancelled", "The visit for «{{.projectTitle}}» has been cancelled. Reason: {{.reason}}"},
+	},
+	AppointmentReminder: {
+		"ru": {"Напоминание о замере", "Замер по проекту «{{.projectTitle}}» - {{.when}}.{{if .address}} Адрес: {{.address}}.{{end}}"},
+		"en": {"Visit reminder", "The measurement visit for «{{.projectTitle}}» is at {{.when}}.{{if .address}} Address: {{.address}}.{{end}}"},
+	},
+	MessageReceived: {
+		"ru": {"Новое сообщение", "{{.senderName}}: {{.preview}}"},
+		"en": {"New message", "{{.senderName}}: {{.preview}}"},
+	},
+}
+
+// Render подставляет data в шаблон типа kind; неизвестный язык - русский
+func Render(kind, lang string, data map[string]interface{}) (string, string, error) {
+	byLang, ok := templates[kind]
+	if !ok {
+		return "", "", fmt.Errorf("нет шаблона для уведомления %s", kind)
+	}
+	tpl, ok := byLang[lang]
+	if !ok {
+		tpl = byLang["ru"]
+	}
+
+	title, err := execute(kind+".title", tpl.Title, data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := execute(kind+".body", tpl.Body, data)
+	if err != nil {
+		return "", "", err
+	}
+	return title, body, nil
+}
+
+func execute(name, text string, data map[string]interface{}) (string, error) {
+	t, err := template.New(name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(sb.String(), "<no value>", ""), nil
+}
@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+)
+
+// Все шаблоны разбираются и на обоих языках подставляют данные
+func TestRenderAllTemplates(t *testing.T) {
+	for kind := range templates {
+		for _, lang := range []string{"ru", "en", "de"} {
+			title, _, err := Render(kind, lang, map[string]interface{}{"projectTitle": "Кухня"})
+			if err != nil {
+				t.Errorf("%s/%s: %v", kind, lang, err)
+			}
+			if title == "" {
+				t.Errorf("%s/%s: пустой заголовок", kind, lang)
+			}
+		}
+	}
+}
+
+// Оповещения по сохранённым поискам несут ссылку на отписку
+func TestAlertsCarryUnsubscribeLink(t *testing.T) {
+	link := "https://refurnish.example/unsubscribe?token=abc"
+	for _, kind := range []string{NewProjectAlert, NewProjectsDigest} {
+		for _, lang := range []string{"ru", "en"} {
+			_, body, err := Render(kind, lang, map[string]interface{}{
+				"projectTitle":   "Кухня",
+				"searchName":     "Кухни в Москве",
+				"count":          2,
+				"unsubscribeUrl": link,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(body, link) {
+				t.Errorf("%s/%s: нет ссылки на отписку в %q", kind, lang, body)
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// internal/portfolio/portfolio.go
+package portfolio
+
+import (
+	"errors"
+	"log"
+
+	"refurnish/internal/models"
+	"refurnish/internal/uploads"
+
+	"gorm.io/gorm"
+)
+
+// Фото "до" и "после"
+const (
+	Before = "before"
+	After  = "after"
+)
+
+var (
+	ErrNotCompleted = errors.New("проект ещё не завершён")
+	ErrNoConsent    = errors.New("клиент не разрешил показывать проект в портфолио")
+)
+
+// FromProject создаёт работу в портфолио назначенного мастера из
+// завершённого проекта: название, категория, описание, цена отклика,
+// дата завершения и фото клиента как "до". Повторный вызов возвращает
+// уже созданную работу.
+func FromProject(db *gorm.DB, projectID string) (*models.PortfolioItem, error) {
+	var project models.Project
+	if err := db.First(&project, "id = ?", projectID).Error; err != nil {
+		return nil, err
+	}
+	if project.Status != "completed" || project.MasterID == nil {
+		return nil, ErrNotCompleted
+	}
+	if !project.PortfolioConsent {
+		return nil, ErrNoConsent
+	}
+
+	var existing models.PortfolioItem
+	if err := db.Where("project_id = ?", project.ID).First(&existing).Error; err == nil {
+		return &existing, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	item := models.PortfolioItem{
+		MasterID:    *project.MasterID,
+		Title:       project.Title,
+		CategoryID:  project.CategoryID,
+		Description: project.Description,
+		CompletedOn: project.CompletedAt,
+		ProjectID:   &project.ID,
+	}
+	var response models.Response
+	if err := db.Where("project_id = ? AND master_id = ?", project.ID, *project.MasterID).
+		First(&response).Error; err == nil && response.Price > 0 {
+		item.Price = &response.Price
+	} else if project.Budget > 0 {
+		item.Price = &project.Budget
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&item).Error; err != nil {
+			return err
+		}
+		var photos []models.ProjectPhoto
+		if err := tx.Where("project_id = ?", project.ID).Order("sort_order, created_at").Find(&photos).Error; err != nil {
+			return err
+		}
+		for i, p := range photos {
+			photo := models.PortfolioPhoto{ItemID: item.ID, URL: p.URL, Kind: Before, SortOrder: i}
+			if err := tx.Create(&photo).Error; err != nil {
+				return err
+			}
+			item.Photos = append(item.Photos, photo)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("🖼️ Проект %s добавлен в портфолио мастера %s", project.ID, item.MasterID)
+	return &item, nil
+}
+
+// RemoveProject удаляет работу, созданную из проекта, - клиент отозвал согласие
+func RemoveProject(db *gorm.DB, projectID string) error {
+	var item models.PortfolioItem
+	if err := db.Where("project_id = ?", projectID).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return Delete(db, &item)
+}
+
+// Delete удаляет работу вместе с файлами фотографий
+func Delete(db *gorm.DB, item *models.PortfolioItem) error {
+	var photos []models.PortfolioPhoto
+	db.Where("item_id = ?", item.ID).Find(&photos)
+
+	if err := db.Delete(item).Error; err != nil {
+		return err
+	}
+	for _, p := range photos {
+		RemoveFile(db, p.URL)
+	}
+	return nil
+}
+
+// RemoveFile удаляет файл фото, если он не принадлежит проекту:
+// работы из проектов ссылаются на фотографии клиента
+func RemoveFile(db *gorm.DB, url string) {
+	var shared int64
+	db.Model(&models.ProjectPhoto{}).Where("url = ?", url).Count(&shared)
+	if shared > 0 {
+		return
+	}
+	if err := uploads.Remove(url); err != nil {
+		log.Printf("⚠️  Не удалось удалить файл %s: %v", url, err)
+	}
+}
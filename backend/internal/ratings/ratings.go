@@ -0,0 +1,128 @@
+// internal/ratings/ratings.go
+package ratings
+
+import (
+	"context"
+	"log"
+	"math"
+
+	"gorm.io/gorm"
+)
+
+// Рейтинг мастера - байесовское среднее: к его отзывам добавляется
+// PriorWeight "виртуальных" отзывов со средней оценкой по площадке.
+// Один отзыв на 5 звёзд даёт около 4.3, а не 5, и новичок не обгоняет
+// мастера с сотней отзывов в среднем 4.8.
+const (
+	PriorWeight = 5.0
+	// DefaultPrior - средняя оценка, пока отзывов на площадке нет
+	DefaultPrior = 4.0
+)
+
+// Bayesian - (C*m + сумма оценок) / (C + n), округлённое до сотых
+func Bayesian(sum float64, count int, prior float64) float64 {
+	if count == 0 {
+		return 0
+	}
+	value := (PriorWeight*prior + sum) / (PriorWeight + float64(count))
+	return math.Round(value*100) / 100
+}
+
+// VisibleReview - условие SQL, при котором отзыв о мастере или о клиенте
+// показывается публично и учитывается в рейтинге: открыт и одобрен модерацией
+const VisibleReview = "published_at IS NOT NULL AND moderation_status = 'approved'"
+
+// Prior - средняя оценка по всем видимым отзывам о мастерах
+func Prior(db *gorm.DB) float64 {
+	return prior(db, "reviews", VisibleReview)
+}
+
+// ClientPrior - средняя оценка по всем видимым отзывам о клиентах
+func ClientPrior(db *gorm.DB) float64 {
+	return prior(db, "client_reviews", VisibleReview)
+}
+
+func prior(db *gorm.DB, table, visible string) float64 {
+	var avg *float64
+	db.Raw("SELECT AVG(rating)::float8 FROM " + table + " WHERE " + visible).Scan(&avg)
+	if avg == nil {
+		return DefaultPrior
+	}
+	return *avg
+}
+
+// Recalculate пересчитывает рейтинг и число отзывов мастера
+func Recalculate(db *gorm.DB, masterID string) error {
+	var stats struct {
+		Sum   float64
+		Count int
+	}
+	err := db.Raw(`
+		SELECT COALESCE(SUM(rating), 0)::float8 AS sum, COUNT(*) AS count
+		FROM reviews WHERE master_id = ? AND `+VisibleReview, masterID).Scan(&stats).Error
+	if err != nil {
+		return err
+	}
+
+	return db.Exec("UPDATE masters SET rating = ?, reviews_count = ? WHERE id = ?",
+		Bayesian(stats.Sum, stats.Count, Prior(db)), stats.Count, masterID).Error
+}
+
+// RecalculateClient пересчитывает репутацию и число отзывов клиента
+func RecalculateClient(db *gorm.DB, clientID string) error {
+	var stats struct {
+		Sum   float64
+		Count int
+	}
+	err := db.Raw(`
+		SELECT COALESCE(SUM(rating), 0)::float8 AS sum, COUNT(*) AS count
+		FROM client_reviews WHERE client_id = ? AND `+VisibleReview, clientID).Scan(&stats).Error
+	if err != nil {
+		return err
+	}
+
+	return db.Exec("UPDATE clients SET reputation = ?, reviews_count = ? WHERE id = ?",
+		Bayesian(stats.Sum, stats.Count, ClientPrior(db)), stats.Count, clientID).Error
+}
+
+// RecalculateAll - фоновая задача: средняя по площадке меняется,
+// поэтому рейтинги всех мастеров периодически пересчитываются
+func RecalculateAll(db *gorm.DB) func(context.Context) error {
+	return func(ctx context.Context) error {
+		prior := Prior(db)
+		result := db.WithContext(ctx).Exec(`
+			UPDATE masters m SET
+				reviews_count = COALESCE(s.count, 0),
+				rating = CASE WHEN COALESCE(s.count, 0) = 0 THEN 0
+					ELSE ROUND(((? * ? + s.sum) / (? + s.count))::numeric, 2)::float8 END
+			FROM masters m2
+			LEFT JOIN (
+				SELECT master_id, SUM(rating)::float8 AS sum, COUNT(*) AS count
+				FROM reviews WHERE `+VisibleReview+` GROUP BY master_id
+			) s ON s.master_id = m2.id
+			WHERE m.id = m2.id
+		`, PriorWeight, prior, PriorWeight)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		clientPrior := ClientPrior(db)
+		result = db.WithContext(ctx).Exec(`
+			UPDATE clients c SET
+				reviews_count = COALESCE(s.count, 0),
+				reputation = CASE WHEN COALESCE(s.count, 0) = 0 THEN 0
+					ELSE ROUND(((? * ? + s.sum) / (? + s.count))::numeric, 2)::float8 END
+			FROM clients c2
+			LEFT JOIN (
+				SELECT client_id, SUM(rating)::float8 AS sum, COUNT(*) AS count
+				FROM client_reviews WHERE `+VisibleReview+` GROUP BY client_id
+			) s ON s.client_id = c2.id
+			WHERE c.id = c2.id
+		`, PriorWeight, clientPrior, PriorWeight)
+		if result.Error != nil {
+			return result.Error
+		}
+		log.Printf("⭐ Рейтинги пересчитаны (средняя по площадке %.2f, по клиентам %.2f)", prior, clientPrior)
+		return nil
+	}
+}
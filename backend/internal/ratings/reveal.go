@@ -0,0 +1,108 @@
+// internal/ratings/reveal.go
+package ratings
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Отзывы клиента о мастере и мастера о клиенте скрыты, пока не оставлены
+// оба - так ни одна сторона не может ответить на оценку местью. Если
+// второй отзыв так и не появился, оставленный открывается по истечении
+// окна после завершения проекта (RevealExpired). После этого новые отзывы
+// по проекту не принимаются (WindowClosed): первый уже открыт, и второй
+// был бы написан с оглядкой на него.
+
+// WindowClosed - окно для отзывов по проекту, завершённому в completedAt, истекло
+func WindowClosed(completedAt *time.Time, window time.Duration) bool {
+	return completedAt != nil && time.Since(*completedAt) > window
+}
+
+// Reveal открывает отзывы по проекту, если оставлены оба или окно window
+// после завершения уже истекло, и пересчитывает рейтинг мастера и
+// репутацию клиента. Возвращает true, если отзывы открыты.
+func Reveal(tx *gorm.DB, projectID string, window time.Duration) (bool, error) {
+	// Блокировка проекта сериализует одновременные отзывы обеих сторон:
+	// иначе каждая транзакция видит только свой отзыв и оба остаются скрытыми
+	var completedAt *time.Time
+	if err := tx.Raw("SELECT completed_at FROM projects WHERE id = ? FOR UPDATE", projectID).
+		Scan(&completedAt).Error; err != nil {
+		return false, err
+	}
+
+	var state struct {
+		Reviews       int
+		ClientReviews int
+	}
+	err := tx.Raw(`
+		SELECT (SELECT COUNT(*) FROM reviews WHERE project_id = ?) AS reviews,
+		       (SELECT COUNT(*) FROM client_reviews WHERE project_id = ?) AS client_reviews
+	`, projectID, projectID).Scan(&state).Error
+	if err != nil {
+		return false, err
+	}
+	if !WindowClosed(completedAt, window) && (state.Reviews == 0 || state.ClientReviews == 0) {
+		return false, nil
+	}
+	return true, publish(tx, projectID)
+}
+
+// publish открывает все скрытые отзывы по проекту
+func publish(tx *gorm.DB, projectID string) error {
+	now := time.Now()
+	if err := tx.Exec("UPDATE reviews SET published_at = ? WHERE project_id = ? AND published_at IS NULL",
+		now, projectID).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("UPDATE client_reviews SET published_at = ? WHERE project_id = ? AND published_at IS NULL",
+		now, projectID).Error; err != nil {
+		return err
+	}
+
+	var ids struct {
+		MasterID *string
+		ClientID string
+	}
+	if err := tx.Raw("SELECT assigned_master AS master_id, client_id FROM projects WHERE id = ?", projectID).Scan(&ids).Error; err != nil {
+		return err
+	}
+	if ids.MasterID != nil {
+		if err := Recalculate(tx, *ids.MasterID); err != nil {
+			return err
+		}
+	}
+	return RecalculateClient(tx, ids.ClientID)
+}
+
+// RevealExpired - фоновая задача: открывает отзывы по проектам, завершённым
+// больше window назад, даже если вторая сторона отзыв не оставила
+func RevealExpired(db *gorm.DB, window time.Duration) func(context.Context) error {
+	return func(ctx context.Context) error {
+		var projectIDs []string
+		err := db.WithContext(ctx).Raw(`
+			SELECT p.id FROM projects p
+			WHERE p.completed_at < ? AND (
+				EXISTS (SELECT 1 FROM reviews r WHERE r.project_id = p.id AND r.published_at IS NULL) OR
+				EXISTS (SELECT 1 FROM client_reviews c WHERE c.project_id = p.id AND c.published_at IS NULL)
+			)
+		`, time.Now().Add(-window)).Scan(&projectIDs).Error
+		if err != nil {
+			return err
+		}
+
+		for _, id := range projectIDs {
+			if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				return publish(tx, id)
+			}); err != nil {
+				return err
+			}
+		}
+		if len(projectIDs) > 0 {
+			log.Printf("⭐ Открыты отзывы по %d проектам после окончания срока", len(projectIDs))
+		}
+		return nil
+	}
+}
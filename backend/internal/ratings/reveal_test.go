@@ -0,0 +1,183 @@
+package ratings
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"refurnish/internal/testdb"
+
+	"gorm.io/gorm"
+)
+
+const testWindow = 14 * 24 * time.Hour
+
+type revealFixture struct {
+	projectID, clientID, masterID string
+}
+
+// completedProject - проект с назначенным мастером, завершённый completedAgo назад
+func completedProject(t *testing.T, db *gorm.DB, completedAgo time.Duration) revealFixture {
+	t.Helper()
+	var f revealFixture
+	f.clientID, _ = testdb.Client(t, db)
+	f.masterID, _ = testdb.Master(t, db)
+	f.projectID = testdb.Project(t, db, f.clientID, map[string]interface{}{
+		"status":          "completed",
+		"assigned_master": f.masterID,
+		"completed_at":    time.Now().Add(-completedAgo),
+	})
+	return f
+}
+
+func (f revealFixture) addReview(t *testing.T, db *gorm.DB, rating int) {
+	t.Helper()
+	if err := db.Exec("INSERT INTO reviews (project_id, client_id, master_id, rating) VALUES (?, ?, ?, ?)",
+		f.projectID, f.clientID, f.masterID, rating).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (f revealFixture) addClientReview(t *testing.T, db *gorm.DB, rating int) {
+	t.Helper()
+	if err := db.Exec("INSERT INTO client_reviews (project_id, master_id, client_id, rating) VALUES (?, ?, ?, ?)",
+		f.projectID, f.masterID, f.clientID, rating).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+type revealState struct {
+	ReviewPublished       bool
+	ClientReviewPublished bool
+	MasterReviews         int
+	MasterRating          float64
+	ClientReviews         int
+	ClientReputation      float64
+}
+
+func (f revealFixture) state(t *testing.T, db *gorm.DB) revealState {
+	t.Helper()
+	var s revealState
+	if err := db.Raw(`
+		SELECT EXISTS (SELECT 1 FROM reviews WHERE project_id = ? AND published_at IS NOT NULL) AS review_published,
+		       EXISTS (SELECT 1 FROM client_reviews WHERE project_id = ? AND published_at IS NOT NULL) AS client_review_published,
+		       (SELECT reviews_count FROM masters WHERE id = ?) AS master_reviews,
+		       (SELECT rating FROM masters WHERE id = ?) AS master_rating,
+		       (SELECT reviews_count FROM clients WHERE id = ?) AS client_reviews,
+		       (SELECT reputation FROM clients WHERE id = ?) AS client_reputation
+	`, f.projectID, f.projectID, f.masterID, f.masterID, f.clientID, f.clientID).Scan(&s).Error; err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestRevealAfterBothReviews(t *testing.T) {
+	db := testdb.Open(t)
+	f := completedProject(t, db, time.Hour)
+
+	f.addReview(t, db, 5)
+	published, err := Reveal(db, f.projectID, testWindow)
+	if err != nil || published {
+		t.Fatalf("Reveal с одним отзывом = %v, %v; want false", published, err)
+	}
+	if s := f.state(t, db); s.ReviewPublished || s.MasterReviews != 0 {
+		t.Fatalf("отзыв открыт раньше второго: %+v", s)
+	}
+
+	f.addClientReview(t, db, 4)
+	published, err = Reveal(db, f.projectID, testWindow)
+	if err != nil || !published {
+		t.Fatalf("Reveal с двумя отзывами = %v, %v; want true", published, err)
+	}
+
+	s := f.state(t, db)
+	if !s.ReviewPublished || !s.ClientReviewPublished {
+		t.Errorf("отзывы не открыты: %+v", s)
+	}
+	if s.MasterReviews != 1 || s.MasterRating <= 0 {
+		t.Errorf("рейтинг мастера не пересчитан: %+v", s)
+	}
+	if s.ClientReviews != 1 || s.ClientReputation <= 0 {
+		t.Errorf("репутация клиента не пересчитана: %+v", s)
+	}
+}
+
+func TestRevealExpiredWindow(t *testing.T) {
+	db := testdb.Open(t)
+	f := completedProject(t, db, testWindow+time.Hour)
+	f.addReview(t, db, 5)
+
+	if err := RevealExpired(db, testWindow)(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	s := f.state(t, db)
+	if !s.ReviewPublished || s.MasterReviews != 1 {
+		t.Errorf("отзыв не открыт после окна: %+v", s)
+	}
+}
+
+// Обе стороны оставляют отзыв одновременно: вторая транзакция ждёт
+// блокировку проекта и после фиксации первой видит оба отзыва
+func TestRevealConcurrentReviews(t *testing.T) {
+	db := testdb.Open(t)
+	f := completedProject(t, db, time.Hour)
+
+	first := db.Begin()
+	defer first.Rollback()
+	f.addReview(t, first, 5)
+	if published, err := Reveal(first, f.projectID, testWindow); err != nil || published {
+		t.Fatalf("первый Reveal = %v, %v; want false", published, err)
+	}
+
+	type result struct {
+		published bool
+		err       error
+	}
+	done := make(chan result, 1)
+	go func() {
+		var published bool
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("INSERT INTO client_reviews (project_id, master_id, client_id, rating) VALUES (?, ?, ?, 4)",
+				f.projectID, f.masterID, f.clientID).Error; err != nil {
+				return err
+			}
+			var err error
+			published, err = Reveal(tx, f.projectID, testWindow)
+			return err
+		})
+		done <- result{published, err}
+	}()
+
+	// Второй Reveal стоит на блокировке, пока первая транзакция открыта
+	select {
+	case r := <-done:
+		t.Fatalf("второй Reveal не дождался блокировки: %+v", r)
+	case <-time.After(200 * time.Millisecond):
+	}
+	if err := first.Commit().Error; err != nil {
+		t.Fatal(err)
+	}
+
+	r := <-done
+	if r.err != nil || !r.published {
+		t.Fatalf("второй Reveal = %v, %v; want true", r.published, r.err)
+	}
+	if s := f.state(t, db); !s.ReviewPublished || !s.ClientReviewPublished {
+		t.Errorf("отзывы не открыты: %+v", s)
+	}
+}
+
+func TestWindowClosed(t *testing.T) {
+	recent := time.Now().Add(-time.Hour)
+	old := time.Now().Add(-testWindow - time.Hour)
+	if WindowClosed(nil, testWindow) {
+		t.Error("незавершённый проект: окно не должно быть закрыто")
+	}
+	if WindowClosed(&recent, testWindow) {
+		t.Error("проект завершён час назад: окно ещё открыто")
+	}
+	if !WindowClosed(&old, testWindow) {
+		t.Error("окно истекло, а отзывы всё ещё принимаются")
+	}
+}
@@ -0,0 +1,96 @@
+// internal/realtime/postgres.go
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// Channel - канал Postgres LISTEN/NOTIFY для событий
+const Channel = "refurnish_events"
+
+// maxPayload - лимит NOTIFY в Postgres 8000 байт, оставляем запас
+const maxPayload = 7900
+
+var errPayloadTooLarge = errors.New("событие больше лимита NOTIFY")
+
+type envelope struct {
+	UserID string `json:"u"`
+	Event  Event  `json:"e"`
+}
+
+// PostgresBus рассылает события через NOTIFY; каждый экземпляр слушает
+// канал и раздаёт события своим подписчикам, включая отправителя.
+type PostgresBus struct {
+	db  *gorm.DB
+	dsn string
+	hub *Hub
+}
+
+func NewPostgresBus(db *gorm.DB, dsn string, hub *Hub) *PostgresBus {
+	return &PostgresBus{db: db, dsn: dsn, hub: hub}
+}
+
+func (b *PostgresBus) Publish(userID string, e Event) error {
+	payload, err := json.Marshal(envelope{UserID: userID, Event: e})
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxPayload {
+		return errPayloadTooLarge
+	}
+	return b.db.Exec("SELECT pg_notify(?, ?)", Channel, string(payload)).Error
+}
+
+// Listen держит LISTEN-соединение и переподключается при обрывах,
+// пока не отменён ctx.
+func (b *PostgresBus) Listen(ctx context.Context) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		err := b.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("⚠️  Realtime: LISTEN прерван: %v, переподключение через %s", err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+func (b *PostgresBus) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+	log.Printf("📡 Realtime: слушаем канал %s", Channel)
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var env envelope
+		if err := json.Unmarshal([]byte(n.Payload), &env); err != nil {
+			log.Printf("⚠️  Realtime: неверное событие: %v", err)
+			continue
+		}
+		b.hub.Deliver(env.UserID, env.Event)
+	}
+}
@@ -0,0 +1,180 @@
+// internal/realtime/realtime.go
+package realtime
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Типы событий. Подписка по префиксу: "message" получает все "message.*"
+const (
+	ResponseCreated      = "response.created"
+	ProjectAssigned      = "project.assigned"
+	ProjectStatusChanged = "project.status_changed"
+	MessageCreated       = "message.created"
+	MessagesRead         = "message.read"
+	NotificationCreated  = "notification.created"
+)
+
+// Event - событие в личном канале пользователя
+type Event struct {
+	ID   string                 `json:"id"`
+	Type string                 `json:"type"`
+	Data map[string]interface{} `json:"data"`
+	At   time.Time              `json:"at"`
+}
+
+// Bus доставляет события между экземплярами бэкенда.
+// Каждый экземпляр получает все события и раздаёт их своим подписчикам.
+type Bus interface {
+	Publish(userID string, e Event) error
+}
+
+// subscriberBuffer - сколько событий копим для медленного клиента,
+// дальше события для него отбрасываются
+const subscriberBuffer = 64
+
+// Subscription - подписка одного соединения на события пользователя
+type Subscription struct {
+	C <-chan Event
+
+	userID string
+	ch     chan Event
+	mu     sync.RWMutex
+	types  []string
+}
+
+// SetTypes меняет фильтр подписки; пустой фильтр - все события
+func (s *Subscription) SetTypes(types []string) {
+	clean := make([]string, 0, len(types))
+	for _, t := range types {
+		if t = strings.TrimSpace(t); t != "" {
+			clean = append(clean, t)
+		}
+	}
+	s.mu.Lock()
+	s.types = clean
+	s.mu.Unlock()
+}
+
+func (s *Subscription) wants(eventType string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.types) == 0 {
+		return true
+	}
+	for _, t := range s.types {
+		if eventType == t || strings.HasPrefix(eventType, t+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Hub хранит подписки соединений этого экземпляра
+type Hub struct {
+	mu   sync.RWMutex
+	subs map[string]map[*Subscription]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[string]map[*Subscription]struct{})}
+}
+
+// Subscribe подписывает соединение на события пользователя
+func (h *Hub) Subscribe(userID string, types []string) *Subscription {
+	ch := make(chan Event, subscriberBuffer)
+	s := &Subscription{C: ch, userID: userID, ch: ch}
+	s.SetTypes(types)
+
+	h.mu.Lock()
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[*Subscription]struct{})
+	}
+	h.subs[userID][s] = struct{}{}
+	h.mu.Unlock()
+	return s
+}
+
+// Unsubscribe снимает подписку и закрывает её канал
+func (h *Hub) Unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[s.userID][s]; !ok {
+		return
+	}
+	delete(h.subs[s.userID], s)
+	if len(h.subs[s.userID]) == 0 {
+		delete(h.subs, s.userID)
+	}
+	close(s.ch)
+}
+
+// Deliver раздаёт событие подпискам пользователя на этом экземпляре
+func (h *Hub) Deliver(userID string, e Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for s := range h.subs[userID] {
+		if !s.wants(e.Type) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			log.Printf("⚠️  Realtime: очередь подписчика user_id=%s переполнена, событие %s пропущено", userID, e.Type)
+		}
+	}
+}
+
+// Publish без внешней шины: только локальные подписчики
+func (h *Hub) Publish(userID string, e Event) error {
+	h.Deliver(userID, e)
+	return nil
+}
+
+var (
+	hub     = NewHub()
+	bus Bus = hub
+)
+
+// Default - хаб этого экземпляра
+func Default() *Hub {
+	return hub
+}
+
+// SetBus подменяет шину доставки (по умолчанию - только локальный хаб)
+func SetBus(b Bus) {
+	bus = b
+}
+
+// Publish отправляет событие в личный канал пользователя.
+// Ошибки шины не мешают основной операции - только логируются.
+func Publish(userID, eventType string, data map[string]interface{}) {
+	if userID == "" {
+		return
+	}
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	e := Event{
+		ID:   newID(),
+		Type: eventType,
+		Data: data,
+		At:   time.Now(),
+	}
+	if err := bus.Publish(userID, e); err != nil {
+		log.Printf("⚠️  Realtime: не удалось опубликовать %s для user_id=%s: %v", eventType, userID, err)
+		// Хотя бы подписчики этого экземпляра получат событие
+		hub.Deliver(userID, e)
+	}
+}
+
+func newID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,65 @@
+// internal/realtime/tickets.go
+package realtime
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TicketTTL - сколько живёт непогашенный билет
+const TicketTTL = 30 * time.Second
+
+// ErrInvalidTicket - билет не найден, просрочен или уже использован
+var ErrInvalidTicket = errors.New("недействительный билет")
+
+// IssueTicket выдаёт одноразовый билет для ?ticket= в /api/realtime/*.
+// Билет хранится в базе, чтобы его можно было погасить на любом экземпляре.
+func IssueTicket(db *gorm.DB, userID, role string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	ticket := hex.EncodeToString(b)
+
+	// Заодно чистим просроченные
+	db.Exec("DELETE FROM realtime_tickets WHERE expires_at < NOW()")
+
+	err := db.Exec(
+		"INSERT INTO realtime_tickets (id, user_id, role, expires_at) VALUES (?, ?, ?, ?)",
+		ticketHash(ticket), userID, role, time.Now().Add(TicketTTL),
+	).Error
+	if err != nil {
+		return "", err
+	}
+	return ticket, nil
+}
+
+// RedeemTicket гасит билет и возвращает его владельца. Повторно тот же
+// билет не примется, поэтому его попадание в логи запросов безопасно.
+func RedeemTicket(db *gorm.DB, ticket string) (userID, role string, err error) {
+	var owner struct {
+		UserID string
+		Role   string
+	}
+	err = db.Raw(
+		"DELETE FROM realtime_tickets WHERE id = ? AND expires_at > NOW() RETURNING user_id, role",
+		ticketHash(ticket),
+	).Scan(&owner).Error
+	if err != nil {
+		return "", "", err
+	}
+	if owner.UserID == "" {
+		return "", "", ErrInvalidTicket
+	}
+	return owner.UserID, owner.Role, nil
+}
+
+func ticketHash(ticket string) string {
+	sum := sha256.Sum256([]byte(ticket))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,194 @@
+package recommend
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"refurnish/internal/catalog"
+	"refurnish/internal/cities"
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Веса факторов ленты мастера (в сумме 1)
+var feedWeights = map[string]float64{
+	"specialization": 0.35,
+	"location":       0.25,
+	"price":          0.20,
+	"freshness":      0.20,
+}
+
+var feedFactorOrder = []string{"specialization", "location", "price", "freshness"}
+
+// freshnessHalfLife - за это время свежесть проекта падает вдвое
+const freshnessHalfLife = 72 * time.Hour
+
+// FeedWindow - сколько самых свежих проектов ранжируется в ленте.
+// Более старые проекты в ленту не попадают, даже если подходят лучше.
+const FeedWindow = 300
+
+// FeedItem - проект ленты с оценкой релевантности для мастера
+type FeedItem struct {
+	Project models.Project
+	Score   float64
+	Factors []Factor
+}
+
+// Feed ранжирует опубликованные проекты для мастера. Проекты, на которые он
+// уже откликнулся или которые скрыл, в ленту не попадают. limited - подходящих
+// проектов больше FeedWindow и ранжированы только самые свежие.
+func Feed(db *gorm.DB, master models.Master) (items []FeedItem, limited bool, err error) {
+	var projects []models.Project
+	err = db.Where("status = ? AND visibility = ?", "published", "public").
+		Where("NOT EXISTS (SELECT 1 FROM responses r WHERE r.project_id = projects.id AND r.master_id = ?)", master.ID).
+		Where("NOT EXISTS (SELECT 1 FROM project_hides h WHERE h.project_id = projects.id AND h.master_id = ?)", master.ID).
+		Order("COALESCE(published_at, created_at) DESC").
+		Limit(FeedWindow + 1).
+		Find(&projects).Error
+	if err != nil {
+		return nil, false, err
+	}
+	if len(projects) > FeedWindow {
+		projects, limited = projects[:FeedWindow], true
+	}
+
+	// Специализации мастера вместе со смежными категориями
+	exact := make(map[string]bool)
+	related := make(map[string]bool)
+	for _, slug := range master.Specializations {
+		exact[slug] = true
+		category, err := catalog.Resolve(db, slug)
+		if err != nil {
+			continue
+		}
+		slugs, _ := catalog.RelatedSlugs(db, category.ID)
+		for _, s := range slugs {
+			related[s] = true
+		}
+	}
+
+	typicalPrice := typicalPrice(db, master)
+
+	masterCity, _ := cities.Find(db, master.City)
+	cityCache := map[string]*models.City{}
+
+	now := time.Now()
+	items = make([]FeedItem, 0, len(projects))
+	for _, project := range projects {
+		scores := map[string]Factor{
+			"specialization": feedSpecializationFactor(project, exact, related),
+			"location":       locationFactor(db, master, masterCity, project, cityCache),
+			"price":          typicalPriceFactor(project, typicalPrice),
+			"freshness":      freshnessFactor(project, now),
+		}
+
+		item := FeedItem{Project: project}
+		for _, name := range feedFactorOrder {
+			f := scores[name]
+			f.Name = name
+			f.Weight = feedWeights[name]
+			f.Score = round(f.Score)
+			f.Contribution = round(f.Score * f.Weight)
+			item.Score += f.Contribution
+			item.Factors = append(item.Factors, f)
+		}
+		item.Score = round(item.Score)
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Score > items[j].Score
+	})
+	return items, limited, nil
+}
+
+// typicalPrice - медиана цен из прошлых откликов мастера, иначе его "цена от"
+func typicalPrice(db *gorm.DB, master models.Master) int {
+	var median float64
+	db.Raw(`
+		SELECT COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY price), 0)
+		FROM responses WHERE master_id = ? AND price > 0
+	`, master.ID).Scan(&median)
+	if median > 0 {
+		return int(median)
+	}
+	return master.PriceFrom
+}
+
+func feedSpecializationFactor(project models.Project, exact, related map[string]bool) Factor {
+	if len(exact) == 0 {
+		return Factor{Score: 0.5, Explanation: "Специализации не указаны в профиле"}
+	}
+	if exact[project.FurnitureType] {
+		return Factor{Score: 1, Explanation: "Ваша специализация"}
+	}
+	if related[project.FurnitureType] {
+		return Factor{Score: 0.6, Explanation: "Смежная категория"}
+	}
+	return Factor{Score: 0.1, Explanation: "Не ваша специализация"}
+}
+
+func locationFactor(db *gorm.DB, master models.Master, masterCity *models.City, project models.Project, cache map[string]*models.City) Factor {
+	if master.City == "" {
+		return Factor{Score: 0.5, Explanation: "Город не указан в профиле"}
+	}
+	if project.City == master.City {
+		return Factor{Score: 1, Explanation: "Ваш город"}
+	}
+	if masterCity == nil {
+		return Factor{Score: 0, Explanation: "Другой город"}
+	}
+
+	projectCity, ok := cache[project.City]
+	if !ok {
+		projectCity, _ = cities.Find(db, project.City)
+		cache[project.City] = projectCity
+	}
+	if projectCity == nil {
+		return Factor{Score: 0, Explanation: "Другой город"}
+	}
+
+	d := cities.DistanceKm(*masterCity, *projectCity)
+	if master.ServiceRadiusKm > 0 && d <= float64(master.ServiceRadiusKm) {
+		return Factor{Score: 0.9, Explanation: fmt.Sprintf("В зоне выезда, %.0f км", d)}
+	}
+	return Factor{
+		Score:       math.Max(0, 1-d/maxDistanceKm) * 0.5,
+		Explanation: fmt.Sprintf("%s, %.0f км", project.City, d),
+	}
+}
+
+func typicalPriceFactor(project models.Project, typical int) Factor {
+	if typical <= 0 || project.Budget <= 0 {
+		return Factor{Score: 0.5, Explanation: "Нет данных о ценах"}
+	}
+	ratio := float64(project.Budget) / float64(typical)
+	if ratio >= 0.8 {
+		return Factor{Score: 1, Explanation: fmt.Sprintf("Бюджет %d ₽ соответствует вашим ценам", project.Budget)}
+	}
+	return Factor{
+		Score:       math.Max(0, ratio/0.8),
+		Explanation: fmt.Sprintf("Бюджет %d ₽ ниже ваших обычных %d ₽", project.Budget, typical),
+	}
+}
+
+func freshnessFactor(project models.Project, now time.Time) Factor {
+	published := project.CreatedAt
+	if project.PublishedAt != nil {
+		published = *project.PublishedAt
+	}
+	age := now.Sub(published)
+	if age < 0 {
+		age = 0
+	}
+	score := math.Pow(0.5, age.Hours()/freshnessHalfLife.Hours())
+
+	explanation := "Опубликован сегодня"
+	if days := int(age.Hours() / 24); days > 0 {
+		explanation = fmt.Sprintf("Опубликован %d дн. назад", days)
+	}
+	return Factor{Score: score, Explanation: explanation}
+}
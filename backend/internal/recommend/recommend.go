@@ -0,0 +1,247 @@
+// internal/recommend/recommend.go
+package recommend
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"refurnish/internal/catalog"
+	"refurnish/internal/cities"
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Веса факторов итоговой оценки (в сумме 1)
+var weights = map[string]float64{
+	"specialization": 0.30,
+	"rating":         0.20,
+	"distance":       0.15,
+	"price":          0.15,
+	"responseRate":   0.10,
+	"availability":   0.10,
+}
+
+// Порядок факторов в ответе
+var factorOrder = []string{"specialization", "rating", "distance", "price", "responseRate", "availability"}
+
+// Factor - вклад одного фактора в оценку мастера
+type Factor struct {
+	Name         string  `json:"name"`
+	Score        float64 `json:"score"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+	Explanation  string  `json:"explanation"`
+}
+
+// Recommendation - мастер с итоговой оценкой и её разбивкой
+type Recommendation struct {
+	Master  models.Master
+	Score   float64
+	Factors []Factor
+}
+
+// masterStats - история мастера, нужная для оценки
+type masterStats struct {
+	MasterID       string
+	Invitations    int
+	Answered       int
+	ActiveProjects int
+}
+
+// maxDistanceKm - на таком расстоянии фактор удалённости обнуляется
+const maxDistanceKm = 300.0
+
+// Rank возвращает до limit мастеров, лучше всего подходящих под проект
+func Rank(db *gorm.DB, project models.Project, limit int) ([]Recommendation, error) {
+	query := db.Model(&models.Master{})
+
+	// Кандидаты - мастера смежных категорий; без категории смотрим всех
+	var exact map[string]bool
+	var related []string
+	if project.CategoryID != nil {
+		var err error
+		related, err = catalog.RelatedSlugs(db, *project.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("specializations && ?::text[]", models.StringArray(related))
+		exact = map[string]bool{project.FurnitureType: true}
+	}
+
+	// Оцениваются не больше 500 кандидатов: сначала мастера из города
+	// проекта, затем по рейтингу - чтобы в выборку не попадали случайные
+	query = query.
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "(city = ?) DESC", Vars: []interface{}{project.City}}}).
+		Order("rating DESC, reviews_count DESC, id")
+
+	var masters []models.Master
+	if err := query.Limit(500).Find(&masters).Error; err != nil {
+		return nil, err
+	}
+	if len(masters) == 0 {
+		return []Recommendation{}, nil
+	}
+
+	stats, err := loadStats(db, masters)
+	if err != nil {
+		return nil, err
+	}
+
+	projectCity, _ := cities.Find(db, project.City)
+	cityCache := map[string]*models.City{}
+
+	result := make([]Recommendation, 0, len(masters))
+	for _, master := range masters {
+		scores := map[string]Factor{
+			"specialization": specializationFactor(master, exact, related),
+			"rating":         ratingFactor(master),
+			"distance":       distanceFactor(db, master, project, projectCity, cityCache),
+			"price":          priceFactor(master, project),
+			"responseRate":   responseRateFactor(stats[master.ID]),
+			"availability":   availabilityFactor(stats[master.ID]),
+		}
+
+		rec := Recommendation{Master: master}
+		for _, name := range factorOrder {
+			f := scores[name]
+			f.Name = name
+			f.Weight = weights[name]
+			f.Score = round(f.Score)
+			f.Contribution = round(f.Score * f.Weight)
+			rec.Score += f.Contribution
+			rec.Factors = append(rec.Factors, f)
+		}
+		rec.Score = round(rec.Score)
+		result = append(result, rec)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func loadStats(db *gorm.DB, masters []models.Master) (map[string]masterStats, error) {
+	ids := make([]string, 0, len(masters))
+	for _, m := range masters {
+		ids = append(ids, m.ID)
+	}
+
+	var rows []masterStats
+	err := db.Raw(`
+		SELECT m.id AS master_id,
+			(SELECT count(*) FROM invitations i WHERE i.master_id = m.id) AS invitations,
+			(SELECT count(*) FROM invitations i WHERE i.master_id = m.id AND i.status <> 'pending') AS answered,
+			(SELECT count(*) FROM projects p WHERE p.assigned_master = m.id AND p.status = 'assigned') AS active_projects
+		FROM masters m
+		WHERE m.id IN ?
+	`, ids).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]masterStats, len(rows))
+	for _, row := range rows {
+		stats[row.MasterID] = row
+	}
+	return stats, nil
+}
+
+func specializationFactor(master models.Master, exact map[string]bool, related []string) Factor {
+	if exact == nil {
+		return Factor{Score: 0.5, Explanation: "Категория проекта не указана"}
+	}
+	for _, s := range master.Specializations {
+		if exact[s] {
+			return Factor{Score: 1, Explanation: "Специализация совпадает с категорией проекта"}
+		}
+	}
+	for _, s := range master.Specializations {
+		for _, r := range related {
+			if s == r {
+				return Factor{Score: 0.7, Explanation: "Специализация в смежной категории: " + s}
+			}
+		}
+	}
+	return Factor{Score: 0, Explanation: "Нет подходящей специализации"}
+}
+
+func ratingFactor(master models.Master) Factor {
+	if master.Rating <= 0 {
+		return Factor{Score: 0.5, Explanation: "Пока нет оценок"}
+	}
+	return Factor{
+		Score:       math.Min(master.Rating/5, 1),
+		Explanation: fmt.Sprintf("Рейтинг %.1f из 5", master.Rating),
+	}
+}
+
+func distanceFactor(db *gorm.DB, master models.Master, project models.Project, projectCity *models.City, cache map[string]*models.City) Factor {
+	if master.City == "" || project.City == "" {
+		return Factor{Score: 0, Explanation: "Город не указан"}
+	}
+	if master.City == project.City {
+		return Factor{Score: 1, Explanation: "Тот же город"}
+	}
+	if projectCity == nil {
+		return Factor{Score: 0, Explanation: "Другой город"}
+	}
+
+	masterCity, ok := cache[master.City]
+	if !ok {
+		masterCity, _ = cities.Find(db, master.City)
+		cache[master.City] = masterCity
+	}
+	if masterCity == nil {
+		return Factor{Score: 0, Explanation: "Другой город"}
+	}
+
+	d := cities.DistanceKm(*masterCity, *projectCity)
+	return Factor{
+		Score:       math.Max(0, 1-d/maxDistanceKm),
+		Explanation: fmt.Sprintf("%s, %.0f км", master.City, d),
+	}
+}
+
+func priceFactor(master models.Master, project models.Project) Factor {
+	if master.PriceFrom <= 0 || project.Budget <= 0 {
+		return Factor{Score: 0.5, Explanation: "Нет данных о цене или бюджете"}
+	}
+	if master.PriceFrom <= project.Budget {
+		return Factor{Score: 1, Explanation: fmt.Sprintf("Цена от %d ₽ укладывается в бюджет", master.PriceFrom)}
+	}
+	over := float64(master.PriceFrom-project.Budget) / float64(project.Budget)
+	return Factor{
+		Score:       math.Max(0, 1-over),
+		Explanation: fmt.Sprintf("Цена от %d ₽ выше бюджета на %.0f%%", master.PriceFrom, over*100),
+	}
+}
+
+func responseRateFactor(s masterStats) Factor {
+	if s.Invitations == 0 {
+		return Factor{Score: 0.5, Explanation: "Ещё не получал приглашений"}
+	}
+	rate := float64(s.Answered) / float64(s.Invitations)
+	return Factor{
+		Score:       rate,
+		Explanation: fmt.Sprintf("Отвечает на %.0f%% приглашений", rate*100),
+	}
+}
+
+func availabilityFactor(s masterStats) Factor {
+	score := math.Max(0, 1-float64(s.ActiveProjects)*0.25)
+	if s.ActiveProjects == 0 {
+		return Factor{Score: score, Explanation: "Свободен"}
+	}
+	return Factor{Score: score, Explanation: fmt.Sprintf("В работе проектов: %d", s.ActiveProjects)}
+}
+
+func round(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
@@ -0,0 +1,198 @@
+// internal/recommend/search.go
+package recommend
+
+import (
+	"strings"
+	"time"
+
+	"refurnish/internal/models"
+	"refurnish/internal/ratings"
+
+	"gorm.io/gorm"
+)
+
+// Ранжирование каталога мастеров: рейтинг, число отзывов и недавняя
+// активность (последний отклик). Веса в сумме дают 1.
+const (
+	searchRatingWeight   = 0.5
+	searchReviewsWeight  = 0.2
+	searchActivityWeight = 0.3
+	// При таком числе отзывов фактор отзывов достигает максимума
+	searchReviewsSaturation = 50
+	// Активность затухает экспоненциально: через 30 дней без откликов - в e раз
+	searchActivityDays = 30
+)
+
+// Варианты сортировки каталога
+const (
+	SortRank      = "rank"
+	SortRating    = "rating"
+	SortReviews   = "reviews"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+	SortNewest    = "newest"
+	SortDistance  = "distance"
+)
+
+// SearchFilter - параметры поиска мастеров. Пустые поля не фильтруют.
+type SearchFilter struct {
+	City            *models.City // город из справочника: мастера рядом и с выездом сюда
+	CityName        string       // город не найден в справочнике - точное совпадение
+	RadiusKm        float64      // дополнительный радиус поиска вокруг City
+	Specializations []string     // slug'и категорий
+	PriceMin        int
+	PriceMax        int
+	MinRating       float64
+	VerifiedOnly    bool   // только мастера, прошедшие проверку документов
+	AvailableOnly   bool   // не в отпуске и не достигли лимита проектов
+	Query           string // полнотекстовый поиск по имени и описанию
+	Sort            string
+	Limit           int
+	Offset          int
+}
+
+// SearchResult - мастер и вычисленные для него значения
+type SearchResult struct {
+	Master       models.Master
+	Score        float64
+	DistanceKm   *float64
+	LastActiveAt *time.Time
+}
+
+// Полнотекстовый вектор мастера. Выражение совпадает с индексом
+// idx_masters_search, иначе индекс не используется.
+const masterSearchVector = "to_tsvector('russian', coalesce(m.name, '') || ' ' || coalesce(m.description, ''))"
+
+// Search возвращает страницу мастеров и общее число найденных
+func Search(db *gorm.DB, f SearchFilter) ([]SearchResult, int64, error) {
+	query := db.Table("masters m").
+		Joins("LEFT JOIN cities mc ON mc.name = m.city").
+		Joins("LEFT JOIN LATERAL (SELECT MAX(r.created_at) AS last_active_at FROM responses r WHERE r.master_id = m.id) a ON true")
+
+	distance := "NULL::float8"
+	var distanceArgs []interface{}
+	if f.City != nil {
+		distance = `(2 * 6371 * asin(sqrt(
+			power(sin(radians(mc.latitude - ?) / 2), 2) +
+			cos(radians(?)) * cos(radians(mc.latitude)) * power(sin(radians(mc.longitude - ?) / 2), 2))))`
+		distanceArgs = []interface{}{f.City.Latitude, f.City.Latitude, f.City.Longitude}
+
+		// Мастер из этого города, из города в радиусе поиска или готовый
+		// выехать на такое расстояние
+		args := append([]interface{}{f.City.Name}, distanceArgs...)
+		args = append(args, f.RadiusKm)
+		query = query.Where("(m.city = ? OR (mc.id IS NOT NULL AND "+distance+" <= GREATEST(m.service_radius_km, ?::float8)))", args...)
+	} else if f.CityName != "" {
+		query = query.Where("m.city = ?", f.CityName)
+	}
+
+	if len(f.Specializations) > 0 {
+		query = query.Where("m.specializations && ?::text[]", models.StringArray(f.Specializations))
+	}
+	if f.PriceMin > 0 {
+		query = query.Where("m.price_from >= ?", f.PriceMin)
+	}
+	if f.PriceMax > 0 {
+		query = query.Where("m.price_from > 0 AND m.price_from <= ?", f.PriceMax)
+	}
+	if f.VerifiedOnly {
+		query = query.Where("m.verified_at IS NOT NULL")
+	}
+	if f.AvailableOnly {
+		query = query.Where(`m.max_concurrent_projects > (SELECT COUNT(*) FROM projects p WHERE p.assigned_master = m.id AND p.status = 'assigned')`).
+			Where("NOT EXISTS (SELECT 1 FROM master_time_off t WHERE t.master_id = m.id AND CURRENT_DATE BETWEEN t.starts_on AND t.ends_on)")
+	}
+	if f.MinRating > 0 {
+		query = query.Where("m.rating >= ?", f.MinRating)
+	}
+	if q := strings.TrimSpace(f.Query); q != "" {
+		query = query.Where("("+masterSearchVector+" @@ plainto_tsquery('russian', ?) OR m.name ILIKE ?)",
+			q, "%"+escapeLike(q)+"%")
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Без отзывов рейтинг считается средним по площадке
+	score := `(? * COALESCE(NULLIF(m.rating, 0), ?) / 5
+		+ ? * LEAST(ln(1 + m.reviews_count) / ln(?), 1)
+		+ ? * COALESCE(exp(-LEAST(EXTRACT(EPOCH FROM now() - a.last_active_at)::float8 / 86400 / ?, 50)), 0))`
+	selectArgs := []interface{}{
+		searchRatingWeight, ratings.Prior(db),
+		searchReviewsWeight, 1 + searchReviewsSaturation,
+		searchActivityWeight, searchActivityDays,
+	}
+	selectArgs = append(selectArgs, distanceArgs...)
+
+	var rows []struct {
+		ID           string
+		Score        float64
+		DistanceKm   *float64
+		LastActiveAt *time.Time
+	}
+	err := query.
+		Select("m.id, "+score+" AS score, "+distance+" AS distance_km, a.last_active_at", selectArgs...).
+		Order(searchOrder(f)).
+		Limit(f.Limit).Offset(f.Offset).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(rows) == 0 {
+		return []SearchResult{}, total, nil
+	}
+
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	var masters []models.Master
+	if err := db.Where("id IN ?", ids).Find(&masters).Error; err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[string]models.Master, len(masters))
+	for _, m := range masters {
+		byID[m.ID] = m
+	}
+
+	results := make([]SearchResult, 0, len(rows))
+	for _, row := range rows {
+		master, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, SearchResult{
+			Master:       master,
+			Score:        round(row.Score),
+			DistanceKm:   row.DistanceKm,
+			LastActiveAt: row.LastActiveAt,
+		})
+	}
+	return results, total, nil
+}
+
+func searchOrder(f SearchFilter) string {
+	switch f.Sort {
+	case SortRating:
+		return "m.rating DESC, m.reviews_count DESC, m.id"
+	case SortReviews:
+		return "m.reviews_count DESC, m.rating DESC, m.id"
+	case SortPriceAsc:
+		return "NULLIF(m.price_from, 0) ASC NULLS LAST, m.id"
+	case SortPriceDesc:
+		return "m.price_from DESC, m.id"
+	case SortNewest:
+		return "m.created_at DESC, m.id"
+	case SortDistance:
+		if f.City != nil {
+			return "distance_km ASC NULLS LAST, score DESC, m.id"
+		}
+	}
+	return "score DESC, m.id"
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,104 @@
+// internal/respond/respond.go
+package respond
+
+import (
+	"errors"
+	"time"
+
+	"refurnish/internal/events"
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Ошибки отклика; обработчики переводят их в HTTP-статусы, бот - в ответы
+var (
+	ErrProjectNotFound  = errors.New("project not found or not available")
+	ErrProjectExpired   = errors.New("project has expired")
+	ErrInviteOnly       = errors.New("this project is available by invitation only")
+	ErrAlreadyResponded = errors.New("you have already responded to this project")
+)
+
+// Input - данные отклика мастера
+type Input struct {
+	ProjectID string
+	Comment   string
+	Price     int
+	StartDate time.Time
+}
+
+// Submit проверяет доступность проекта и создаёт отклик мастера.
+// Отклик, приглашение и событие response.created пишутся одной транзакцией.
+func Submit(db *gorm.DB, master *models.Master, input Input) (*models.Response, error) {
+	var project models.Project
+	if err := db.Preload("Client").Where("id = ?", input.ProjectID).First(&project).Error; err != nil {
+		return nil, ErrProjectNotFound
+	}
+
+	// Истёкший проект (в том числе ещё не обработанный фоновой задачей)
+	if project.Status == "expired" ||
+		(project.Status == "published" && !project.Deadline.IsZero() &&
+			project.Deadline.Before(time.Now().Truncate(24*time.Hour))) {
+		return nil, ErrProjectExpired
+	}
+
+	if project.Status != "published" {
+		return nil, ErrProjectNotFound
+	}
+
+	// В проект по приглашениям могут откликнуться только приглашённые мастера
+	if project.Visibility == "invite_only" {
+		var invited int64
+		db.Model(&models.Invitation{}).
+			Where("project_id = ? AND master_id = ? AND status <> ?", project.ID, master.ID, "declined").
+			Count(&invited)
+		if invited == 0 {
+			return nil, ErrInviteOnly
+		}
+	}
+
+	// Проверяем, не откликался ли уже мастер
+	var existing int64
+	db.Model(&models.Response{}).Where("project_id = ? AND master_id = ?", project.ID, master.ID).Count(&existing)
+	if existing > 0 {
+		return nil, ErrAlreadyResponded
+	}
+
+	response := models.Response{
+		ProjectID: project.ID,
+		MasterID:  master.ID,
+		Comment:   input.Comment,
+		Price:     input.Price,
+		StartDate: input.StartDate,
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&response).Error; err != nil {
+			return err
+		}
+
+		// Отклик по приглашению считается его принятием
+		err := tx.Exec(`
+			UPDATE invitations SET status = 'accepted', responded_at = now()
+			WHERE project_id = ? AND master_id = ? AND status = 'pending'
+		`, project.ID, master.ID).Error
+		if err != nil {
+			return err
+		}
+
+		return events.Emit(tx, events.ResponseCreated, response.ID, map[string]interface{}{
+			"projectId":    project.ID,
+			"projectTitle": project.Title,
+			"clientUserId": project.Client.UserID,
+			"masterId":     master.ID,
+			"masterName":   master.Name,
+			"price":        response.Price,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	events.Wake()
+
+	return &response, nil
+}
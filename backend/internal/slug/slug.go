@@ -0,0 +1,49 @@
+// internal/slug/slug.go
+package slug
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Транслитерация кириллицы для адресов страниц (упрощённая ГОСТ 7.79-2000, схема Б)
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+}
+
+// MaxLength - ограничение длины slug'а
+const MaxLength = 60
+
+// Make строит slug из произвольного текста: "Иван Петров" -> "ivan-petrov".
+// Пустой результат означает, что в тексте нет ни букв, ни цифр.
+func Make(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case translit[r] != "" || r == 'ъ' || r == 'ь':
+			b.WriteString(translit[r])
+			dash = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			// прочие алфавиты пропускаем
+		default:
+			if !dash && b.Len() > 0 {
+				b.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+
+	s := strings.Trim(b.String(), "-")
+	if len(s) > MaxLength {
+		s = strings.TrimRight(s[:MaxLength], "-")
+	}
+	return s
+}
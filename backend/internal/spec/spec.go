@@ -0,0 +1,245 @@
+// internal/spec/spec.go
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Типы полей спецификации
+const (
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeString  = "string"
+	TypeEnum    = "enum"
+	TypeBoolean = "boolean"
+)
+
+// KeyPattern - допустимые ключи полей (используются в фильтрах OpenProjects)
+var KeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+// Field - описание одного поля спецификации
+type Field struct {
+	Key      string   `json:"key"`
+	Type     string   `json:"type"`
+	LabelRu  string   `json:"labelRu"`
+	LabelEn  string   `json:"labelEn,omitempty"`
+	Unit     string   `json:"unit,omitempty"`
+	Required bool     `json:"required,omitempty"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+	Options  []string `json:"options,omitempty"`
+}
+
+// Label возвращает подпись поля на нужном языке
+func (f Field) Label(lang string) string {
+	if lang == "en" && f.LabelEn != "" {
+		return f.LabelEn
+	}
+	return f.LabelRu
+}
+
+// Schema - набор полей, который категория требует от проекта
+type Schema struct {
+	Fields []Field `json:"fields"`
+}
+
+// Field ищет поле по ключу
+func (s Schema) Field(key string) (Field, bool) {
+	for _, f := range s.Fields {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// Parse разбирает и проверяет схему, заданную администратором
+func Parse(raw []byte) (Schema, error) {
+	var schema Schema
+	if len(raw) == 0 || string(raw) == "null" {
+		return schema, nil
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return schema, fmt.Errorf("неверный формат схемы: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range schema.Fields {
+		if !KeyPattern.MatchString(f.Key) {
+			return schema, fmt.Errorf("недопустимый ключ поля %q", f.Key)
+		}
+		if seen[f.Key] {
+			return schema, fmt.Errorf("поле %q описано дважды", f.Key)
+		}
+		seen[f.Key] = true
+
+		switch f.Type {
+		case TypeNumber, TypeInteger, TypeString, TypeBoolean:
+		case TypeEnum:
+			if len(f.Options) == 0 {
+				return schema, fmt.Errorf("у поля %q нет вариантов значений", f.Key)
+			}
+		default:
+			return schema, fmt.Errorf("неизвестный тип поля %q: %s", f.Key, f.Type)
+		}
+		if f.LabelRu == "" {
+			return schema, fmt.Errorf("у поля %q нет подписи", f.Key)
+		}
+	}
+	return schema, nil
+}
+
+// ForCategory собирает итоговую схему категории: поля предков
+// идут первыми, дочерняя категория может переопределить поле по ключу.
+func ForCategory(db *gorm.DB, categoryID string) (Schema, error) {
+	var chain []models.JSONB
+	err := db.Raw(`
+		WITH RECURSIVE up AS (
+			SELECT id, parent_id, spec_schema, 0 AS depth FROM categories WHERE id = ?
+			UNION ALL
+			SELECT c.id, c.parent_id, c.spec_schema, u.depth + 1
+			FROM categories c JOIN up u ON c.id = u.parent_id
+		)
+		SELECT spec_schema FROM up ORDER BY depth DESC
+	`, categoryID).Scan(&chain).Error
+	if err != nil {
+		return Schema{}, err
+	}
+
+	var result Schema
+	index := make(map[string]int)
+	for _, raw := range chain {
+		schema, err := Parse(raw)
+		if err != nil {
+			return Schema{}, err
+		}
+		for _, f := range schema.Fields {
+			if i, ok := index[f.Key]; ok {
+				result.Fields[i] = f
+				continue
+			}
+			index[f.Key] = len(result.Fields)
+			result.Fields = append(result.Fields, f)
+		}
+	}
+	return result, nil
+}
+
+// Validate проверяет значения проекта по схеме и возвращает очищенный
+// набор (только известные поля, приведённые к нужному типу).
+// В режиме partial (черновик) отсутствие обязательных полей не считается ошибкой.
+func Validate(schema Schema, values map[string]interface{}, partial bool) (map[string]interface{}, []string) {
+	clean := make(map[string]interface{})
+	var problems []string
+
+	for key := range values {
+		if _, ok := schema.Field(key); !ok {
+			problems = append(problems, fmt.Sprintf("%s: неизвестное поле", key))
+		}
+	}
+
+	for _, f := range schema.Fields {
+		raw, present := values[f.Key]
+		if !present || raw == nil || raw == "" {
+			if f.Required && !partial {
+				problems = append(problems, fmt.Sprintf("%s: обязательное поле", f.Key))
+			}
+			continue
+		}
+
+		value, problem := coerce(f, raw)
+		if problem != "" {
+			problems = append(problems, fmt.Sprintf("%s: %s", f.Key, problem))
+			continue
+		}
+		clean[f.Key] = value
+	}
+
+	return clean, problems
+}
+
+func coerce(f Field, raw interface{}) (interface{}, string) {
+	switch f.Type {
+	case TypeNumber, TypeInteger:
+		n, ok := raw.(float64)
+		if !ok {
+			return nil, "ожидается число"
+		}
+		if f.Type == TypeInteger && n != math.Trunc(n) {
+			return nil, "ожидается целое число"
+		}
+		if f.Min != nil && n < *f.Min {
+			return nil, fmt.Sprintf("значение меньше %v", *f.Min)
+		}
+		if f.Max != nil && n > *f.Max {
+			return nil, fmt.Sprintf("значение больше %v", *f.Max)
+		}
+		return n, ""
+	case TypeBoolean:
+		b, ok := raw.(bool)
+		if !ok {
+			return nil, "ожидается true или false"
+		}
+		return b, ""
+	case TypeEnum:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, "ожидается строка"
+		}
+		for _, option := range f.Options {
+			if option == s {
+				return s, ""
+			}
+		}
+		return nil, "недопустимое значение, варианты: " + strings.Join(f.Options, ", ")
+	default:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, "ожидается строка"
+		}
+		s = strings.TrimSpace(s)
+		if len([]rune(s)) > 500 {
+			return nil, "слишком длинное значение"
+		}
+		return s, ""
+	}
+}
+
+// Describe превращает сохранённые значения в упорядоченный список для ответа API
+func Describe(schema Schema, raw []byte, lang string) []map[string]interface{} {
+	result := []map[string]interface{}{}
+	if len(raw) == 0 {
+		return result
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return result
+	}
+
+	for _, f := range schema.Fields {
+		value, ok := values[f.Key]
+		if !ok {
+			continue
+		}
+		item := map[string]interface{}{
+			"key":   f.Key,
+			"label": f.Label(lang),
+			"type":  f.Type,
+			"value": value,
+		}
+		if f.Unit != "" {
+			item["unit"] = f.Unit
+		}
+		result = append(result, item)
+	}
+	return result
+}
@@ -0,0 +1,183 @@
+// internal/subscribers/subscribers.go
+package subscribers
+
+import (
+	"context"
+	"errors"
+
+	"refurnish/internal/alerts"
+	"refurnish/internal/events"
+	"refurnish/internal/notify"
+	"refurnish/internal/portfolio"
+	"refurnish/internal/realtime"
+	"refurnish/internal/webhooks"
+
+	"gorm.io/gorm"
+)
+
+// Register подписывает реакции на доменные события.
+// Имена подписчиков хранятся в outbox_consumers - не переименовывать.
+func Register() {
+	events.Subscribe("search-alerts", searchAlerts, events.ProjectPublished)
+	events.Subscribe("notifications", notifications,
+		events.ResponseCreated, events.MasterAssigned, events.ProjectExpired, events.ReviewCreated,
+		events.ClientReviewCreated, events.InvitationCreated, events.InvitationAnswered, events.AppointmentProposed, events.AppointmentConfirmed,
+		events.AppointmentRescheduled, events.AppointmentCancelled, events.AppointmentReminder)
+	events.Subscribe("realtime", realtimeEvents,
+		events.ResponseCreated, events.MasterAssigned, events.ProjectPublished, events.ProjectExpired,
+		events.ProjectCompleted)
+	events.Subscribe("webhooks", webhooks.OnEvent, webhooks.EventTypes...)
+	events.Subscribe("portfolio", portfolioItems, events.ProjectCompleted)
+}
+
+// portfolioItems - завершённый проект попадает в портфолио мастера,
+// если клиент заранее дал согласие
+func portfolioItems(ctx context.Context, db *gorm.DB, e events.Event) error {
+	_, err := portfolio.FromProject(db.WithContext(ctx), e.AggregateID)
+	if errors.Is(err, portfolio.ErrNoConsent) || errors.Is(err, portfolio.ErrNotCompleted) {
+		return nil
+	}
+	return err
+}
+
+// searchAlerts - сохранённые поиски мастеров (совпадения идемпотентны)
+func searchAlerts(ctx context.Context, db *gorm.DB, e events.Event) error {
+	alerts.ProjectPublished(db.WithContext(ctx), e.AggregateID)
+	return nil
+}
+
+func notifications(ctx context.Context, db *gorm.DB, e events.Event) error {
+	db = db.WithContext(ctx)
+	switch e.Type {
+	case events.ResponseCreated:
+		return notify.Send(db, e.String("clientUserId"), notify.ResponseReceived, map[string]interface{}{
+			"projectId":    e.String("projectId"),
+			"projectTitle": e.String("projectTitle"),
+			"responseId":   e.AggregateID,
+			"masterName":   e.String("masterName"),
+			"price":        e.Int("price"),
+		})
+	case events.MasterAssigned:
+		return notify.Send(db, e.String("masterUserId"), notify.MasterAssigned, map[string]interface{}{
+			"projectId":    e.AggregateID,
+			"projectTitle": e.String("projectTitle"),
+		})
+	case events.ReviewCreated:
+		return notify.Send(db, e.String("masterUserId"), notify.ReviewReceived, map[string]interface{}{
+			"projectId":    e.String("projectId"),
+			"projectTitle": e.String("projectTitle"),
+			"reviewId":     e.AggregateID,
+		})
+	case events.ClientReviewCreated:
+		return notify.Send(db, e.String("clientUserId"), notify.ClientReviewReceived, map[string]interface{}{
+			"projectId":    e.String("projectId"),
+			"projectTitle": e.String("projectTitle"),
+			"reviewId":     e.AggregateID,
+		})
+	case events.InvitationCreated:
+		return notify.Send(db, e.String("masterUserId"), notify.ProjectInvitation, map[string]interface{}{
+			"projectId":    e.String("projectId"),
+			"projectTitle": e.String("projectTitle"),
+			"invitationId": e.AggregateID,
+		})
+	case events.InvitationAnswered:
+		return notify.Send(db, e.String("clientUserId"), notify.InvitationAnswered, map[string]interface{}{
+			"projectId":    e.String("projectId"),
+			"projectTitle": e.String("projectTitle"),
+			"invitationId": e.AggregateID,
+			"masterId":     e.String("masterId"),
+			"masterName":   e.String("masterName"),
+			"status":       e.String("status"),
+		})
+	case events.AppointmentProposed:
+		return notify.Send(db, e.String("clientUserId"), notify.AppointmentProposed, appointmentData(e))
+	case events.AppointmentConfirmed, events.AppointmentReminder:
+		// Подтверждение и напоминание получают обе стороны
+		kind := notify.AppointmentConfirmed
+		if e.Type == events.AppointmentReminder {
+			kind = notify.AppointmentReminder
+		}
+		if err := notify.Send(db, e.String("clientUserId"), kind, appointmentData(e)); err != nil {
+			return err
+		}
+		return notify.Send(db, e.String("masterUserId"), kind, appointmentData(e))
+	case events.AppointmentRescheduled, events.AppointmentCancelled:
+		// Уведомляется сторона, которая не меняла выезд
+		kind := notify.AppointmentRescheduled
+		if e.Type == events.AppointmentCancelled {
+			kind = notify.AppointmentCancelled
+		}
+		userID := e.String("clientUserId")
+		if e.String("by") == "client" {
+			userID = e.String("masterUserId")
+		}
+		return notify.Send(db, userID, kind, appointmentData(e))
+	case events.ProjectExpired:
+		return notify.Send(db, e.String("clientUserId"), notify.ProjectExpired, map[string]interface{}{
+			"projectId":    e.AggregateID,
+			"projectTitle": e.String("projectTitle"),
+			"actions": []map[string]string{
+				{"action": "extend", "method": "POST", "url": "/api/client/project/" + e.AggregateID + "/extend"},
+			},
+		})
+	}
+	return nil
+}
+
+// appointmentData - поля события выезда, которые попадают в уведомление
+func appointmentData(e events.Event) map[string]interface{} {
+	data := map[string]interface{}{"appointmentId": e.AggregateID}
+	for _, key := range []string{"projectId", "projectTitle", "masterName", "when", "address", "slots", "reason"} {
+		data[key] = e.String(key)
+	}
+	return data
+}
+
+func realtimeEvents(ctx context.Context, db *gorm.DB, e events.Event) error {
+	switch e.Type {
+	case events.ResponseCreated:
+		realtime.Publish(e.String("clientUserId"), realtime.ResponseCreated, map[string]interface{}{
+			"projectId":  e.String("projectId"),
+			"responseId": e.AggregateID,
+			"masterId":   e.String("masterId"),
+			"masterName": e.String("masterName"),
+			"price":      e.Int("price"),
+		})
+	case events.MasterAssigned:
+		realtime.Publish(e.String("masterUserId"), realtime.ProjectAssigned, map[string]interface{}{
+			"projectId": e.AggregateID,
+			"masterId":  e.String("masterId"),
+		})
+		// Остальные откликнувшиеся узнают, что проект занят
+		var responders []string
+		err := db.WithContext(ctx).Raw(`
+			SELECT m.user_id FROM responses r
+			JOIN masters m ON m.id = r.master_id
+			WHERE r.project_id = ? AND m.id <> ?
+		`, e.AggregateID, e.String("masterId")).Scan(&responders).Error
+		if err != nil {
+			return err
+		}
+		for _, userID := range responders {
+			realtime.Publish(userID, realtime.ProjectStatusChanged, map[string]interface{}{
+				"projectId": e.AggregateID,
+				"status":    "assigned",
+			})
+		}
+	case events.ProjectCompleted:
+		realtime.Publish(e.String("masterUserId"), realtime.ProjectStatusChanged, map[string]interface{}{
+			"projectId": e.AggregateID,
+			"status":    "completed",
+		})
+	case events.ProjectPublished, events.ProjectExpired:
+		status := "published"
+		if e.Type == events.ProjectExpired {
+			status = "expired"
+		}
+		realtime.Publish(e.String("clientUserId"), realtime.ProjectStatusChanged, map[string]interface{}{
+			"projectId": e.AggregateID,
+			"status":    status,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// internal/telegram/api.go
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// API - используемая часть Telegram Bot API. Реализация по умолчанию
+// ходит по HTTP; для тестов BaseURL можно направить на локальный сервер.
+type API interface {
+	SendMessage(ctx context.Context, chatID int64, text string, keyboard [][]Button) error
+	GetUpdates(ctx context.Context, offset int64, timeout int) ([]Update, error)
+	AnswerCallback(ctx context.Context, callbackID, text string) error
+}
+
+// Button - inline-кнопка; нажатие приходит как CallbackQuery с Data
+type Button struct {
+	Text string `json:"text"`
+	Data string `json:"callback_data"`
+}
+
+type Update struct {
+	UpdateID      int64          `json:"update_id"`
+	Message       *Message       `json:"message"`
+	CallbackQuery *CallbackQuery `json:"callback_query"`
+}
+
+type Message struct {
+	MessageID      int64    `json:"message_id"`
+	Chat           Chat     `json:"chat"`
+	From           *User    `json:"from"`
+	Text           string   `json:"text"`
+	ReplyToMessage *Message `json:"reply_to_message"`
+}
+
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"` // private, group, supergroup, channel
+}
+
+// Private - личный чат с пользователем. Только в нём chat_id однозначно
+// соответствует одному человеку.
+func (c Chat) Private() bool {
+	return c.Type == "private"
+}
+
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    User     `json:"from"`
+	Message *Message `json:"message"`
+	Data    string   `json:"data"`
+}
+
+// Error - ошибка, которую вернул Bot API
+type Error struct {
+	Code        int
+	Description string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("telegram: %d %s", e.Code, e.Description)
+}
+
+// Client - HTTP-клиент Bot API
+type Client struct {
+	BaseURL string // по умолчанию https://api.telegram.org
+	Token   string
+	HTTP    *http.Client
+}
+
+func NewClient(token, baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = "https://api.telegram.org"
+	}
+	return &Client{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Token:   token,
+		HTTP:    &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/bot%s/%s", c.BaseURL, c.Token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		OK          bool            `json:"ok"`
+		Result      json.RawMessage `json:"result"`
+		ErrorCode   int             `json:"error_code"`
+		Description string          `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("telegram: %s: %w", method, err)
+	}
+	if !envelope.OK {
+		return &Error{Code: envelope.ErrorCode, Description: envelope.Description}
+	}
+	if result != nil {
+		return json.Unmarshal(envelope.Result, result)
+	}
+	return nil
+}
+
+func (c *Client) SendMessage(ctx context.Context, chatID int64, text string, keyboard [][]Button) error {
+	params := map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	}
+	if len(keyboard) > 0 {
+		params["reply_markup"] = map[string]interface{}{"inline_keyboard": keyboard}
+	}
+	return c.call(ctx, "sendMessage", params, nil)
+}
+
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout int) ([]Update, error) {
+	var updates []Update
+	err := c.call(ctx, "getUpdates", map[string]interface{}{
+		"offset":          offset,
+		"timeout":         timeout,
+		"allowed_updates": []string{"message", "callback_query"},
+	}, &updates)
+	return updates, err
+}
+
+func (c *Client) AnswerCallback(ctx context.Context, callbackID, text string) error {
+	return c.call(ctx, "answerCallbackQuery", map[string]interface{}{
+		"callback_query_id": callbackID,
+		"text":              text,
+	}, nil)
+}
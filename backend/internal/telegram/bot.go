@@ -0,0 +1,319 @@
+// internal/telegram/bot.go
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"refurnish/internal/config"
+	"refurnish/internal/models"
+	"refurnish/internal/respond"
+
+	"gorm.io/gorm"
+)
+
+const helpText = `Команды:
+/start <код> — привязать аккаунт ReFurnish (код выдаётся в профиле)
+/project <id> — карточка проекта
+/respond <id> <цена> [комментарий] — откликнуться на проект
+/unlink — отвязать аккаунт
+
+Можно нажать «Откликнуться» под уведомлением или ответить на карточку проекта сообщением «откликнуться с ценой 15000».`
+
+var (
+	uuidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	// Разряды - через пробел, в том числе неразрывный: его подставляют клавиатуры
+	pricePattern = regexp.MustCompile(`\d+(?:[ \x{00A0}\x{202F}]\d{3})*`)
+)
+
+// Bot обрабатывает входящие сообщения и нажатия кнопок (long polling)
+type Bot struct {
+	API API
+	DB  *gorm.DB
+
+	mu sync.Mutex
+	// pending - чат ждёт цену для отклика на проект после кнопки «Откликнуться»
+	pending map[int64]string
+}
+
+func NewBot(db *gorm.DB, api API) *Bot {
+	return &Bot{API: api, DB: db, pending: make(map[int64]string)}
+}
+
+// Run опрашивает getUpdates, пока не отменён ctx
+func (b *Bot) Run(ctx context.Context) {
+	log.Printf("🤖 Telegram-бот запущен")
+
+	var offset int64
+	for ctx.Err() == nil {
+		updates, err := b.API.GetUpdates(ctx, offset, 30)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("⚠️  Telegram: getUpdates: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			b.Handle(ctx, u)
+		}
+	}
+}
+
+// Handle обрабатывает одно обновление
+func (b *Bot) Handle(ctx context.Context, u Update) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("❌ Telegram: обработка обновления %d упала: %v", u.UpdateID, p)
+		}
+	}()
+
+	switch {
+	case u.CallbackQuery != nil:
+		b.handleCallback(ctx, u.CallbackQuery)
+	case u.Message != nil && u.Message.Text != "":
+		b.handleMessage(ctx, u.Message)
+	}
+}
+
+func (b *Bot) reply(ctx context.Context, chatID int64, text string, keyboard [][]Button) {
+	if err := b.API.SendMessage(ctx, chatID, text, keyboard); err != nil {
+		log.Printf("⚠️  Telegram: не удалось ответить в чат %d: %v", chatID, err)
+	}
+}
+
+func (b *Bot) handleMessage(ctx context.Context, m *Message) {
+	chatID := m.Chat.ID
+	text := strings.TrimSpace(m.Text)
+	command, args := splitCommand(text)
+
+	// Аккаунт привязывается к чату: в группе от его имени писали бы все
+	// участники, поэтому там бот не привязывает аккаунты и не откликается
+	if !m.Chat.Private() {
+		if command == "/start" {
+			b.reply(ctx, chatID, "Бот работает только в личном чате. Напишите ему напрямую.", nil)
+		}
+		return
+	}
+
+	switch command {
+	case "/start":
+		if args == "" {
+			b.reply(ctx, chatID, "Здравствуйте! Чтобы получать уведомления, получите код в профиле ReFurnish и отправьте /start <код>.\n\n"+helpText, nil)
+			return
+		}
+		b.link(ctx, m, args)
+		return
+	case "/help":
+		b.reply(ctx, chatID, helpText, nil)
+		return
+	case "/unlink":
+		if userID, ok := UserByChat(b.DB, chatID); ok {
+			Unlink(b.DB, userID)
+		}
+		b.reply(ctx, chatID, "Аккаунт отвязан, уведомления больше не будут приходить.", nil)
+		return
+	case "/project":
+		b.sendCard(ctx, chatID, strings.TrimSpace(args))
+		return
+	case "/respond":
+		parts := strings.SplitN(args, " ", 2)
+		rest := ""
+		if len(parts) == 2 {
+			rest = parts[1]
+		}
+		b.respond(ctx, chatID, strings.TrimSpace(parts[0]), rest)
+		return
+	}
+
+	// Ответ на карточку проекта: «откликнуться с ценой 15000»
+	if m.ReplyToMessage != nil {
+		if projectID := uuidPattern.FindString(m.ReplyToMessage.Text); projectID != "" {
+			b.respond(ctx, chatID, projectID, text)
+			return
+		}
+	}
+
+	// Цена после нажатия «Откликнуться»
+	b.mu.Lock()
+	projectID, ok := b.pending[chatID]
+	delete(b.pending, chatID)
+	b.mu.Unlock()
+	if ok {
+		b.respond(ctx, chatID, projectID, text)
+		return
+	}
+
+	b.reply(ctx, chatID, helpText, nil)
+}
+
+func (b *Bot) handleCallback(ctx context.Context, q *CallbackQuery) {
+	b.API.AnswerCallback(ctx, q.ID, "")
+	if q.Message == nil || !q.Message.Chat.Private() {
+		return
+	}
+	chatID := q.Message.Chat.ID
+
+	action, projectID, _ := strings.Cut(q.Data, ":")
+	switch action {
+	case "project":
+		b.sendCard(ctx, chatID, projectID)
+	case "respond":
+		b.mu.Lock()
+		b.pending[chatID] = projectID
+		b.mu.Unlock()
+		b.reply(ctx, chatID, "Укажите цену в рублях, например: 15000. После цены можно добавить комментарий.", nil)
+	}
+}
+
+func (b *Bot) link(ctx context.Context, m *Message, code string) {
+	username := ""
+	if m.From != nil {
+		username = m.From.Username
+	}
+	_, err := Link(b.DB, strings.ToUpper(strings.TrimSpace(code)), m.Chat.ID, username)
+	if errors.Is(err, ErrInvalidCode) {
+		b.reply(ctx, m.Chat.ID, "Код недействителен или истёк. Получите новый код в профиле.", nil)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Telegram: ошибка привязки: %v", err)
+		b.reply(ctx, m.Chat.ID, "Не удалось привязать аккаунт, попробуйте позже.", nil)
+		return
+	}
+	b.reply(ctx, m.Chat.ID, "✅ Аккаунт привязан. Уведомления о проектах и откликах будут приходить сюда.", nil)
+}
+
+func (b *Bot) sendCard(ctx context.Context, chatID int64, projectID string) {
+	var project models.Project
+	if !uuidPattern.MatchString(projectID) ||
+		b.DB.Preload("Category").First(&project, "id = ?", projectID).Error != nil ||
+		(project.Status != "published" && project.Status != "assigned") ||
+		project.Visibility == "invite_only" && !b.canSeeInviteOnly(chatID, project.ID) {
+		b.reply(ctx, chatID, "Проект не найден или недоступен.", nil)
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📋 %s\n\n", project.Title)
+	if project.Category != nil {
+		fmt.Fprintf(&sb, "Категория: %s\n", project.Category.NameRu)
+	}
+	fmt.Fprintf(&sb, "Город: %s\nБюджет: %d ₽\n", project.City, project.Budget)
+	if !project.Deadline.IsZero() {
+		fmt.Fprintf(&sb, "Срок: %s\n", project.Deadline.Format("02.01.2006"))
+	}
+	if desc := []rune(project.Description); len(desc) > 500 {
+		fmt.Fprintf(&sb, "\n%s…\n", string(desc[:500]))
+	} else if len(desc) > 0 {
+		fmt.Fprintf(&sb, "\n%s\n", project.Description)
+	}
+	fmt.Fprintf(&sb, "\n%s/project/%s\nID проекта: %s", config.PublicURL(), project.ID, project.ID)
+
+	var keyboard [][]Button
+	if project.Status == "published" {
+		keyboard = [][]Button{{{Text: "✋ Откликнуться", Data: "respond:" + project.ID}}}
+	}
+	b.reply(ctx, chatID, sb.String(), keyboard)
+}
+
+func (b *Bot) canSeeInviteOnly(chatID int64, projectID string) bool {
+	userID, ok := UserByChat(b.DB, chatID)
+	if !ok {
+		return false
+	}
+	var count int64
+	b.DB.Model(&models.Invitation{}).
+		Joins("JOIN masters ON masters.id = invitations.master_id").
+		Where("invitations.project_id = ? AND masters.user_id = ?", projectID, userID).
+		Count(&count)
+	return count > 0
+}
+
+// respond создаёт отклик от привязанного мастера; text - цена и комментарий
+func (b *Bot) respond(ctx context.Context, chatID int64, projectID, text string) {
+	userID, ok := UserByChat(b.DB, chatID)
+	if !ok {
+		b.reply(ctx, chatID, "Сначала привяжите аккаунт: /start <код из профиля>.", nil)
+		return
+	}
+	var master models.Master
+	if err := b.DB.First(&master, "user_id = ?", userID).Error; err != nil {
+		b.reply(ctx, chatID, "Откликаться на проекты могут только мастера.", nil)
+		return
+	}
+	if !uuidPattern.MatchString(projectID) {
+		b.reply(ctx, chatID, "Укажите проект: /respond <id> <цена> [комментарий]", nil)
+		return
+	}
+
+	price, comment, ok := parsePrice(text)
+	if !ok {
+		b.reply(ctx, chatID, "Не удалось распознать цену. Пример: 15000 или «откликнуться с ценой 15000».", nil)
+		return
+	}
+
+	_, err := respond.Submit(b.DB, &master, respond.Input{
+		ProjectID: projectID,
+		Comment:   comment,
+		Price:     price,
+		StartDate: time.Now().Truncate(24 * time.Hour),
+	})
+	switch {
+	case errors.Is(err, respond.ErrProjectNotFound):
+		b.reply(ctx, chatID, "Проект не найден или уже не принимает отклики.", nil)
+	case errors.Is(err, respond.ErrProjectExpired):
+		b.reply(ctx, chatID, "Срок публикации проекта истёк.", nil)
+	case errors.Is(err, respond.ErrInviteOnly):
+		b.reply(ctx, chatID, "Этот проект доступен только по приглашению.", nil)
+	case errors.Is(err, respond.ErrAlreadyResponded):
+		b.reply(ctx, chatID, "Вы уже откликнулись на этот проект.", nil)
+	case err != nil:
+		log.Printf("❌ Telegram: ошибка отклика: %v", err)
+		b.reply(ctx, chatID, "Не удалось отправить отклик, попробуйте позже.", nil)
+	default:
+		b.reply(ctx, chatID, fmt.Sprintf("✅ Отклик отправлен: %d ₽.", price), nil)
+	}
+}
+
+// splitCommand - "/start@bot CODE" -> ("/start", "CODE")
+func splitCommand(text string) (string, string) {
+	if !strings.HasPrefix(text, "/") {
+		return "", text
+	}
+	command, args, _ := strings.Cut(text, " ")
+	command, _, _ = strings.Cut(command, "@")
+	return strings.ToLower(command), strings.TrimSpace(args)
+}
+
+// parsePrice - первая сумма в тексте («15 000», «15000 руб»), остальное - комментарий
+func parsePrice(text string) (int, string, bool) {
+	loc := pricePattern.FindStringIndex(text)
+	if loc == nil {
+		return 0, "", false
+	}
+	digits := strings.Join(strings.Fields(text[loc[0]:loc[1]]), "")
+	price, err := strconv.Atoi(digits)
+	if err != nil || price <= 0 {
+		return 0, "", false
+	}
+
+	comment := strings.TrimSpace(text[loc[1]:])
+	for _, prefix := range []string{"руб.", "руб", "₽", "р.", ","} {
+		comment = strings.TrimSpace(strings.TrimPrefix(comment, prefix))
+	}
+	return price, comment, true
+}
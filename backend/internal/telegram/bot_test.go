@@ -0,0 +1,319 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"refurnish/internal/models"
+	"refurnish/internal/testdb"
+
+	"gorm.io/gorm"
+)
+
+const testToken = "123456:TEST"
+
+// fakeTelegram - локальный Bot API: запоминает отправленные сообщения
+// и ответы на нажатия кнопок
+type fakeTelegram struct {
+	mu       sync.Mutex
+	sent     []sentMessage
+	answered []string
+	server   *httptest.Server
+}
+
+type sentMessage struct {
+	ChatID   int64
+	Text     string
+	Keyboard [][]Button
+}
+
+func newFakeTelegram(t *testing.T) *fakeTelegram {
+	fake := &fakeTelegram{}
+	fake.server = httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(fake.server.Close)
+	return fake
+}
+
+func (f *fakeTelegram) serve(w http.ResponseWriter, r *http.Request) {
+	prefix := "/bot" + testToken + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "error_code": 401, "description": "Unauthorized"})
+		return
+	}
+
+	var params struct {
+		ChatID          int64  `json:"chat_id"`
+		Text            string `json:"text"`
+		CallbackQueryID string `json:"callback_query_id"`
+		ReplyMarkup     struct {
+			InlineKeyboard [][]Button `json:"inline_keyboard"`
+		} `json:"reply_markup"`
+	}
+	json.NewDecoder(r.Body).Decode(&params)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch strings.TrimPrefix(r.URL.Path, prefix) {
+	case "sendMessage":
+		f.sent = append(f.sent, sentMessage{ChatID: params.ChatID, Text: params.Text, Keyboard: params.ReplyMarkup.InlineKeyboard})
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "result": map[string]interface{}{"message_id": len(f.sent)}})
+	case "answerCallbackQuery":
+		f.answered = append(f.answered, params.CallbackQueryID)
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "result": true})
+	default:
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "error_code": 404, "description": "Not Found"})
+	}
+}
+
+// last - последнее сообщение, отправленное в чат
+func (f *fakeTelegram) last(t *testing.T, chatID int64) sentMessage {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := len(f.sent) - 1; i >= 0; i-- {
+		if f.sent[i].ChatID == chatID {
+			return f.sent[i]
+		}
+	}
+	t.Fatalf("в чат %d ничего не отправлено", chatID)
+	return sentMessage{}
+}
+
+type botFixture struct {
+	db   *gorm.DB
+	bot  *Bot
+	fake *fakeTelegram
+}
+
+func newBotFixture(t *testing.T) *botFixture {
+	db := testdb.Open(t)
+	fake := newFakeTelegram(t)
+	return &botFixture{db: db, fake: fake, bot: NewBot(db, NewClient(testToken, fake.server.URL))}
+}
+
+// linkedMaster - мастер, чат которого уже привязан к аккаунту
+func (f *botFixture) linkedMaster(t *testing.T, chatID int64) string {
+	t.Helper()
+	masterID, userID := testdb.Master(t, f.db)
+	code, err := NewLinkCode(f.db, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Link(f.db, code.Code, chatID, "ivan"); err != nil {
+		t.Fatal(err)
+	}
+	return masterID
+}
+
+func (f *botFixture) publishedProject(t *testing.T) string {
+	t.Helper()
+	clientID, _ := testdb.Client(t, f.db)
+	return testdb.Project(t, f.db, clientID, nil)
+}
+
+func (f *botFixture) message(chatID int64, text string) {
+	f.bot.Handle(context.Background(), Update{Message: &Message{
+		Chat: Chat{ID: chatID, Type: "private"},
+		From: &User{ID: chatID, Username: "user"},
+		Text: text,
+	}})
+}
+
+func (f *botFixture) response(t *testing.T, projectID, masterID string) models.Response {
+	t.Helper()
+	var response models.Response
+	if err := f.db.First(&response, "project_id = ? AND master_id = ?", projectID, masterID).Error; err != nil {
+		t.Fatalf("отклик не создан: %v", err)
+	}
+	return response
+}
+
+func TestStartLinksAccount(t *testing.T) {
+	f := newBotFixture(t)
+	userID := testdb.User(t, f.db, "client")
+	code, err := NewLinkCode(f.db, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Код можно ввести в любом регистре
+	f.message(100, "/start "+strings.ToLower(code.Code))
+	if got := f.fake.last(t, 100).Text; !strings.Contains(got, "Аккаунт привязан") {
+		t.Fatalf("ответ на /start: %q", got)
+	}
+	if linked, ok := UserByChat(f.db, 100); !ok || linked != userID {
+		t.Fatalf("чат привязан к %q, want %q", linked, userID)
+	}
+
+	// Повторно код не принимается
+	f.message(200, "/start@refurnish_bot "+code.Code)
+	if got := f.fake.last(t, 200).Text; !strings.Contains(got, "недействителен") {
+		t.Errorf("повторный код: %q", got)
+	}
+	if _, ok := UserByChat(f.db, 200); ok {
+		t.Error("повторный код привязал второй чат")
+	}
+}
+
+func TestStartRejectsExpiredCode(t *testing.T) {
+	f := newBotFixture(t)
+	userID := testdb.User(t, f.db, "client")
+	code, err := NewLinkCode(f.db, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.db.Exec("UPDATE telegram_link_codes SET expires_at = now() - interval '1 minute' WHERE code = ?", code.Code)
+
+	f.message(100, "/start "+code.Code)
+
+	if got := f.fake.last(t, 100).Text; !strings.Contains(got, "недействителен или истёк") {
+		t.Errorf("ответ на просроченный код: %q", got)
+	}
+	if _, ok := UserByChat(f.db, 100); ok {
+		t.Error("просроченный код привязал чат")
+	}
+}
+
+// В группе chat_id общий для всех участников: привязка и отклики там запрещены
+func TestGroupChatIgnored(t *testing.T) {
+	f := newBotFixture(t)
+	userID := testdb.User(t, f.db, "master")
+	code, err := NewLinkCode(f.db, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	group := Chat{ID: -500, Type: "group"}
+
+	f.bot.Handle(context.Background(), Update{Message: &Message{Chat: group, From: &User{ID: 501}, Text: "/start " + code.Code}})
+	if got := f.fake.last(t, -500).Text; !strings.Contains(got, "личном чате") {
+		t.Errorf("ответ на /start в группе: %q", got)
+	}
+	if _, ok := UserByChat(f.db, -500); ok {
+		t.Fatal("группа привязана к аккаунту")
+	}
+
+	// Чат, привязанный раньше, из группы тоже не откликается
+	masterID := f.linkedMaster(t, -600)
+	projectID := f.publishedProject(t)
+	f.bot.Handle(context.Background(), Update{Message: &Message{
+		Chat: Chat{ID: -600, Type: "supergroup"},
+		From: &User{ID: 601},
+		Text: "/respond " + projectID + " 15000",
+	}})
+	var count int64
+	f.db.Model(&models.Response{}).Where("project_id = ? AND master_id = ?", projectID, masterID).Count(&count)
+	if count != 0 {
+		t.Error("отклик из группового чата")
+	}
+}
+
+func TestRespondButtonThenPrice(t *testing.T) {
+	f := newBotFixture(t)
+	masterID := f.linkedMaster(t, 300)
+	projectID := f.publishedProject(t)
+
+	f.bot.Handle(context.Background(), Update{CallbackQuery: &CallbackQuery{
+		ID:      "cb-1",
+		From:    User{ID: 300},
+		Message: &Message{Chat: Chat{ID: 300, Type: "private"}},
+		Data:    "respond:" + projectID,
+	}})
+
+	f.fake.mu.Lock()
+	answered := strings.Join(f.fake.answered, ",")
+	f.fake.mu.Unlock()
+	if answered != "cb-1" {
+		t.Errorf("answerCallbackQuery: %q", answered)
+	}
+	if got := f.fake.last(t, 300).Text; !strings.Contains(got, "Укажите цену") {
+		t.Fatalf("после кнопки: %q", got)
+	}
+
+	f.message(300, "15 000 руб, начну в понедельник")
+
+	if got := f.fake.last(t, 300).Text; got != "✅ Отклик отправлен: 15000 ₽." {
+		t.Errorf("после цены: %q", got)
+	}
+	response := f.response(t, projectID, masterID)
+	if response.Price != 15000 || response.Comment != "начну в понедельник" {
+		t.Errorf("отклик: price=%d comment=%q", response.Price, response.Comment)
+	}
+
+	// Ожидание цены одноразовое: следующее сообщение - не отклик
+	f.message(300, "20000")
+	if got := f.fake.last(t, 300).Text; got != helpText {
+		t.Errorf("второе сообщение: %q", got)
+	}
+}
+
+func TestReplyToProjectCard(t *testing.T) {
+	f := newBotFixture(t)
+	masterID := f.linkedMaster(t, 400)
+	projectID := f.publishedProject(t)
+
+	f.message(400, "/project "+projectID)
+	card := f.fake.last(t, 400)
+	if !strings.Contains(card.Text, "Шкаф-купе") || !strings.Contains(card.Text, projectID) {
+		t.Fatalf("карточка проекта: %q", card.Text)
+	}
+	if len(card.Keyboard) != 1 || card.Keyboard[0][0].Data != "respond:"+projectID {
+		t.Errorf("кнопки карточки: %+v", card.Keyboard)
+	}
+
+	reply := func(text string) {
+		f.bot.Handle(context.Background(), Update{Message: &Message{
+			Chat:           Chat{ID: 400, Type: "private"},
+			From:           &User{ID: 400},
+			Text:           text,
+			ReplyToMessage: &Message{Chat: Chat{ID: 400, Type: "private"}, Text: card.Text},
+		}})
+	}
+
+	reply("откликнуться с ценой 15 000")
+	if got := f.fake.last(t, 400).Text; got != "✅ Отклик отправлен: 15000 ₽." {
+		t.Errorf("ответ на карточку: %q", got)
+	}
+	if response := f.response(t, projectID, masterID); response.Price != 15000 || response.Comment != "" {
+		t.Errorf("отклик: price=%d comment=%q", response.Price, response.Comment)
+	}
+
+	reply("откликнуться с ценой 12 000")
+	if got := f.fake.last(t, 400).Text; !strings.Contains(got, "уже откликнулись") {
+		t.Errorf("повторный отклик: %q", got)
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		text    string
+		price   int
+		comment string
+		ok      bool
+	}{
+		{"15000", 15000, "", true},
+		{"15 000", 15000, "", true},
+		{"15\u00a0000 ₽", 15000, "", true},
+		{"15\u202f000", 15000, "", true},
+		{"1 250 000 руб. под ключ", 1250000, "под ключ", true},
+		{"откликнуться с ценой 15 000", 15000, "", true},
+		{"15000р. сделаю за неделю", 15000, "сделаю за неделю", true},
+		{"15000₽, материалы мои", 15000, "материалы мои", true},
+		{"15 00", 15, "00", true},
+		{"0", 0, "", false},
+		{"бесплатно", 0, "", false},
+		{"", 0, "", false},
+		{"99999999999999999999", 0, "", false},
+	}
+	for _, tt := range tests {
+		price, comment, ok := parsePrice(tt.text)
+		if price != tt.price || comment != tt.comment || ok != tt.ok {
+			t.Errorf("parsePrice(%q) = %d, %q, %v; want %d, %q, %v",
+				tt.text, price, comment, ok, tt.price, tt.comment, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// internal/telegram/link.go
+package telegram
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"time"
+
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CodeTTL - сколько действует код привязки
+const CodeTTL = 15 * time.Minute
+
+var ErrInvalidCode = errors.New("код недействителен или истёк")
+
+// codeAlphabet без похожих символов (0/O, 1/I)
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// NewLinkCode выдаёт одноразовый код; прежние неиспользованные коды пользователя сгорают
+func NewLinkCode(db *gorm.DB, userID string) (*models.TelegramLinkCode, error) {
+	code := make([]byte, 8)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(codeAlphabet))))
+		if err != nil {
+			return nil, err
+		}
+		code[i] = codeAlphabet[n.Int64()]
+	}
+
+	linkCode := models.TelegramLinkCode{
+		Code:      string(code),
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(CodeTTL),
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND used_at IS NULL", userID).Delete(&models.TelegramLinkCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&linkCode).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &linkCode, nil
+}
+
+// Link погашает код и привязывает чат к пользователю. Чат, привязанный
+// к другому аккаунту, перепривязывается.
+func Link(db *gorm.DB, code string, chatID int64, username string) (string, error) {
+	var userID string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var linkCode models.TelegramLinkCode
+		result := tx.Raw(`
+			UPDATE telegram_link_codes SET used_at = now()
+			WHERE code = ? AND used_at IS NULL AND expires_at > now()
+			RETURNING *
+		`, code).Scan(&linkCode)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidCode
+		}
+		userID = linkCode.UserID
+
+		if err := tx.Where("chat_id = ? AND user_id <> ?", chatID, userID).Delete(&models.TelegramLink{}).Error; err != nil {
+			return err
+		}
+		link := models.TelegramLink{UserID: userID, ChatID: chatID, Username: username, LinkedAt: time.Now()}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"chat_id", "username", "linked_at"}),
+		}).Create(&link).Error
+	})
+	return userID, err
+}
+
+// Unlink отвязывает чат
+func Unlink(db *gorm.DB, userID string) error {
+	return db.Where("user_id = ?", userID).Delete(&models.TelegramLink{}).Error
+}
+
+// UserByChat - пользователь, привязанный к чату
+func UserByChat(db *gorm.DB, chatID int64) (string, bool) {
+	var link models.TelegramLink
+	if err := db.First(&link, "chat_id = ?", chatID).Error; err != nil {
+		return "", false
+	}
+	return link.UserID, true
+}
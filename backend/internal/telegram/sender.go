@@ -0,0 +1,71 @@
+// internal/telegram/sender.go
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"refurnish/internal/models"
+	"refurnish/internal/notify"
+
+	"gorm.io/gorm"
+)
+
+// Sender - канал уведомлений notify.Telegram
+type Sender struct {
+	API API
+	DB  *gorm.DB
+}
+
+func NewSender(db *gorm.DB, api API) *Sender {
+	return &Sender{API: api, DB: db}
+}
+
+func (s *Sender) Address(db *gorm.DB, userID string) (string, bool) {
+	var link models.TelegramLink
+	if err := db.First(&link, "user_id = ?", userID).Error; err != nil {
+		return "", false
+	}
+	return strconv.FormatInt(link.ChatID, 10), true
+}
+
+func (s *Sender) Deliver(ctx context.Context, address string, m notify.Message) error {
+	chatID, err := strconv.ParseInt(address, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: неверный chat id", notify.ErrPermanent)
+	}
+
+	err = s.API.SendMessage(ctx, chatID, m.Title+"\n\n"+m.Body, keyboardFor(m))
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) && (apiErr.Code == 400 || apiErr.Code == 403) {
+		// Бот заблокирован или чат удалён - повторять бессмысленно
+		if apiErr.Code == 403 {
+			s.DB.Where("chat_id = ?", chatID).Delete(&models.TelegramLink{})
+		}
+		return fmt.Errorf("%w: %s", notify.ErrPermanent, apiErr.Error())
+	}
+	return err
+}
+
+// keyboardFor - кнопки под уведомлением: карточка проекта и быстрый отклик
+func keyboardFor(m notify.Message) [][]Button {
+	projectID, _ := m.Data["projectId"].(string)
+	if projectID == "" {
+		return nil
+	}
+
+	switch m.Kind {
+	case notify.NewProjectAlert, notify.ProjectInvitation:
+		return [][]Button{{
+			{Text: "📋 Открыть проект", Data: "project:" + projectID},
+			{Text: "✋ Откликнуться", Data: "respond:" + projectID},
+		}}
+	default:
+		return [][]Button{{
+			{Text: "📋 Открыть проект", Data: "project:" + projectID},
+		}}
+	}
+}
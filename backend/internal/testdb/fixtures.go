@@ -0,0 +1,80 @@
+// internal/testdb/fixtures.go
+package testdb
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// Фабрики тестовых данных. Пишут напрямую в таблицы, минуя обработчики,
+// и возвращают id созданной записи.
+
+// Insert выполняет INSERT ... RETURNING id и возвращает id
+func Insert(t testing.TB, db *gorm.DB, query string, args ...interface{}) string {
+	t.Helper()
+	var id string
+	if err := db.Raw(query, args...).Scan(&id).Error; err != nil {
+		t.Fatalf("%s: %v", strings.TrimSpace(query), err)
+	}
+	return id
+}
+
+// User - пользователь с ролью role и уникальным email
+func User(t testing.TB, db *gorm.DB, role string) string {
+	t.Helper()
+	return Insert(t, db, "INSERT INTO users (email, password, role) VALUES (?, 'x', ?) RETURNING id",
+		role+"-"+randomSuffix()+"@example.com", role)
+}
+
+// Client - профиль клиента с новым пользователем. Возвращает id клиента и пользователя.
+func Client(t testing.TB, db *gorm.DB) (clientID, userID string) {
+	t.Helper()
+	userID = User(t, db, "client")
+	clientID = Insert(t, db, "INSERT INTO clients (user_id, name) VALUES (?, 'Анна') RETURNING id", userID)
+	return clientID, userID
+}
+
+// Master - профиль мастера из Москвы с новым пользователем.
+// Возвращает id мастера и пользователя.
+func Master(t testing.TB, db *gorm.DB) (masterID, userID string) {
+	t.Helper()
+	userID = User(t, db, "master")
+	masterID = Insert(t, db, "INSERT INTO masters (user_id, name, city) VALUES (?, 'Иван', 'Москва') RETURNING id", userID)
+	return masterID, userID
+}
+
+// Project - проект клиента. По умолчанию опубликованный шкаф в Москве
+// со сроком через месяц; columns дополняют или заменяют эти значения.
+func Project(t testing.TB, db *gorm.DB, clientID string, columns map[string]interface{}) string {
+	t.Helper()
+	values := map[string]interface{}{
+		"client_id":    clientID,
+		"title":        "Шкаф-купе",
+		"description":  "Шкаф в прихожую",
+		"budget":       40000,
+		"deadline":     gorm.Expr("now() + interval '1 month'"),
+		"city":         "Москва",
+		"status":       "published",
+		"published_at": gorm.Expr("now()"),
+	}
+	for column, value := range columns {
+		values[column] = value
+	}
+
+	names := make([]string, 0, len(values))
+	for column := range values {
+		names = append(names, column)
+	}
+	sort.Strings(names)
+	args := make([]interface{}, 0, len(names))
+	for _, column := range names {
+		args = append(args, values[column])
+	}
+
+	query := "INSERT INTO projects (" + strings.Join(names, ", ") + ") VALUES (" +
+		strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ") + ") RETURNING id"
+	return Insert(t, db, query, args...)
+}
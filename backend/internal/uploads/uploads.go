@@ -0,0 +1,208 @@
+// internal/uploads/uploads.go
+package uploads
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// URLPrefix - префикс, под которым файлы раздаются сервером
+const URLPrefix = "/uploads/"
+
+// MaxImageSize - максимальный размер загружаемого изображения
+const MaxImageSize = 10 << 20
+
+var (
+	ErrNoFile          = errors.New("файл не передан")
+	ErrTooLarge        = errors.New("файл слишком большой (максимум 10 МБ)")
+	ErrUnsupportedType = errors.New("неподдерживаемый тип файла")
+)
+
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// Вложения в переписке: изображения и PDF
+var attachmentExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// File - сохранённый файл
+type File struct {
+	URL         string
+	Name        string // исходное имя файла
+	ContentType string
+	Size        int64
+}
+
+// Dir возвращает каталог для загруженных файлов (UPLOAD_DIR, по умолчанию ./uploads)
+func Dir() string {
+	if dir := os.Getenv("UPLOAD_DIR"); dir != "" {
+		return dir
+	}
+	return "uploads"
+}
+
+// PrivateDir - каталог для документов, которые не раздаются публично
+// (PRIVATE_UPLOAD_DIR, по умолчанию ./uploads-private)
+func PrivateDir() string {
+	if dir := os.Getenv("PRIVATE_UPLOAD_DIR"); dir != "" {
+		return dir
+	}
+	return "uploads-private"
+}
+
+// Handler раздаёт загруженные файлы. Каталоги не отдаются: список
+// файлов в /uploads/ позволил бы перебрать чужие загрузки
+func Handler() http.Handler {
+	return http.StripPrefix(URLPrefix, http.FileServer(filesOnly{http.Dir(Dir())}))
+}
+
+// filesOnly - файловая система без каталогов: на них отвечает 404
+type filesOnly struct {
+	fs http.FileSystem
+}
+
+func (f filesOnly) Open(name string) (http.File, error) {
+	file, err := f.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		file.Close()
+		return nil, os.ErrNotExist
+	}
+	return file, nil
+}
+
+// SaveImage сохраняет изображение из multipart-поля field и возвращает его URL
+func SaveImage(w http.ResponseWriter, r *http.Request, field string) (string, error) {
+	f, err := save(w, r, field, imageExtensions, Dir(), URLPrefix)
+	if err != nil {
+		return "", err
+	}
+	return f.URL, nil
+}
+
+// SavePrivate сохраняет изображение или PDF в PrivateDir. File.URL - имя
+// файла без префикса, отдавать его можно только через OpenPrivate
+func SavePrivate(w http.ResponseWriter, r *http.Request, field string) (*File, error) {
+	return save(w, r, field, attachmentExtensions, PrivateDir(), "")
+}
+
+// OpenPrivate открывает файл, сохранённый SavePrivate
+func OpenPrivate(name string) (*os.File, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, os.ErrNotExist
+	}
+	return os.Open(filepath.Join(PrivateDir(), name))
+}
+
+// RemovePrivate удаляет файл, сохранённый SavePrivate
+func RemovePrivate(name string) error {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil
+	}
+	return os.Remove(filepath.Join(PrivateDir(), name))
+}
+
+func save(w http.ResponseWriter, r *http.Request, field string, allowed map[string]string, dir, prefix string) (*File, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxImageSize+1<<20)
+
+	file, header, err := r.FormFile(field)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, ErrTooLarge
+		}
+		return nil, ErrNoFile
+	}
+	defer file.Close()
+
+	if header.Size > MaxImageSize {
+		return nil, ErrTooLarge
+	}
+
+	// Тип определяем по содержимому, а не по имени файла
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	contentType := http.DetectContentType(head[:n])
+	ext, ok := allowed[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	name += ext
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	dst, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, file); err != nil {
+		os.Remove(dst.Name())
+		return nil, err
+	}
+
+	return &File{
+		URL:         prefix + name,
+		Name:        filepath.Base(header.Filename),
+		ContentType: contentType,
+		Size:        header.Size,
+	}, nil
+}
+
+// IsClientError - ошибка из-за неверного файла, а не сбоя сервера
+func IsClientError(err error) bool {
+	return errors.Is(err, ErrNoFile) || errors.Is(err, ErrTooLarge) || errors.Is(err, ErrUnsupportedType)
+}
+
+// Open открывает публичный файл по его URL
+func Open(url string) (*os.File, error) {
+	name := strings.TrimPrefix(url, URLPrefix)
+	if name == url || name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, os.ErrNotExist
+	}
+	return os.Open(filepath.Join(Dir(), name))
+}
+
+// Remove удаляет ранее загруженный файл по его URL
+func Remove(url string) error {
+	name := strings.TrimPrefix(url, URLPrefix)
+	if name == url || name == "" || strings.ContainsAny(name, `/\`) {
+		return nil
+	}
+	return os.Remove(filepath.Join(Dir(), name))
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,64 @@
+// internal/verification/checksum.go
+package verification
+
+// Контрольные числа ИНН и ОГРНИП проверяются без обращения к ФНС:
+// это отсекает опечатки и выдуманные номера, но не подтверждает,
+// что номер принадлежит мастеру - для этого модератор сверяет документы.
+
+var (
+	inn10Weights  = []int{2, 4, 10, 3, 5, 9, 4, 6, 8}
+	inn12Weights1 = []int{7, 2, 4, 10, 3, 5, 9, 4, 6, 8}
+	inn12Weights2 = []int{3, 7, 2, 4, 10, 3, 5, 9, 4, 6, 8}
+)
+
+// ValidINN проверяет ИНН организации (10 цифр) или физлица/ИП (12 цифр)
+func ValidINN(inn string) bool {
+	digits, ok := parseDigits(inn)
+	if !ok {
+		return false
+	}
+	switch len(digits) {
+	case 10:
+		return innControl(digits, inn10Weights) == digits[9]
+	case 12:
+		return innControl(digits, inn12Weights1) == digits[10] &&
+			innControl(digits, inn12Weights2) == digits[11]
+	}
+	return false
+}
+
+// ValidOGRNIP проверяет ОГРНИП: 15 цифр, контрольная - остаток от деления
+// первых 14 цифр на 13 (последняя цифра остатка)
+func ValidOGRNIP(ogrnip string) bool {
+	digits, ok := parseDigits(ogrnip)
+	if !ok || len(digits) != 15 {
+		return false
+	}
+	var n int64
+	for _, d := range digits[:14] {
+		n = n*10 + int64(d)
+	}
+	return int(n%13%10) == digits[14]
+}
+
+func innControl(digits, weights []int) int {
+	sum := 0
+	for i, w := range weights {
+		sum += digits[i] * w
+	}
+	return sum % 11 % 10
+}
+
+func parseDigits(s string) ([]int, bool) {
+	if s == "" {
+		return nil, false
+	}
+	digits := make([]int, 0, len(s))
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return nil, false
+		}
+		digits = append(digits, int(r-'0'))
+	}
+	return digits, true
+}
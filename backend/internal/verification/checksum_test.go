@@ -0,0 +1,71 @@
+package verification
+
+import (
+	"errors"
+	"testing"
+
+	"refurnish/internal/models"
+)
+
+func TestValidINN(t *testing.T) {
+	tests := []struct {
+		inn  string
+		want bool
+	}{
+		{"7707083893", true},    // организация
+		{"500100732259", true},  // физлицо
+		{"7707083894", false},   // неверная контрольная цифра
+		{"500100732258", false}, // неверная вторая контрольная
+		{"500100732269", false}, // неверная первая контрольная
+		{"770708389", false},
+		{"77070838930", false},
+		{"77070838 93", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := ValidINN(tt.inn); got != tt.want {
+			t.Errorf("ValidINN(%q) = %v, want %v", tt.inn, got, tt.want)
+		}
+	}
+}
+
+func TestValidOGRNIP(t *testing.T) {
+	tests := []struct {
+		ogrnip string
+		want   bool
+	}{
+		{"304500116000157", true},
+		{"304500116000158", false},
+		{"30450011600015", false},
+		{"1027700132195", false}, // ОГРН организации, 13 цифр
+		{"30450011600015a", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := ValidOGRNIP(tt.ogrnip); got != tt.want {
+			t.Errorf("ValidOGRNIP(%q) = %v, want %v", tt.ogrnip, got, tt.want)
+		}
+	}
+}
+
+func TestCheckSubmission(t *testing.T) {
+	docs := []models.VerificationDocument{{Kind: DocPassportSelfie}, {Kind: DocSelfEmployed}}
+	tests := []struct {
+		name, inn, ogrnip string
+		docs              []models.VerificationDocument
+		want              error
+	}{
+		{"самозанятый", "500100732259", "", docs, nil},
+		{"ИП", "500100732259", "304500116000157", docs, nil},
+		{"ИНН организации", "7707083893", "", docs, ErrCompanyINN},
+		{"опечатка в ИНН", "500100732258", "", docs, ErrInvalidINN},
+		{"опечатка в ОГРНИП", "500100732259", "304500116000158", docs, ErrInvalidOGRNIP},
+		{"без селфи", "500100732259", "", docs[1:], ErrNoSelfie},
+		{"без налогового документа", "500100732259", "", docs[:1], ErrNoTaxDocument},
+	}
+	for _, tt := range tests {
+		if got := CheckSubmission(tt.inn, tt.ogrnip, tt.docs); !errors.Is(got, tt.want) {
+			t.Errorf("%s: CheckSubmission = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
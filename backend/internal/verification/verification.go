@@ -0,0 +1,109 @@
+// internal/verification/verification.go
+package verification
+
+import (
+	"errors"
+	"time"
+
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Статусы заявки
+const (
+	StatusDraft    = "draft"
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// Виды документов
+const (
+	DocPassportSelfie    = "passport_selfie"           // селфи с паспортом
+	DocINNCertificate    = "inn_certificate"           // свидетельство ИНН
+	DocSelfEmployed      = "self_employed_certificate" // справка о постановке на учёт самозанятого
+	DocOGRNIPCertificate = "ogrnip_certificate"        // лист записи ЕГРИП
+)
+
+// DocumentKinds - допустимые виды документов
+var DocumentKinds = map[string]bool{
+	DocPassportSelfie:    true,
+	DocINNCertificate:    true,
+	DocSelfEmployed:      true,
+	DocOGRNIPCertificate: true,
+}
+
+// MaxDocuments - ограничение числа файлов в заявке
+const MaxDocuments = 10
+
+var (
+	ErrNoSelfie      = errors.New("нужно селфи с паспортом")
+	ErrNoTaxDocument = errors.New("нужна справка самозанятого, свидетельство ИНН или лист записи ЕГРИП")
+	ErrInvalidINN    = errors.New("неверный ИНН: не сходится контрольное число")
+	ErrCompanyINN    = errors.New("это ИНН организации: укажите личный ИНН из 12 цифр")
+	ErrInvalidOGRNIP = errors.New("неверный ОГРНИП: не сходится контрольное число")
+)
+
+// CheckSubmission проверяет заявку перед отправкой модератору
+func CheckSubmission(inn, ogrnip string, documents []models.VerificationDocument) error {
+	if !ValidINN(inn) {
+		return ErrInvalidINN
+	}
+	// Мастер - самозанятый или ИП, у обоих ИНН физлица
+	if len(inn) != 12 {
+		return ErrCompanyINN
+	}
+	if ogrnip != "" && !ValidOGRNIP(ogrnip) {
+		return ErrInvalidOGRNIP
+	}
+
+	kinds := map[string]bool{}
+	for _, d := range documents {
+		kinds[d.Kind] = true
+	}
+	if !kinds[DocPassportSelfie] {
+		return ErrNoSelfie
+	}
+	if !kinds[DocINNCertificate] && !kinds[DocSelfEmployed] && !kinds[DocOGRNIPCertificate] {
+		return ErrNoTaxDocument
+	}
+	return nil
+}
+
+// Approve отмечает мастера проверенным и переносит в профиль ИНН и ОГРНИП
+func Approve(tx *gorm.DB, request *models.VerificationRequest, adminID string) error {
+	now := time.Now()
+	if err := tx.Model(request).Updates(map[string]interface{}{
+		"status":      StatusApproved,
+		"reviewed_by": adminID,
+		"reviewed_at": now,
+		"reason":      nil,
+	}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.Master{}).Where("id = ?", request.MasterID).Updates(map[string]interface{}{
+		"verified_at": now,
+		"inn":         request.INN,
+		"ogrnip":      request.OGRNIP,
+	}).Error
+}
+
+// Reject отклоняет заявку с причиной. Отметка о проверке, если была, снимается
+func Reject(tx *gorm.DB, request *models.VerificationRequest, adminID, reason string) error {
+	if err := tx.Model(request).Updates(map[string]interface{}{
+		"status":      StatusRejected,
+		"reviewed_by": adminID,
+		"reviewed_at": time.Now(),
+		"reason":      reason,
+	}).Error; err != nil {
+		return err
+	}
+	return Reset(tx, request.MasterID)
+}
+
+// Reset снимает отметку о проверке - например, после смены имени в профиле.
+// Мастер должен подать заявку заново.
+func Reset(tx *gorm.DB, masterID string) error {
+	return tx.Model(&models.Master{}).Where("id = ?", masterID).Update("verified_at", nil).Error
+}
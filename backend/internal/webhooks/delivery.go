@@ -0,0 +1,245 @@
+// internal/webhooks/delivery.go
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxAttempts - после стольких неудач доставка помечается failed
+	MaxAttempts = 8
+	// DisableAfter - подряд неудачных попыток, после которых вебхук отключается
+	DisableAfter = 20
+
+	requestTimeout  = 10 * time.Second
+	maxResponseBody = 2048
+	batchSize       = 50
+	workers         = 10
+	pollEvery       = 10 * time.Second
+)
+
+// lease - на это время забранные доставки скрываются от других экземпляров.
+// Пачка уходит в workers потоков, каждый запрос не дольше requestTimeout;
+// запас - на запросы к базе. Если экземпляр упал, доставки вернутся в очередь
+// по истечении lease.
+var lease = time.Duration((batchSize+workers-1)/workers)*requestTimeout + time.Minute
+
+// Client - HTTP-клиент для доставки; можно подменить. Соединяется
+// только с внешними адресами (см. guard.go), прокси из окружения не использует
+var Client = &http.Client{
+	Timeout: requestTimeout,
+	Transport: &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+		MaxIdleConnsPerHost: 2,
+		IdleConnTimeout:     90 * time.Second,
+	},
+	// Редиректы не выполняем: подпись относится к исходному URL
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+var kick = make(chan struct{}, 1)
+
+// Wake будит доставщик, не дожидаясь очередного опроса
+func Wake() {
+	select {
+	case kick <- struct{}{}:
+	default:
+	}
+}
+
+// Run доставляет ожидающие вебхуки, пока не отменён ctx
+func Run(ctx context.Context, db *gorm.DB) {
+	log.Printf("🪝 Доставка вебхуков запущена")
+
+	ticker := time.NewTicker(pollEvery)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := DeliverBatch(ctx, db)
+			if err != nil {
+				log.Printf("❌ Вебхуки: %v", err)
+				break
+			}
+			if n < batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-kick:
+		}
+	}
+}
+
+// DeliverBatch забирает пачку доставок, у которых наступило время попытки
+func DeliverBatch(ctx context.Context, db *gorm.DB) (int, error) {
+	var due []models.WebhookDelivery
+	err := db.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries
+		SET next_attempt_at = now() + ? * interval '1 second'
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, int(lease.Seconds()), batchSize).Scan(&due).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	for _, d := range due {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(d models.WebhookDelivery) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			attempt(ctx, db, d)
+		}(d)
+	}
+	wg.Wait()
+	return len(due), nil
+}
+
+func attempt(ctx context.Context, db *gorm.DB, d models.WebhookDelivery) {
+	var hook models.Webhook
+	if err := db.First(&hook, "id = ?", d.WebhookID).Error; err != nil {
+		return
+	}
+	if !hook.IsActive {
+		db.Model(&d).Updates(map[string]interface{}{
+			"status":     "failed",
+			"last_error": "вебхук отключён",
+		})
+		return
+	}
+
+	status, body, err := post(ctx, hook, d)
+
+	attempts := d.Attempts + 1
+	updates := map[string]interface{}{"attempts": attempts}
+	if status != 0 {
+		updates["response_status"] = status
+		updates["response_body"] = body
+	}
+
+	if err == nil {
+		updates["status"] = "success"
+		updates["delivered_at"] = time.Now()
+		updates["last_error"] = nil
+		db.Model(&d).Updates(updates)
+		if hook.ConsecutiveFailures > 0 {
+			db.Model(&hook).Update("consecutive_failures", 0)
+		}
+		return
+	}
+
+	updates["last_error"] = err.Error()
+	if attempts >= MaxAttempts {
+		updates["status"] = "failed"
+	} else {
+		updates["next_attempt_at"] = time.Now().Add(backoff(attempts))
+	}
+	db.Model(&d).Updates(updates)
+	log.Printf("⚠️  Вебхук %s, доставка %s, попытка %d: %v", hook.ID, d.ID, attempts, err)
+
+	recordFailure(db, hook)
+}
+
+func post(ctx context.Context, hook models.Webhook, d models.WebhookDelivery) (int, string, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	body := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ReFurnish-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, d.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, timestamp, body))
+
+	resp, err := Client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(raw), fmt.Errorf("получатель ответил %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(raw), nil
+}
+
+// recordFailure считает неудачи подряд и отключает вебхук после DisableAfter
+func recordFailure(db *gorm.DB, hook models.Webhook) {
+	var failures int
+	db.Raw(`
+		UPDATE webhooks SET consecutive_failures = consecutive_failures + 1
+		WHERE id = ? RETURNING consecutive_failures
+	`, hook.ID).Scan(&failures)
+	if failures < DisableAfter {
+		return
+	}
+
+	reason := fmt.Sprintf("%d неудачных доставок подряд", failures)
+	result := db.Model(&models.Webhook{}).Where("id = ? AND is_active", hook.ID).Updates(map[string]interface{}{
+		"is_active":       false,
+		"disabled_at":     time.Now(),
+		"disabled_reason": reason,
+	})
+	if result.RowsAffected > 0 {
+		log.Printf("🚫 Вебхук %s отключён: %s", hook.ID, reason)
+	}
+}
+
+// backoff - 30с, 1м, 2м ... 64м
+func backoff(attempts int) time.Duration {
+	return 30 * time.Second << (attempts - 1)
+}
+
+// Redeliver ставит копию доставки в очередь; история исходной сохраняется
+func Redeliver(db *gorm.DB, original models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	delivery := models.WebhookDelivery{
+		WebhookID:    original.WebhookID,
+		EventID:      original.EventID,
+		RedeliveryOf: &original.ID,
+		EventType:    original.EventType,
+		Payload:      original.Payload,
+		Status:       "pending",
+	}
+	if err := db.Create(&delivery).Error; err != nil {
+		return nil, err
+	}
+	Wake()
+	return &delivery, nil
+}
@@ -0,0 +1,288 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"refurnish/internal/models"
+	"refurnish/internal/testdb"
+
+	"gorm.io/gorm"
+)
+
+// receiver - получатель вебхуков: запоминает запросы и отвечает status
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []receivedRequest
+	server   *httptest.Server
+}
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newReceiver(t *testing.T) *receiver {
+	allowLoopback(t)
+	rec := &receiver{status: http.StatusOK}
+	rec.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rec.mu.Lock()
+		rec.requests = append(rec.requests, receivedRequest{header: r.Header.Clone(), body: body})
+		status := rec.status
+		rec.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(rec.server.Close)
+	return rec
+}
+
+func (rec *receiver) respond(status int) {
+	rec.mu.Lock()
+	rec.status = status
+	rec.mu.Unlock()
+}
+
+func (rec *receiver) received() []receivedRequest {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]receivedRequest(nil), rec.requests...)
+}
+
+func createHook(t *testing.T, db *gorm.DB, url string) models.Webhook {
+	t.Helper()
+	hook := models.Webhook{
+		UserID:     testdb.User(t, db, "client"),
+		URL:        url,
+		EventTypes: []string{"project.created"},
+		Secret:     "whsec_test",
+		IsActive:   true,
+	}
+	if err := db.Create(&hook).Error; err != nil {
+		t.Fatal(err)
+	}
+	return hook
+}
+
+func queueDelivery(t *testing.T, db *gorm.DB, hook models.Webhook) models.WebhookDelivery {
+	t.Helper()
+	delivery := models.WebhookDelivery{
+		WebhookID: hook.ID,
+		EventType: "project.created",
+		Payload:   models.JSONB(`{"type":"project.created","data":{"projectId":"p1"}}`),
+		Status:    "pending",
+	}
+	if err := db.Create(&delivery).Error; err != nil {
+		t.Fatal(err)
+	}
+	return delivery
+}
+
+func deliver(t *testing.T, db *gorm.DB, want int) {
+	t.Helper()
+	n, err := DeliverBatch(context.Background(), db)
+	if err != nil || n != want {
+		t.Fatalf("DeliverBatch = %d, %v; want %d", n, err, want)
+	}
+}
+
+func reloadDelivery(t *testing.T, db *gorm.DB, id string) models.WebhookDelivery {
+	t.Helper()
+	var d models.WebhookDelivery
+	if err := db.First(&d, "id = ?", id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func reloadHook(t *testing.T, db *gorm.DB, id string) models.Webhook {
+	t.Helper()
+	var h models.Webhook
+	if err := db.First(&h, "id = ?", id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+// makeDue переносит попытку доставки на "сейчас"
+func makeDue(db *gorm.DB, id string) {
+	db.Exec("UPDATE webhook_deliveries SET next_attempt_at = now() - interval '1 second' WHERE id = ?", id)
+}
+
+func TestDeliverBatchSignsRequest(t *testing.T) {
+	db := testdb.Open(t)
+	rec := newReceiver(t)
+	hook := createHook(t, db, rec.server.URL)
+	delivery := queueDelivery(t, db, hook)
+
+	deliver(t, db, 1)
+
+	got := rec.received()
+	if len(got) != 1 {
+		t.Fatalf("получено %d запросов, want 1", len(got))
+	}
+	req := got[0]
+	timestamp, _ := strconv.ParseInt(req.header.Get(HeaderTimestamp), 10, 64)
+	if !Verify(hook.Secret, timestamp, req.body, req.header.Get(HeaderSignature), time.Minute) {
+		t.Error("подпись запроса не проходит Verify")
+	}
+	if req.header.Get(HeaderDelivery) != delivery.ID || req.header.Get(HeaderEvent) != "project.created" {
+		t.Errorf("заголовки: %v", req.header)
+	}
+
+	d := reloadDelivery(t, db, delivery.ID)
+	if d.Status != "success" || d.Attempts != 1 || d.DeliveredAt == nil {
+		t.Errorf("доставка: status=%s attempts=%d", d.Status, d.Attempts)
+	}
+}
+
+func TestDeliverBatchRetriesWithBackoff(t *testing.T) {
+	db := testdb.Open(t)
+	rec := newReceiver(t)
+	rec.respond(http.StatusInternalServerError)
+	hook := createHook(t, db, rec.server.URL)
+	delivery := queueDelivery(t, db, hook)
+
+	deliver(t, db, 1)
+
+	d := reloadDelivery(t, db, delivery.ID)
+	if d.Status != "pending" || d.Attempts != 1 || d.ResponseStatus == nil || *d.ResponseStatus != 500 {
+		t.Fatalf("после ошибки: status=%s attempts=%d response=%v", d.Status, d.Attempts, d.ResponseStatus)
+	}
+	var wait float64
+	db.Raw("SELECT EXTRACT(EPOCH FROM next_attempt_at - now()) FROM webhook_deliveries WHERE id = ?", delivery.ID).Scan(&wait)
+	if wait < 20 || wait > 40 {
+		t.Errorf("следующая попытка через %.0fс, want ~30с", wait)
+	}
+	if h := reloadHook(t, db, hook.ID); h.ConsecutiveFailures != 1 {
+		t.Errorf("consecutive_failures = %d, want 1", h.ConsecutiveFailures)
+	}
+
+	// До наступления next_attempt_at повтора нет
+	deliver(t, db, 0)
+
+	rec.respond(http.StatusOK)
+	makeDue(db, delivery.ID)
+	deliver(t, db, 1)
+
+	if d := reloadDelivery(t, db, delivery.ID); d.Status != "success" || d.Attempts != 2 {
+		t.Errorf("после повтора: status=%s attempts=%d", d.Status, d.Attempts)
+	}
+	if h := reloadHook(t, db, hook.ID); h.ConsecutiveFailures != 0 {
+		t.Errorf("успех не сбросил consecutive_failures: %d", h.ConsecutiveFailures)
+	}
+}
+
+func TestDeliverBatchFailsAfterMaxAttempts(t *testing.T) {
+	db := testdb.Open(t)
+	rec := newReceiver(t)
+	rec.respond(http.StatusBadGateway)
+	hook := createHook(t, db, rec.server.URL)
+	delivery := queueDelivery(t, db, hook)
+	db.Exec("UPDATE webhook_deliveries SET attempts = ? WHERE id = ?", MaxAttempts-1, delivery.ID)
+
+	deliver(t, db, 1)
+
+	if d := reloadDelivery(t, db, delivery.ID); d.Status != "failed" || d.Attempts != MaxAttempts {
+		t.Errorf("status=%s attempts=%d, want failed/%d", d.Status, d.Attempts, MaxAttempts)
+	}
+}
+
+func TestDeliverBatchDisablesWebhook(t *testing.T) {
+	db := testdb.Open(t)
+	rec := newReceiver(t)
+	rec.respond(http.StatusServiceUnavailable)
+	hook := createHook(t, db, rec.server.URL)
+	db.Exec("UPDATE webhooks SET consecutive_failures = ? WHERE id = ?", DisableAfter-1, hook.ID)
+	first := queueDelivery(t, db, hook)
+
+	deliver(t, db, 1)
+
+	h := reloadHook(t, db, hook.ID)
+	if h.IsActive || h.DisabledAt == nil || h.DisabledReason == nil {
+		t.Fatalf("вебхук не отключён после %d неудач: %+v", DisableAfter, h)
+	}
+
+	// Отключённому вебхуку запросы больше не отправляются
+	second := queueDelivery(t, db, hook)
+	deliver(t, db, 1)
+
+	if got := len(rec.received()); got != 1 {
+		t.Errorf("получено %d запросов, want 1", got)
+	}
+	if d := reloadDelivery(t, db, second.ID); d.Status != "failed" {
+		t.Errorf("доставка на отключённый вебхук: status=%s", d.Status)
+	}
+	if d := reloadDelivery(t, db, first.ID); d.Status != "pending" {
+		t.Errorf("первая доставка: status=%s, want pending", d.Status)
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	db := testdb.Open(t)
+	rec := newReceiver(t)
+	hook := createHook(t, db, rec.server.URL)
+	original := queueDelivery(t, db, hook)
+	deliver(t, db, 1)
+	original = reloadDelivery(t, db, original.ID)
+
+	again, err := Redeliver(db, original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID == original.ID || again.RedeliveryOf == nil || *again.RedeliveryOf != original.ID {
+		t.Fatalf("копия доставки: %+v", again)
+	}
+
+	deliver(t, db, 1)
+
+	got := rec.received()
+	if len(got) != 2 {
+		t.Fatalf("получено %d запросов, want 2", len(got))
+	}
+	if string(got[0].body) != string(got[1].body) {
+		t.Errorf("повторная доставка с другим телом: %s / %s", got[0].body, got[1].body)
+	}
+	if got[1].header.Get(HeaderDelivery) != again.ID {
+		t.Errorf("%s = %s, want %s", HeaderDelivery, got[1].header.Get(HeaderDelivery), again.ID)
+	}
+	if d := reloadDelivery(t, db, original.ID); d.Status != "success" || d.Attempts != 1 {
+		t.Errorf("исходная доставка изменилась: status=%s attempts=%d", d.Status, d.Attempts)
+	}
+	if d := reloadDelivery(t, db, again.ID); d.Status != "success" {
+		t.Errorf("повторная доставка: status=%s", d.Status)
+	}
+}
+
+// Пачка доставляется параллельно и успевает до истечения lease,
+// даже если каждый получатель отвечает по requestTimeout
+func TestDeliverBatchWithinLease(t *testing.T) {
+	rounds := (batchSize + workers - 1) / workers
+	if worst := time.Duration(rounds) * requestTimeout; lease <= worst {
+		t.Fatalf("lease %v не покрывает худший случай %v", lease, worst)
+	}
+
+	db := testdb.Open(t)
+	allowLoopback(t)
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	t.Cleanup(slow.Close)
+	hook := createHook(t, db, slow.URL)
+	for i := 0; i < workers; i++ {
+		queueDelivery(t, db, hook)
+	}
+
+	start := time.Now()
+	deliver(t, db, workers)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("%d доставок по 200мс заняли %v - доставка не параллельная", workers, elapsed)
+	}
+}
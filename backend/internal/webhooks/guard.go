@@ -0,0 +1,100 @@
+// internal/webhooks/guard.go
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrBadURL         = errors.New("укажите корректный http(s) URL")
+	ErrUnresolvedHost = errors.New("не удалось найти адрес хоста")
+	ErrPrivateAddress = errors.New("URL указывает на внутренний адрес")
+)
+
+// allowPrivate отключает проверку адресов - только для тестов с httptest.Server
+var allowPrivate = false
+
+// Диапазоны, которых нет среди IsPrivate/IsLoopback/IsLinkLocal*
+var blockedNets = []*net.IPNet{
+	mustCIDR("0.0.0.0/8"),     // "этот" хост
+	mustCIDR("100.64.0.0/10"), // CGNAT
+	mustCIDR("192.0.0.0/24"),  // служебные IETF
+	mustCIDR("198.18.0.0/15"), // тестирование сетей
+}
+
+func mustCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// blockedIP - адрес внутренней сети, куда вебхуки ходить не должны:
+// loopback, частные сети, link-local (в т.ч. 169.254.169.254 облачных метаданных)
+func blockedIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, n := range blockedNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckURL проверяет URL вебхука при сохранении: http(s), хост
+// разрешается и ни один из его адресов не внутренний. DNS может
+// поменяться позже, поэтому адрес проверяется ещё раз при соединении.
+func CheckURL(ctx context.Context, raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Hostname() == "" {
+		return "", ErrBadURL
+	}
+	if allowPrivate {
+		return raw, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return "", ErrUnresolvedHost
+	}
+	for _, addr := range addrs {
+		if blockedIP(addr.IP) {
+			return "", ErrPrivateAddress
+		}
+	}
+	return raw, nil
+}
+
+// dialer проверяет уже разрешённый адрес непосредственно перед соединением,
+// так что подмена DNS после CheckURL не приведёт запрос во внутреннюю сеть
+var dialer = &net.Dialer{
+	Timeout: 5 * time.Second,
+	Control: func(network, address string, _ syscall.RawConn) error {
+		if allowPrivate {
+			return nil
+		}
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		ip := net.ParseIP(host)
+		if ip == nil || blockedIP(ip) {
+			return ErrPrivateAddress
+		}
+		return nil
+	},
+}
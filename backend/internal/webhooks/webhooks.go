@@ -0,0 +1,149 @@
+// internal/webhooks/webhooks.go
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"refurnish/internal/events"
+	"refurnish/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Заголовки запроса к получателю
+const (
+	HeaderEvent     = "X-Refurnish-Event"
+	HeaderDelivery  = "X-Refurnish-Delivery"
+	HeaderTimestamp = "X-Refurnish-Timestamp"
+	HeaderSignature = "X-Refurnish-Signature"
+)
+
+// EventTypes - события, на которые можно подписать вебхук
+var EventTypes = []string{
+	events.ProjectCreated,
+	events.ProjectPublished,
+	events.ProjectExpired,
+	events.ResponseCreated,
+	events.MasterAssigned,
+	events.ProjectCompleted,
+}
+
+func IsEventType(t string) bool {
+	for _, e := range EventTypes {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign - подпись тела: hex(HMAC-SHA256(secret, "<timestamp>.<body>")).
+// Получатель считает её так же и сравнивает с X-Refurnish-Signature
+// (после префикса "sha256="); метка времени защищает от повторов.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify - проверка подписи на стороне получателя; tolerance - допустимое
+// расхождение часов
+func Verify(secret string, timestamp int64, body []byte, signature string, tolerance time.Duration) bool {
+	if d := time.Since(time.Unix(timestamp, 0)); d > tolerance || d < -tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// OnEvent - подписчик доменных событий: ставит доставки вебхукам
+// владельцев проекта и назначенного мастера.
+func OnEvent(ctx context.Context, db *gorm.DB, e events.Event) error {
+	db = db.WithContext(ctx)
+
+	var owners []string
+	for _, key := range []string{"clientUserId", "masterUserId"} {
+		if id := e.String(key); id != "" {
+			owners = append(owners, id)
+		}
+	}
+	if len(owners) == 0 {
+		return nil
+	}
+
+	var hooks []models.Webhook
+	if err := db.Where("user_id IN ? AND is_active", owners).Find(&hooks).Error; err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"id":         e.ID,
+		"type":       e.Type,
+		"occurredAt": e.CreatedAt.UTC().Format(time.RFC3339),
+		"data":       publicData(e),
+	})
+	if err != nil {
+		return err
+	}
+
+	queued := 0
+	for _, hook := range hooks {
+		if !subscribed(hook, e.Type) {
+			continue
+		}
+		eventID := e.ID
+		delivery := models.WebhookDelivery{
+			WebhookID: hook.ID,
+			EventID:   &eventID,
+			EventType: e.Type,
+			Payload:   models.JSONB(body),
+			Status:    "pending",
+		}
+		// Повторная доставка события из outbox не создаёт дубль
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&delivery)
+		if result.Error != nil {
+			return result.Error
+		}
+		queued += int(result.RowsAffected)
+	}
+	if queued > 0 {
+		Wake()
+	}
+	return nil
+}
+
+func subscribed(hook models.Webhook, eventType string) bool {
+	for _, t := range hook.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// publicData - payload события без внутренних идентификаторов пользователей
+func publicData(e events.Event) map[string]interface{} {
+	data := map[string]interface{}{}
+	for k, v := range e.Payload {
+		if strings.HasSuffix(k, "UserId") {
+			continue
+		}
+		data[k] = v
+	}
+	switch e.Type {
+	case events.ResponseCreated:
+		data["responseId"] = e.AggregateID
+	default:
+		data["projectId"] = e.AggregateID
+	}
+	return data
+}
@@ -0,0 +1,115 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"type":"project.created"}`)
+	now := time.Now().Unix()
+	old := now - 600
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		signature string
+		want      bool
+	}{
+		{"верная подпись", "secret", now, body, Sign("secret", now, body), true},
+		{"другой секрет", "other", now, body, Sign("secret", now, body), false},
+		{"изменённое тело", "secret", now, []byte(`{"type":"project.completed"}`), Sign("secret", now, body), false},
+		{"подмена метки времени", "secret", now + 1, body, Sign("secret", now, body), false},
+		{"старая метка", "secret", old, body, Sign("secret", old, body), false},
+	}
+	for _, tt := range tests {
+		if got := Verify(tt.secret, tt.timestamp, tt.body, tt.signature, 5*time.Minute); got != tt.want {
+			t.Errorf("%s: Verify = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute}
+	for i, d := range want {
+		if got := backoff(i + 1); got != d {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, d)
+		}
+	}
+}
+
+func TestBlockedIP(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1":       true,
+		"10.1.2.3":        true,
+		"172.16.0.1":      true,
+		"192.168.1.1":     true,
+		"169.254.169.254": true,
+		"100.64.0.1":      true,
+		"0.0.0.0":         true,
+		"::1":             true,
+		"fe80::1":         true,
+		"fd00::1":         true,
+		"::ffff:10.0.0.1": true,
+		"93.184.216.34":   false,
+		"2606:4700::1111": false,
+	}
+	for addr, want := range tests {
+		if got := blockedIP(net.ParseIP(addr)); got != want {
+			t.Errorf("blockedIP(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want error
+	}{
+		{"https://93.184.216.34/hook", nil},
+		{"ftp://93.184.216.34/hook", ErrBadURL},
+		{"https:///hook", ErrBadURL},
+		{"http://127.0.0.1:8080/hook", ErrPrivateAddress},
+		{"http://localhost/hook", ErrPrivateAddress},
+		{"http://169.254.169.254/latest/meta-data", ErrPrivateAddress},
+		{"http://[::1]/hook", ErrPrivateAddress},
+		{"http://10.0.0.5/hook", ErrPrivateAddress},
+	}
+	for _, tt := range tests {
+		_, err := CheckURL(context.Background(), tt.url)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("CheckURL(%s) = %v, want %v", tt.url, err, tt.want)
+		}
+	}
+}
+
+// Даже если URL прошёл проверку, а DNS потом стал указывать внутрь,
+// соединение с внутренним адресом не устанавливается
+func TestClientRefusesPrivateAddress(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	resp, err := Client.Post(server.URL, "application/json", nil)
+	if err == nil {
+		resp.Body.Close()
+	}
+	if !errors.Is(err, ErrPrivateAddress) || called {
+		t.Fatalf("запрос на %s: err=%v, called=%v; want ErrPrivateAddress", server.URL, err, called)
+	}
+}
+
+// allowLoopback разрешает доставку на httptest.Server до конца теста
+func allowLoopback(t *testing.T) {
+	allowPrivate = true
+	t.Cleanup(func() { allowPrivate = false })
+}
@@ -16,6 +16,7 @@ import AvailableProjects from './pages/AvailableProjects';
 import MasterResponses from './pages/MasterResponses';
 import MasterProfile from './pages/MasterProfile.tsx';
 import AssignedProjects from './pages/AssignedProjects';
+import Unsubscribe from './pages/Unsubscribe';
 import {JSX} from "react";
 
 // Проверка авторизации
@@ -296,6 +297,9 @@ export default function App() {
                     </Layout>
                 } />
 
+                {/* Отписка от оповещений по ссылке из уведомления, без входа */}
+                <Route path="/unsubscribe" element={<Unsubscribe />} />
+
                 {/* Главная страница */}
                 <Route path="/" element={
                     <Layout>
@@ -78,7 +78,8 @@ export const projectApi = {
     // Получить отклики на проект (клиент)
     getProjectResponses: (id: string) => api.get(`/client/project/${id}/responses`),
 
-    // Назначить мастера (клиент)
-    assignMaster: (projectId: string, masterId: string) =>
-        api.post(`/client/project/${projectId}/assign`, { masterId })
+    // Назначить мастера (клиент). Если мастер может быть занят, сервер
+    // отвечает 409 с warnings - повторите запрос с confirm: true
+    assignMaster: (projectId: string, masterId: string, confirm = false) =>
+        api.post(`/client/project/${projectId}/assign`, { masterId, confirm })
 };
\ No newline at end of file
@@ -0,0 +1,141 @@
+// src/components/RecommendedMasters.tsx
+import { useState, useEffect } from 'react';
+import { api } from '../api';
+import { Sparkles, Send, CheckCircle, MapPin, Star } from 'lucide-react';
+
+interface Factor {
+    name: string;
+    score: number;
+    weight: number;
+    contribution: number;
+    explanation: string;
+}
+
+interface RecommendedMaster {
+    masterId: string;
+    name: string;
+    city: string;
+    priceFrom: number;
+    rating: number;
+    score: number;
+    breakdown: Factor[];
+    invited: boolean;
+    responded: boolean;
+}
+
+const factorLabels: Record<string, string> = {
+    specialization: 'Специализация',
+    rating: 'Рейтинг',
+    distance: 'Расстояние',
+    price: 'Цена',
+    responseRate: 'Отзывчивость',
+    availability: 'Загрузка',
+};
+
+export default function RecommendedMasters({ projectId }: { projectId: string }) {
+    const [masters, setMasters] = useState<RecommendedMaster[]>([]);
+    const [loading, setLoading] = useState(true);
+    const [inviting, setInviting] = useState<string | null>(null);
+
+    useEffect(() => {
+        fetchRecommendations();
+    }, [projectId]);
+
+    const fetchRecommendations = async () => {
+        try {
+            setLoading(true);
+            const res = await api.get(`/client/project/${projectId}/recommended-masters`);
+            setMasters(Array.isArray(res.data) ? res.data : []);
+        } catch (err) {
+            console.error('Не удалось загрузить рекомендации:', err);
+            setMasters([]);
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    const handleInvite = async (masterId: string) => {
+        try {
+            setInviting(masterId);
+            await api.post(`/client/project/${projectId}/invitations`, { masterIds: [masterId] });
+            setMasters(prev => prev.map(m => (m.masterId === masterId ? { ...m, invited: true } : m)));
+        } catch (error: any) {
+            alert(error.response?.data || 'Не удалось отправить приглашение');
+        } finally {
+            setInviting(null);
+        }
+    };
+
+    if (loading || masters.length === 0) {
+        return null;
+    }
+
+    return (
+        <div className="bg-white rounded-2xl shadow-lg border border-gray-100 p-6 mt-8">
+            <h2 className="text-xl font-bold text-gray-800 mb-4 flex items-center">
+                <Sparkles className="w-5 h-5 text-yellow-500 mr-2" />
+                Рекомендованные мастера
+            </h2>
+            <div className="space-y-4">
+                {masters.map(master => (
+                    <div key={master.masterId} className="border border-gray-100 rounded-xl p-4">
+                        <div className="flex items-start justify-between gap-4">
+                            <div>
+                                <div className="font-semibold text-gray-800">{master.name || 'Мастер'}</div>
+                                <div className="flex items-center text-sm text-gray-500 mt-1 space-x-4">
+                                    {master.city && (
+                                        <span className="flex items-center">
+                                            <MapPin className="w-4 h-4 mr-1" />
+                                            {master.city}
+                                        </span>
+                                    )}
+                                    <span className="flex items-center">
+                                        <Star className="w-4 h-4 mr-1" />
+                                        {master.rating.toFixed(1)}
+                                    </span>
+                                    {master.priceFrom > 0 && <span>от {master.priceFrom.toLocaleString('ru-RU')} ₽</span>}
+                                </div>
+                            </div>
+                            <div className="text-right">
+                                <div className="text-2xl font-bold text-blue-600">{Math.round(master.score * 100)}</div>
+                                <div className="text-xs text-gray-500">совпадение</div>
+                            </div>
+                        </div>
+
+                        <div className="grid grid-cols-2 md:grid-cols-3 gap-2 mt-3">
+                            {master.breakdown.map(f => (
+                                <div key={f.name} className="text-xs bg-gray-50 rounded-lg px-3 py-2" title={f.explanation}>
+                                    <div className="font-medium text-gray-700">
+                                        {factorLabels[f.name] || f.name}: {Math.round(f.score * 100)}%
+                                    </div>
+                                    <div className="text-gray-500 truncate">{f.explanation}</div>
+                                </div>
+                            ))}
+                        </div>
+
+                        <div className="mt-3">
+                            {master.responded ? (
+                                <span className="inline-flex items-center text-sm text-green-600">
+                                    <CheckCircle className="w-4 h-4 mr-1" /> Уже откликнулся
+                                </span>
+                            ) : master.invited ? (
+                                <span className="inline-flex items-center text-sm text-gray-500">
+                                    <CheckCircle className="w-4 h-4 mr-1" /> Приглашение отправлено
+                                </span>
+                            ) : (
+                                <button
+                                    onClick={() => handleInvite(master.masterId)}
+                                    disabled={inviting === master.masterId}
+                                    className="inline-flex items-center px-4 py-2 bg-blue-600 text-white rounded-lg text-sm font-medium hover:bg-blue-700 disabled:opacity-50"
+                                >
+                                    <Send className="w-4 h-4 mr-2" />
+                                    Пригласить
+                                </button>
+                            )}
+                        </div>
+                    </div>
+                ))}
+            </div>
+        </div>
+    );
+}
@@ -12,8 +12,7 @@ export default function EditProject() {
         furnitureType: '',
         budget: '',
         deadline: '',
-        city: 'Москва',
-        status: 'published'
+        city: 'Москва'
     });
     const [loading, setLoading] = useState(true);
 
@@ -38,8 +37,7 @@ export default function EditProject() {
                 furnitureType: project.furnitureType,
                 budget: project.budget.toString(),
                 deadline: formattedDeadline,
-                city: project.city,
-                status: project.status
+                city: project.city
             });
         } catch (error: any) {
             alert('Ошибка загрузки проекта: ' + (error.response?.data?.message || 'Проект не найден'));
@@ -80,13 +78,6 @@ export default function EditProject() {
     }
 
     const cities = ['Москва', 'Санкт-Петербург', 'Новосибирск', 'Екатеринбург', 'Казань'];
-    const statuses = [
-        { value: 'published', label: 'Опубликован' },
-        { value: 'assigned', label: 'Назначен мастер' },
-        { value: 'in_progress', label: 'В работе' },
-        { value: 'completed', label: 'Завершен' },
-        { value: 'cancelled', label: 'Отменен' }
-    ];
 
     return (
         <div className="max-w-2xl mx-auto">
@@ -153,22 +144,6 @@ export default function EditProject() {
                             required
                         />
                     </div>
-
-                    <div>
-                        <label className="block text-gray-700 mb-2">Статус</label>
-                        <select
-                            name="status"
-                            className="w-full px-4 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-blue-500 focus:border-blue-500 outline-none"
-                            value={form.status}
-                            onChange={handleChange}
-                        >
-                            {statuses.map(status => (
-                                <option key={status.value} value={status.value}>
-                                    {status.label}
-                                </option>
-                            ))}
-                        </select>
-                    </div>
                 </div>
 
                 <div>
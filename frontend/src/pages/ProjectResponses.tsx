@@ -1,7 +1,8 @@
 // src/pages/ProjectResponses.tsx
 import { useState, useEffect } from 'react';
 import { useParams, useNavigate } from 'react-router-dom';
-import { api } from '../api';
+import { api, projectApi } from '../api';
+import RecommendedMasters from '../components/RecommendedMasters';
 import {
     User,
     Phone,
@@ -101,10 +102,23 @@ export default function ProjectResponses() {
         }
 
         try {
-            const res = await api.post(`/client/project/${id}/assign`, {
-                masterId,
-                projectId: id
-            });
+            let res;
+            try {
+                res = await projectApi.assignMaster(id!, masterId);
+            } catch (error: any) {
+                // Мастер может быть занят в эти даты - назначаем только после подтверждения
+                const data = error.response?.data;
+                if (error.response?.status !== 409 || !data?.requiresConfirmation) {
+                    throw error;
+                }
+                const warnings = (data.warnings || [])
+                    .map((w: { message: string }) => `• ${w.message}`)
+                    .join('\n');
+                if (!window.confirm(`${data.error}:\n${warnings}\n\nВсё равно назначить мастера?`)) {
+                    return;
+                }
+                res = await projectApi.assignMaster(id!, masterId, true);
+            }
 
             if (res.data && res.data.success) {
                 alert(`✅ Мастер ${masterName || ''} успешно назначен на проект!`);
@@ -437,6 +451,8 @@ export default function ProjectResponses() {
                     </div>
                 </div>
             )}
+
+            {id && <RecommendedMasters projectId={id} />}
         </div>
     );
 }
\ No newline at end of file
@@ -0,0 +1,100 @@
+// src/pages/Unsubscribe.tsx
+// Отписка от сохранённого поиска по ссылке из уведомления. Ссылка только
+// открывает эту страницу - отписка происходит по кнопке (POST), чтобы её
+// не запускали сканеры ссылок в почте.
+import { useState, useEffect } from 'react';
+import { useSearchParams } from 'react-router-dom';
+import { api } from '../api';
+import { BellOff, CheckCircle, AlertCircle } from 'lucide-react';
+
+type State = 'loading' | 'confirm' | 'done' | 'error';
+
+export default function Unsubscribe() {
+    const [params] = useSearchParams();
+    const token = params.get('token') || '';
+    const [state, setState] = useState<State>('loading');
+    const [searchName, setSearchName] = useState('');
+    const [error, setError] = useState('');
+    const [submitting, setSubmitting] = useState(false);
+
+    useEffect(() => {
+        if (!token) {
+            setError('В ссылке нет токена отписки');
+            setState('error');
+            return;
+        }
+        api.get('/searches/unsubscribe', { params: { token } })
+            .then((res) => {
+                setSearchName(res.data.name);
+                setState(res.data.isActive ? 'confirm' : 'done');
+            })
+            .catch(() => {
+                setError('Подписка не найдена. Возможно, поиск уже удалён.');
+                setState('error');
+            });
+    }, [token]);
+
+    const handleUnsubscribe = async () => {
+        setSubmitting(true);
+        try {
+            await api.post('/searches/unsubscribe', { token });
+            setState('done');
+        } catch {
+            setError('Не удалось отписаться. Попробуйте позже.');
+            setState('error');
+        } finally {
+            setSubmitting(false);
+        }
+    };
+
+    return (
+        <div className="min-h-screen flex items-center justify-center bg-gradient-to-br from-gray-50 to-blue-50 p-4">
+            <div className="bg-white rounded-2xl shadow-xl p-8 max-w-md w-full text-center">
+                {state === 'loading' && (
+                    <div className="w-8 h-8 border-4 border-blue-200 border-t-blue-600 rounded-full animate-spin mx-auto"></div>
+                )}
+
+                {state === 'confirm' && (
+                    <>
+                        <div className="w-20 h-20 bg-gradient-to-r from-blue-500 to-purple-600 rounded-full flex items-center justify-center mx-auto mb-6">
+                            <BellOff className="w-10 h-10 text-white" />
+                        </div>
+                        <h2 className="text-2xl font-bold text-gray-800 mb-3">Отписаться от оповещений?</h2>
+                        <p className="text-gray-600 mb-6">
+                            Вы перестанете получать новые проекты по поиску «{searchName}»
+                        </p>
+                        <button
+                            onClick={handleUnsubscribe}
+                            disabled={submitting}
+                            className="w-full px-6 py-3 bg-gradient-to-r from-blue-500 to-purple-600 text-white rounded-xl font-semibold hover:from-blue-600 hover:to-purple-700 transition-all disabled:opacity-50"
+                        >
+                            {submitting ? 'Отписываем...' : 'Отписаться'}
+                        </button>
+                    </>
+                )}
+
+                {state === 'done' && (
+                    <>
+                        <div className="w-20 h-20 bg-gradient-to-r from-green-500 to-emerald-600 rounded-full flex items-center justify-center mx-auto mb-6">
+                            <CheckCircle className="w-10 h-10 text-white" />
+                        </div>
+                        <h2 className="text-2xl font-bold text-gray-800 mb-3">Вы отписались</h2>
+                        <p className="text-gray-600">
+                            Оповещения по поиску «{searchName}» больше не придут
+                        </p>
+                    </>
+                )}
+
+                {state === 'error' && (
+                    <>
+                        <div className="w-20 h-20 bg-gradient-to-r from-yellow-400 to-orange-500 rounded-full flex items-center justify-center mx-auto mb-6">
+                            <AlertCircle className="w-10 h-10 text-white" />
+                        </div>
+                        <h2 className="text-2xl font-bold text-gray-800 mb-3">Не получилось</h2>
+                        <p className="text-gray-600">{error}</p>
+                    </>
+                )}
+            </div>
+        </div>
+    );
+}